@@ -1,17 +1,23 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
 	"io"
-	"net/mail"
 	"os"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/x/term"
 	"github.com/evolution-cms/installer/internal/domain"
 	installengine "github.com/evolution-cms/installer/internal/engine/install"
+	"github.com/evolution-cms/installer/internal/i18n"
 	"github.com/evolution-cms/installer/internal/logging"
+	"github.com/evolution-cms/installer/internal/ui"
 )
 
 func applyCLIDefaults(opt *installengine.Options) error {
@@ -58,23 +64,102 @@ func applyCLIDefaults(opt *installengine.Options) error {
 	if adminEmail == "" {
 		return errors.New("CLI mode requires --admin-email")
 	}
-	if _, err := mail.ParseAddress(adminEmail); err != nil {
-		return fmt.Errorf("CLI mode requires a valid --admin-email: %v", err)
-	}
 	opt.AdminEmail = adminEmail
 
 	adminPassword := strings.TrimSpace(opt.AdminPassword)
 	if adminPassword == "" {
 		return errors.New("CLI mode requires --admin-password")
 	}
-	if len([]rune(adminPassword)) < 6 {
-		return errors.New("--admin-password must be at least 6 characters long")
-	}
 	opt.AdminPassword = adminPassword
 
+	return validateInstallFlags(*opt)
+}
+
+// validateInstallFlags runs answer flags through the same checks the installer
+// applies to typed answers. Empty values are left to the interactive questions.
+func validateInstallFlags(opt installengine.Options) error {
+	type check struct {
+		flag  string
+		value string
+		rule  domain.ValidationRule
+	}
+	checks := []check{
+		{"--admin-username", opt.AdminUsername, domain.ValidateUsername},
+		{"--admin-email", opt.AdminEmail, domain.ValidateEmail},
+		{"--admin-password", opt.AdminPassword, domain.ValidatePassword},
+		{"--admin-directory", opt.AdminDirectory, domain.ValidateAdminDir},
+	}
+	if strings.ToLower(strings.TrimSpace(opt.DBType)) != "sqlite" {
+		checks = append(checks,
+			check{"--db-name", opt.DBName, domain.ValidateDBIdentifier},
+			check{"--db-user", opt.DBUser, domain.ValidateDBUser},
+		)
+	}
+	for _, c := range checks {
+		if err := domain.ValidateAnswer(c.value, c.rule); err != nil {
+			return fmt.Errorf("invalid %s: %v", c.flag, err)
+		}
+	}
 	return nil
 }
 
+// validateDBConnectionFlags checks --db-port, --db-socket, --db-ssl-mode, the
+// certificate files, --db-collation, --db-table-prefix, --db-existing and
+// --db-import. They have no regular interactive question, so every mode runs
+// it.
+func validateDBConnectionFlags(opt installengine.Options) error {
+	if opt.DBPort != 0 {
+		if err := domain.ValidateAnswer(strconv.Itoa(opt.DBPort), domain.ValidatePort); err != nil {
+			return fmt.Errorf("invalid --db-port: %v", err)
+		}
+	}
+	if mode := strings.ToLower(strings.TrimSpace(opt.DBSSLMode)); mode != "" && !slices.Contains(installengine.SSLModes, mode) {
+		return fmt.Errorf("invalid --db-ssl-mode: must be one of %s (got %q)", strings.Join(installengine.SSLModes, ", "), opt.DBSSLMode)
+	}
+	if c := strings.TrimSpace(opt.DBCollation); c != "" && !collationRe.MatchString(c) {
+		return fmt.Errorf("invalid --db-collation: expected a MySQL collation such as utf8mb4_unicode_ci (got %q)", c)
+	}
+	if err := domain.ValidateAnswer(opt.DBTablePrefix, domain.ValidateTablePrefix); err != nil {
+		return fmt.Errorf("invalid --db-table-prefix: %v", err)
+	}
+	if v := strings.ToLower(strings.TrimSpace(opt.DBExisting)); v != "" && !slices.Contains(installengine.ExistingTablesActions, v) {
+		return fmt.Errorf("invalid --db-existing: must be one of %s (got %q)", strings.Join(installengine.ExistingTablesActions, ", "), opt.DBExisting)
+	}
+	if path := strings.TrimSpace(opt.DBImport); path != "" {
+		lower := strings.ToLower(path)
+		if !strings.HasSuffix(lower, ".sql") && !strings.HasSuffix(lower, ".sql.gz") {
+			return fmt.Errorf("invalid --db-import: expected a .sql or .sql.gz file (got %q)", path)
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("invalid --db-import: %v", err)
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Errorf("invalid --db-import: %s is not a file", path)
+		}
+	}
+	if (strings.TrimSpace(opt.DBSSLCert) == "") != (strings.TrimSpace(opt.DBSSLKey) == "") {
+		return errors.New("--db-ssl-cert and --db-ssl-key must be given together")
+	}
+	for _, f := range []struct{ flag, path string }{
+		{"--db-socket", opt.DBSocket},
+		{"--db-ssl-ca", opt.DBSSLCA},
+		{"--db-ssl-cert", opt.DBSSLCert},
+		{"--db-ssl-key", opt.DBSSLKey},
+	} {
+		path := strings.TrimSpace(f.path)
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			return fmt.Errorf("invalid %s: %v", f.flag, err)
+		}
+	}
+	return nil
+}
+
+var collationRe = regexp.MustCompile(`^[A-Za-z0-9]+_[A-Za-z0-9_]+$`)
+
 func isAllowedDBType(dbType string) bool {
 	switch dbType {
 	case "mysql", "pgsql", "sqlite", "sqlsrv":
@@ -84,11 +169,17 @@ func isAllowedDBType(dbType string) bool {
 	}
 }
 
-func runCLI(ctx context.Context, events <-chan domain.Event, actions chan<- domain.Action, cancel func(), logger *logging.EventLogger, quiet bool) ([]string, error) {
-	fmt.Fprintln(os.Stdout, "Running installer in CLI mode (no TUI).")
+// runCLI prints events as lines. With a prompter (--plain) questions are asked
+// on stdin; without one (--cli) a missing answer stops the run.
+func runCLI(ctx context.Context, events <-chan domain.Event, actions chan<- domain.Action, cancel func(), logger *logging.EventLogger, minLevel domain.Severity, prompter *plainPrompter, termStatus *ui.TerminalStatus) ([]string, error) {
+	if prompter != nil {
+		fmt.Fprintln(os.Stdout, i18n.T("cli.plain_mode", nil))
+	} else {
+		fmt.Fprintln(os.Stdout, i18n.T("cli.mode", nil))
+	}
 
 	stepLabels := map[string]string{}
-	state := &cliState{lastLogByStep: map[string]string{}}
+	state := &cliState{lastLogByStep: map[string]string{}, plain: prompter}
 	var postExec []string
 	var hadError bool
 
@@ -100,6 +191,7 @@ func runCLI(ctx context.Context, events <-chan domain.Event, actions chan<- doma
 		case ev, ok := <-events:
 			if !ok {
 				finishCLIInlineOutput(state)
+				printCLITimingTable(os.Stdout, &state.timings)
 				if hadError {
 					return postExec, errors.New("installation failed")
 				}
@@ -108,7 +200,8 @@ func runCLI(ctx context.Context, events <-chan domain.Event, actions chan<- doma
 			if logger != nil {
 				logger.Record(ev)
 			}
-			if applyCLIEvent(ev, stepLabels, actions, cancel, &hadError, quiet, state) {
+			termStatus.Observe(ev)
+			if applyCLIEvent(ev, stepLabels, actions, cancel, &hadError, minLevel, state) {
 				continue
 			}
 			if ev.Type == domain.EventExecRequest {
@@ -116,24 +209,35 @@ func runCLI(ctx context.Context, events <-chan domain.Event, actions chan<- doma
 					postExec = append([]string(nil), p.Command...)
 				}
 			}
+			if p, ok := ev.Payload.(domain.SummaryPayload); ok {
+				finishCLIInlineOutput(state)
+				var logPaths []string
+				if logger != nil {
+					logPaths = logger.PlannedPaths()
+				}
+				printCLISummary(os.Stdout, p.Summary, logPaths)
+			}
 		}
 	}
 }
 
-func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<- domain.Action, cancel func(), hadError *bool, quiet bool, state *cliState) bool {
+func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<- domain.Action, cancel func(), hadError *bool, minLevel domain.Severity, state *cliState) bool {
+	if state != nil {
+		state.timings.Observe(ev)
+	}
 	switch ev.Type {
 	case domain.EventSteps:
 		switch p := ev.Payload.(type) {
 		case domain.StepsPayload:
 			for _, s := range p.Steps {
 				if s.ID != "" && s.Label != "" {
-					stepLabels[s.ID] = s.Label
+					stepLabels[s.ID] = i18n.Step(s)
 				}
 			}
 		case []domain.StepState:
 			for _, s := range p {
 				if s.ID != "" && s.Label != "" {
-					stepLabels[s.ID] = s.Label
+					stepLabels[s.ID] = i18n.Step(s)
 				}
 			}
 		}
@@ -146,6 +250,11 @@ func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<-
 		if p, okPayload := ev.Payload.(domain.StepDonePayload); okPayload {
 			ok = p.OK
 		}
+		if state != nil {
+			if t, found := state.timings.Step(ev.StepID); found && t.Duration() > 0 {
+				label += " (" + domain.FormatDuration(t.Duration()) + ")"
+			}
+		}
 		if !ok {
 			*hadError = true
 			printCLILine("✗", ev.StepID, label, os.Stderr, state)
@@ -153,7 +262,7 @@ func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<-
 			printCLILine("✓", ev.StepID, label, os.Stdout, state)
 		}
 	case domain.EventProgress:
-		if quiet {
+		if !domain.EventSeverity(ev).Enabled(minLevel) {
 			return false
 		}
 		if p, ok := ev.Payload.(domain.ProgressPayload); ok {
@@ -162,23 +271,31 @@ func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<-
 				unit = "units"
 			}
 			msg := fmt.Sprintf("Progress: %d/%d %s", p.Current, p.Total, unit)
-			printCLIInlineLine("•", ev.StepID, msg, os.Stdout, state)
+			pct := -1
+			if p.Total > 0 {
+				pct = int(p.Current * 100 / p.Total)
+			}
+			printCLIProgressLine("•", ev.StepID, msg, pct, os.Stdout, state)
 		}
 	case domain.EventLog:
 		switch payload := ev.Payload.(type) {
 		case domain.QuestionPayload:
-			return handleCLIQuestion(payload.Question, actions, cancel, hadError)
+			q := i18n.Question(payload.Question)
+			if state != nil && state.plain != nil {
+				return handlePlainQuestion(state.plain, q, actions, cancel, hadError)
+			}
+			return handleCLIQuestion(q, actions, cancel, hadError)
 		case domain.LogPayload:
 			msg := formatCLILogMessage(payload)
 			if msg != "" {
-				if shouldSkipCLILog(payload.Fields, ev.StepID, msg, state) {
+				if !domain.EventSeverity(ev).Enabled(minLevel) {
 					return false
 				}
-				if quiet && !shouldPrintQuiet(msg) {
+				if shouldSkipCLILog(payload.Fields, ev.StepID, msg, state) {
 					return false
 				}
 				if isInlineCLIProgress(payload.Fields) {
-					printCLIInlineLine("-", ev.StepID, msg, os.Stdout, state)
+					printCLIProgressLine("-", ev.StepID, msg, inlineCLIProgressPct(payload.Fields), os.Stdout, state)
 				} else {
 					printCLILine("-", ev.StepID, msg, os.Stdout, state)
 				}
@@ -188,14 +305,14 @@ func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<-
 		if p, ok := ev.Payload.(domain.LogPayload); ok {
 			msg := formatCLILogMessage(p)
 			if msg != "" {
-				if shouldSkipCLILog(p.Fields, ev.StepID, msg, state) {
+				if !domain.EventSeverity(ev).Enabled(minLevel) {
 					return false
 				}
-				if quiet && !shouldPrintQuiet(msg) {
+				if shouldSkipCLILog(p.Fields, ev.StepID, msg, state) {
 					return false
 				}
 				if isInlineCLIProgress(p.Fields) {
-					printCLIInlineLine("!", ev.StepID, msg, os.Stderr, state)
+					printCLIProgressLine("!", ev.StepID, msg, inlineCLIProgressPct(p.Fields), os.Stderr, state)
 				} else {
 					printCLILine("!", ev.StepID, msg, os.Stderr, state)
 				}
@@ -211,6 +328,9 @@ func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<-
 		}
 	case domain.EventExtras:
 		if p, ok := ev.Payload.(domain.ExtrasState); ok && p.Stage == domain.ExtrasStageSelect {
+			if state != nil && state.plain != nil {
+				return handlePlainExtras(state.plain, p, actions, cancel, hadError)
+			}
 			required := requiredExtrasSelections(p.Selections)
 			if len(required) > 0 {
 				sendAction(actions, domain.Action{
@@ -234,6 +354,51 @@ func applyCLIEvent(ev domain.Event, stepLabels map[string]string, actions chan<-
 	return false
 }
 
+// printCLISummary prints the final installation summary. The admin password
+// is never printed.
+func printCLISummary(out io.Writer, s domain.InstallSummary, logPaths []string) {
+	details := i18n.Details(s.Details)
+	if len(logPaths) > 0 {
+		details = append(details, domain.QuestionDetail{Label: i18n.T("field.log", nil), Value: strings.Join(logPaths, ", ")})
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, i18n.T("summary.title", nil))
+	printCLIDetails(out, details)
+	if len(s.Extras) > 0 {
+		fmt.Fprintln(out, i18n.T("summary.extras", nil)+":")
+		for _, r := range s.Extras {
+			mark := "✓"
+			if r.Status == domain.ExtrasStatusError {
+				mark = "✗"
+			}
+			line := "  " + mark + " " + r.Name
+			if r.Status == domain.ExtrasStatusError && r.Message != "" {
+				line += " - " + r.Message
+			}
+			fmt.Fprintln(out, logging.Redact(line))
+		}
+	}
+	if len(s.NextSteps) > 0 {
+		fmt.Fprintln(out, i18n.T("summary.next_steps", nil)+":")
+		for i, step := range s.NextSteps {
+			fmt.Fprintf(out, "  %d. %s\n", i+1, i18n.Log(step))
+		}
+	}
+}
+
+// printCLITimingTable prints the per-step timing summary after the run.
+func printCLITimingTable(out io.Writer, timings *domain.Timings) {
+	table := timings.Table()
+	if len(table) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Timing:")
+	for _, line := range table {
+		fmt.Fprintln(out, "  "+line)
+	}
+}
+
 func requiredExtrasSelections(selections []domain.ExtrasSelection) []domain.ExtrasSelection {
 	if len(selections) == 0 {
 		return nil
@@ -257,6 +422,35 @@ func handleCLIQuestion(q domain.QuestionState, actions chan<- domain.Action, can
 		})
 		fmt.Fprintln(os.Stdout, "Installer update available; skipping in --cli mode.")
 		return true
+	case "review_install":
+		choice := confirmCLIReview(q, os.Stdin, os.Stdout, os.Stderr, term.IsTerminal(os.Stdin.Fd()))
+		sendAction(actions, domain.Action{
+			Type:       domain.ActionAnswerSelect,
+			QuestionID: q.ID,
+			OptionID:   choice,
+		})
+		if choice != "confirm" {
+			*hadError = true
+		}
+		return true
+	case "db_create":
+		sendAction(actions, domain.Action{
+			Type:       domain.ActionAnswerSelect,
+			QuestionID: q.ID,
+			OptionID:   "exit",
+		})
+		*hadError = true
+		fmt.Fprintln(os.Stderr, "The database does not exist; pass --db-create to create it.")
+		return true
+	case "db_existing":
+		sendAction(actions, domain.Action{
+			Type:       domain.ActionAnswerSelect,
+			QuestionID: q.ID,
+			OptionID:   "abort",
+		})
+		*hadError = true
+		fmt.Fprintln(os.Stderr, "The database already has tables with this prefix; pass --db-table-prefix for another prefix or --db-existing=drop|keep.")
+		return true
 	case "db_retry":
 		sendAction(actions, domain.Action{
 			Type:       domain.ActionAnswerSelect,
@@ -276,6 +470,38 @@ func handleCLIQuestion(q domain.QuestionState, actions chan<- domain.Action, can
 	}
 }
 
+// confirmCLIReview prints the review screen and asks for confirmation on an
+// interactive terminal. Without one (CI, pipes) the settings are accepted as
+// before the review screen existed.
+func confirmCLIReview(q domain.QuestionState, in io.Reader, out io.Writer, errOut io.Writer, interactive bool) string {
+	fmt.Fprintln(out, i18n.T("cli.review_settings", nil))
+	printCLIDetails(out, q.Details)
+
+	if !interactive {
+		return "confirm"
+	}
+	fmt.Fprint(out, i18n.T("cli.review_confirm", nil))
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return "confirm"
+	}
+	fmt.Fprintln(errOut, "To change a setting, rerun with the corresponding flag.")
+	return "cancel"
+}
+
+// printCLIDetails prints question detail rows with aligned values.
+func printCLIDetails(out io.Writer, details []domain.QuestionDetail) {
+	labelW := 0
+	for _, d := range details {
+		labelW = max(labelW, len([]rune(d.Label)))
+	}
+	for _, d := range details {
+		pad := strings.Repeat(" ", labelW-len([]rune(d.Label)))
+		fmt.Fprintf(out, "  %s:%s %s\n", d.Label, pad, logging.Redact(d.Value))
+	}
+}
+
 func cliMissingInputMessage(q domain.QuestionState) string {
 	flag := ""
 	switch q.ID {
@@ -289,6 +515,14 @@ func cliMissingInputMessage(q domain.QuestionState) string {
 		flag = "--db-user"
 	case "db_password":
 		flag = "--db-password"
+	case "db_url":
+		flag = "--db-url"
+	case "db_create":
+		flag = "--db-create"
+	case "db_collation":
+		flag = "--db-collation"
+	case "db_table_prefix":
+		flag = "--db-table-prefix"
 	case "admin_username":
 		flag = "--admin-username"
 	case "admin_email":
@@ -325,6 +559,16 @@ func printCLILine(prefix, stepID, message string, out io.Writer, state *cliState
 	fmt.Fprintln(out, line)
 }
 
+// printCLIProgressLine redraws a progress line in place, or prints it as a
+// throttled regular line in --plain mode.
+func printCLIProgressLine(prefix, stepID, message string, pct int, out io.Writer, state *cliState) {
+	if state != nil && state.plain != nil {
+		state.plain.printProgressLine(prefix, stepID, message, pct, out, state)
+		return
+	}
+	printCLIInlineLine(prefix, stepID, message, out, state)
+}
+
 func printCLIInlineLine(prefix, stepID, message string, out io.Writer, state *cliState) {
 	line := formatCLILine(prefix, stepID, message)
 	if line == "" {
@@ -367,7 +611,7 @@ func finishCLIInlineOutput(state *cliState) {
 
 func formatCLILine(prefix, stepID, message string) string {
 	stepID = strings.TrimSpace(stepID)
-	message = strings.TrimSpace(message)
+	message = strings.TrimSpace(logging.Redact(message))
 	if message == "" {
 		return ""
 	}
@@ -391,6 +635,9 @@ func cliStreamKey(out io.Writer) string {
 type cliState struct {
 	lastLogByStep     map[string]string
 	inlineLenByStream map[string]int
+	timings           domain.Timings
+	// plain asks questions on stdin and never redraws lines (--plain).
+	plain *plainPrompter
 }
 
 func shouldSkipCLILog(fields map[string]string, stepID string, message string, state *cliState) bool {
@@ -417,8 +664,17 @@ func isInlineCLIProgress(fields map[string]string) bool {
 	return fields != nil && fields["kind"] == "inline_progress"
 }
 
+// inlineCLIProgressPct returns the percentage of an inline progress log, or -1.
+func inlineCLIProgressPct(fields map[string]string) int {
+	pct, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(fields["pct"]), "%"))
+	if err != nil {
+		return -1
+	}
+	return pct
+}
+
 func formatCLILogMessage(p domain.LogPayload) string {
-	msg := strings.TrimSpace(p.Message)
+	msg := strings.TrimSpace(i18n.Log(p))
 	if p.Fields != nil && p.Fields["kind"] == "inline_progress" {
 		label := strings.TrimSpace(p.Fields["label"])
 		if label == "" {
@@ -438,64 +694,9 @@ func formatCLILogMessage(p domain.LogPayload) string {
 	return msg
 }
 
-func shouldPrintQuiet(message string) bool {
-	msg := strings.TrimSpace(message)
-	if msg == "" {
-		return false
-	}
-	if isIssueMessage(msg) {
-		return true
-	}
-	if isVerboseMessage(msg) {
-		return false
-	}
-	return true
-}
-
-func isVerboseMessage(message string) bool {
-	for _, prefix := range []string{
-		"- Downloading ",
-		"- Installing ",
-		"- Syncing ",
-		"- Cloning ",
-	} {
-		if strings.HasPrefix(message, prefix) {
-			return true
-		}
-	}
-	switch {
-	case strings.HasPrefix(message, "Package operations:"):
-		return true
-	case strings.HasPrefix(message, "Verifying lock file contents can be installed on current platform."):
-		return true
-	case strings.HasPrefix(message, "Generating optimized autoload files"):
-		return true
-	case strings.HasPrefix(message, "Extracting"):
-		return true
-	case strings.HasPrefix(message, "Created project in "):
-		return true
-	case strings.HasPrefix(message, "Use the `composer fund` command"):
-		return true
-	case strings.Contains(message, "packages you are using are looking for funding"):
-		return true
-	}
-	return false
-}
-
-func isIssueMessage(message string) bool {
-	lower := strings.ToLower(message)
-	if strings.Contains(lower, "warning") || strings.Contains(lower, "error") || strings.Contains(lower, "failed") {
-		return true
-	}
-	if strings.Contains(message, "⚠") || strings.Contains(message, "✗") {
-		return true
-	}
-	return false
-}
-
 func stepLabel(stepLabels map[string]string, stepID string, payload any) string {
 	if p, ok := payload.(domain.StepStartPayload); ok {
-		if label := strings.TrimSpace(p.Label); label != "" {
+		if label := strings.TrimSpace(i18n.Text(p.Key, nil, p.Label)); label != "" {
 			stepLabels[stepID] = label
 			return label
 		}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/logging"
+)
+
+func runLogs(args []string) int {
+	sub := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		sub = strings.ToLower(strings.TrimSpace(args[0]))
+		args = args[1:]
+	}
+
+	indexPath, err := logging.HistoryIndexPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch sub {
+	case "list", "ls":
+		entries, err := logging.LoadHistory(indexPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		printLogsList(os.Stdout, entries)
+		return 0
+	case "show":
+		if len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "Usage: evo logs show <n|path>")
+			return 2
+		}
+		path, err := resolveLogRef(indexPath, args[0])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return printLogFile(path)
+	case "last":
+		path, err := resolveLogRef(indexPath, "1")
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		return printLogFile(path)
+	case "prune":
+		fs := flag.NewFlagSet("logs prune", flag.ContinueOnError)
+		keep := fs.Int("keep", logging.HistoryKeep(), "Runs to keep per project")
+		if err := fs.Parse(args); err != nil {
+			return 2
+		}
+		removed, err := logging.PruneHistory(indexPath, *keep)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("Pruned %d log(s); keeping up to %d per project.\n", len(removed), *keep)
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown logs command: %s\n", sub)
+		fmt.Fprintln(os.Stderr, "Usage: evo logs [list|show <n|path>|last|prune [--keep=N]]")
+		return 2
+	}
+}
+
+func printLogsList(w io.Writer, entries []logging.HistoryEntry) {
+	if len(entries) == 0 {
+		fmt.Fprintln(w, "No installer runs recorded.")
+		return
+	}
+	for i, entry := range entries {
+		fmt.Fprintf(w, "%3d  %s  %-9s  %s\n", i+1, entry.Started.Local().Format(time.DateTime), entry.Result, entry.ProjectDir)
+		fmt.Fprintf(w, "     %s\n", entry.Path)
+	}
+}
+
+// resolveLogRef accepts a 1-based index from `evo logs list` or a file path.
+func resolveLogRef(indexPath string, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	n, err := strconv.Atoi(ref)
+	if err != nil {
+		return ref, nil
+	}
+	entries, err := logging.LoadHistory(indexPath)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no installer runs recorded")
+	}
+	if n < 1 || n > len(entries) {
+		return "", fmt.Errorf("log #%d not found (1-%d available)", n, len(entries))
+	}
+	return entries[n-1].Path, nil
+}
+
+func printLogFile(path string) int {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	_, _ = os.Stdout.Write(raw)
+	return 0
+}
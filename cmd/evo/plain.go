@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
+	"github.com/evolution-cms/installer/internal/logging"
+)
+
+// plainProgressStepPct is how far an inline progress line must advance before
+// --plain mode prints it again.
+const plainProgressStepPct = 10
+
+// plainPrompter asks installer questions as numbered line prompts for --plain
+// mode: no cursor movement or redrawing, and secrets are read without echo
+// when stdin is a terminal.
+type plainPrompter struct {
+	ctx context.Context
+	in  *bufio.Reader
+	out io.Writer
+
+	// fd is the terminal read for secret input; 0 with tty=false reads
+	// secrets as ordinary lines (e.g. answers piped from a file).
+	fd  uintptr
+	tty bool
+
+	// lastProgress remembers the last printed progress line per step.
+	lastProgress map[string]plainProgress
+}
+
+type plainProgress struct {
+	pct int
+	msg string
+}
+
+func newPlainPrompter(ctx context.Context, in *os.File, out io.Writer) *plainPrompter {
+	return &plainPrompter{
+		ctx: ctx,
+		in:  bufio.NewReader(in),
+		out: out,
+		fd:  in.Fd(),
+		tty: term.IsTerminal(in.Fd()),
+	}
+}
+
+var errPlainInputClosed = errors.New("input closed")
+
+// ask prints q and reads answers until one is valid. Typing "<" (or "b" in a
+// list) goes back when the question allows it.
+func (p *plainPrompter) ask(q domain.QuestionState) (domain.Action, error) {
+	fmt.Fprintln(p.out)
+	fmt.Fprintln(p.out, "? "+strings.TrimSpace(q.Prompt))
+	printCLIDetails(p.out, q.Details)
+	if q.Error != "" {
+		fmt.Fprintln(p.out, "! "+q.Error)
+	}
+	if q.Kind == domain.QuestionInput {
+		return p.askInput(q)
+	}
+	return p.askSelect(q)
+}
+
+func (p *plainPrompter) askSelect(q domain.QuestionState) (domain.Action, error) {
+	if len(q.Options) == 0 {
+		return domain.Action{}, fmt.Errorf("question %q has no options", q.ID)
+	}
+	def := -1
+	if q.Selected >= 0 && q.Selected < len(q.Options) && q.Options[q.Selected].Enabled {
+		def = q.Selected
+	}
+	for i, opt := range q.Options {
+		line := fmt.Sprintf("  %d) %s", i+1, opt.Label)
+		if !opt.Enabled {
+			line += " (" + i18n.T("plain.unavailable", nil)
+			if reason := strings.TrimSpace(opt.Reason); reason != "" {
+				line += ": " + reason
+			}
+			line += ")"
+		}
+		fmt.Fprintln(p.out, line)
+	}
+
+	count := strconv.Itoa(len(q.Options))
+	hint := i18n.T("plain.choose", map[string]string{"count": count})
+	if def >= 0 {
+		hint += i18n.T("plain.enter_for", map[string]string{"n": strconv.Itoa(def + 1)})
+	}
+	if q.CanGoBack {
+		hint += i18n.T("plain.back_list", nil)
+	}
+	for {
+		fmt.Fprint(p.out, hint+": ")
+		line, err := p.readLine(false)
+		if err != nil {
+			return domain.Action{}, err
+		}
+		answer := strings.ToLower(strings.TrimSpace(line))
+		switch {
+		case answer == "" && def >= 0:
+			return selectAction(q, def), nil
+		case (answer == "b" || answer == "<") && q.CanGoBack:
+			return domain.Action{Type: domain.ActionBack, QuestionID: q.ID}, nil
+		}
+		n, convErr := strconv.Atoi(answer)
+		if convErr != nil || n < 1 || n > len(q.Options) {
+			fmt.Fprintln(p.out, "! "+i18n.T("plain.invalid_number", map[string]string{"count": count}))
+			continue
+		}
+		if !q.Options[n-1].Enabled {
+			fmt.Fprintln(p.out, "! "+i18n.T("plain.option_unavailable", map[string]string{"n": strconv.Itoa(n)}))
+			continue
+		}
+		return selectAction(q, n-1), nil
+	}
+}
+
+func selectAction(q domain.QuestionState, idx int) domain.Action {
+	return domain.Action{
+		Type:       domain.ActionAnswerSelect,
+		QuestionID: q.ID,
+		OptionID:   q.Options[idx].ID,
+	}
+}
+
+func (p *plainPrompter) askInput(q domain.QuestionState) (domain.Action, error) {
+	// A revisited question keeps its previous answer on Enter.
+	def := q.Default
+	if q.Value != "" {
+		def = q.Value
+	}
+	hint := i18n.T("plain.answer", nil)
+	switch {
+	case q.Secret:
+		hint += i18n.T("plain.hidden", nil)
+		if def != "" {
+			hint += i18n.T("plain.keep", nil)
+		}
+	case def != "":
+		hint += " [" + def + "]"
+	}
+	if q.CanGoBack {
+		hint += i18n.T("plain.back_input", nil)
+	}
+
+	for {
+		fmt.Fprint(p.out, hint+": ")
+		line, err := p.readLine(q.Secret)
+		if err != nil {
+			return domain.Action{}, err
+		}
+		value := line
+		if !q.Secret {
+			value = strings.TrimSpace(value)
+		}
+		if strings.TrimSpace(value) == "<" && q.CanGoBack {
+			return domain.Action{Type: domain.ActionBack, QuestionID: q.ID}, nil
+		}
+		if strings.TrimSpace(value) == "" {
+			value = def
+		}
+		if err := domain.ValidateAnswer(value, q.Validate...); err != nil {
+			fmt.Fprintln(p.out, "! "+i18n.Error(err))
+			continue
+		}
+		if q.Secret {
+			logging.RegisterSecretAnswer(value)
+		}
+		return domain.Action{Type: domain.ActionAnswerInput, QuestionID: q.ID, Text: value}, nil
+	}
+}
+
+// readLine reads one answer without the line ending. Cancelling the context
+// (Ctrl+C) returns immediately and restores the terminal echo.
+func (p *plainPrompter) readLine(secret bool) (string, error) {
+	hidden := secret && p.tty
+	var saved *term.State
+	if hidden {
+		saved, _ = term.GetState(p.fd)
+	}
+
+	type result struct {
+		line string
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		var r result
+		if hidden {
+			b, err := term.ReadPassword(p.fd)
+			fmt.Fprintln(p.out)
+			r = result{line: string(b), err: err}
+		} else {
+			r.line, r.err = p.in.ReadString('\n')
+			if r.err == io.EOF && r.line != "" {
+				r.err = nil
+			}
+		}
+		ch <- r
+	}()
+
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	select {
+	case <-ctx.Done():
+		if saved != nil {
+			_ = term.Restore(p.fd, saved)
+		}
+		fmt.Fprintln(p.out)
+		return "", ctx.Err()
+	case r := <-ch:
+		if errors.Is(r.err, io.EOF) {
+			return "", errPlainInputClosed
+		}
+		return strings.TrimRight(r.line, "\r\n"), r.err
+	}
+}
+
+// handlePlainQuestion answers a question from stdin; a closed input or Ctrl+C
+// stops the run.
+func handlePlainQuestion(p *plainPrompter, q domain.QuestionState, actions chan<- domain.Action, cancel func(), hadError *bool) bool {
+	action, err := p.ask(q)
+	if err != nil {
+		stopPlainRun(err, cancel, hadError)
+		return true
+	}
+	sendAction(actions, action)
+	return true
+}
+
+func stopPlainRun(err error, cancel func(), hadError *bool) {
+	*hadError = true
+	if !errors.Is(err, context.Canceled) {
+		fmt.Fprintf(os.Stderr, "Cannot read answer: %v\n", err)
+	}
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// handlePlainExtras offers the preselected Extras as a numbered choice; the
+// per-package picker stays in the TUI.
+func handlePlainExtras(p *plainPrompter, st domain.ExtrasState, actions chan<- domain.Action, cancel func(), hadError *bool) bool {
+	required := requiredExtrasSelections(st.Selections)
+	q := domain.QuestionState{
+		ID:     "extras_select",
+		Kind:   domain.QuestionSelect,
+		Prompt: "Install Extras?",
+	}
+	if len(st.Selections) > 0 {
+		q.Prompt = "Install Extras? Preselected: " + extrasSelectionNames(st.Selections)
+		q.Options = append(q.Options, domain.QuestionOption{ID: "all", Label: "Install the preselected Extras", Enabled: true})
+	}
+	if len(required) > 0 && len(required) < len(st.Selections) {
+		q.Options = append(q.Options, domain.QuestionOption{ID: "required", Label: "Install required Extras only (" + extrasSelectionNames(required) + ")", Enabled: true})
+	}
+	q.Options = append(q.Options, domain.QuestionOption{ID: "skip", Label: "Skip Extras", Enabled: true})
+
+	action, err := p.ask(q)
+	if err != nil {
+		stopPlainRun(err, cancel, hadError)
+		return true
+	}
+	decision := domain.Action{Type: domain.ActionExtrasDecision, QuestionID: q.ID, OptionID: "install"}
+	switch action.OptionID {
+	case "all":
+		decision.Extras = st.Selections
+	case "required":
+		decision.Extras = required
+	default:
+		decision.OptionID = "skip"
+	}
+	sendAction(actions, decision)
+	return true
+}
+
+func extrasSelectionNames(selections []domain.ExtrasSelection) string {
+	names := make([]string, 0, len(selections))
+	for _, sel := range selections {
+		name := strings.TrimSpace(sel.Name)
+		if name == "" {
+			name = sel.ID
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// printProgressLine prints an inline progress update as a regular line,
+// skipping repeats and advances smaller than plainProgressStepPct. pct is -1
+// when the update carries no percentage.
+func (p *plainPrompter) printProgressLine(prefix, stepID, message string, pct int, out io.Writer, state *cliState) {
+	if p.lastProgress == nil {
+		p.lastProgress = map[string]plainProgress{}
+	}
+	key := stepID + "\x00" + prefix
+	if last, seen := p.lastProgress[key]; seen {
+		if last.msg == message {
+			return
+		}
+		if pct >= 0 && pct < 100 && last.pct >= 0 && pct >= last.pct && pct-last.pct < plainProgressStepPct {
+			return
+		}
+	}
+	p.lastProgress[key] = plainProgress{pct: pct, msg: message}
+	printCLILine(prefix, stepID, message, out, state)
+}
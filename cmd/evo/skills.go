@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	installengine "github.com/evolution-cms/installer/internal/engine/install"
+)
+
+const skillsUsage = "Usage: evo skills [verify|remove] [dir] [--skills-target=a,b] [--skills-source=path]"
+
+func runSkills(args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Fprintln(os.Stderr, skillsUsage)
+		return 2
+	}
+	sub := strings.ToLower(strings.TrimSpace(args[0]))
+	args = args[1:]
+	dir := "."
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		dir, args = args[0], args[1:]
+	}
+
+	fs := flag.NewFlagSet("skills "+sub, flag.ContinueOnError)
+	target := fs.String("skills-target", "", "Comma-separated lockfile targets (verify: default all)")
+	source := fs.String("skills-source", "", "evo-skills source checkout whose manifest sets the lockfile path")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	targets, err := parseSkillsList("--skills-target", *target)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	lockfile, err := installengine.SkillsLockfilePath(dir, *source)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	switch sub {
+	case "verify":
+		reports, err := installengine.VerifySkills(dir, lockfile, targets)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		code := 0
+		for _, report := range reports {
+			if len(report.Problems) == 0 {
+				fmt.Printf("%s: OK (%d skill(s))\n", report.Target, report.Skills)
+				continue
+			}
+			code = 1
+			fmt.Printf("%s: %d problem(s)\n", report.Target, len(report.Problems))
+			for _, problem := range report.Problems {
+				fmt.Printf("  - %s\n", problem)
+			}
+		}
+		return code
+	case "remove":
+		if len(targets) == 0 {
+			fmt.Fprintln(os.Stderr, "evo skills remove requires --skills-target")
+			return 2
+		}
+		removed, err := installengine.RemoveSkills(dir, lockfile, targets)
+		for _, path := range removed {
+			fmt.Printf("Removed %s\n", path)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("Removed skills target(s): %s\n", strings.Join(targets, ", "))
+		return 0
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown skills command: %s\n", sub)
+		fmt.Fprintln(os.Stderr, skillsUsage)
+		return 2
+	}
+}
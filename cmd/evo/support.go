@@ -0,0 +1,374 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	installengine "github.com/evolution-cms/installer/internal/engine/install"
+	"github.com/evolution-cms/installer/internal/logging"
+)
+
+const (
+	supportHistoryLogs  = 5
+	supportMaxFileBytes = 4 << 20
+)
+
+// bundleFile is one archive entry. Note explains skipped or partial items in
+// the manifest.
+type bundleFile struct {
+	Name string
+	Data []byte
+	Note string
+}
+
+func runSupportBundle(ctx context.Context, args []string) int {
+	fs := flag.NewFlagSet("support-bundle", flag.ContinueOnError)
+	out := fs.String("out", "", "Archive path (default: <dir>/evo-support-<timestamp>.tar.gz)")
+	dir := "."
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		dir = args[0]
+		args = args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectDir, err := filepath.Abs(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	logging.RegisterSecretsFromEnv(os.Environ())
+	if err := logging.LoadRedactPatterns(logging.RedactPatternsPath()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	files := collectSupportFiles(ctx, projectDir)
+	archive := strings.TrimSpace(*out)
+	if archive == "" {
+		archive = filepath.Join(projectDir, "evo-support-"+time.Now().Format("20060102-150405")+".tar.gz")
+	}
+	manifest := supportManifest(files)
+	files = append([]bundleFile{{Name: "manifest.txt", Data: []byte(manifest)}}, files...)
+	if err := writeSupportArchive(archive, files); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Print(manifest)
+	fmt.Printf("\nSupport bundle saved to %s\n", archive)
+	fmt.Println("All files were redacted; review the archive before sharing it.")
+	return 0
+}
+
+func collectSupportFiles(ctx context.Context, projectDir string) []bundleFile {
+	var files []bundleFile
+	add := func(name string, data []byte, note string) {
+		files = append(files, bundleFile{Name: name, Data: data, Note: note})
+	}
+	addFile := func(name string, path string) {
+		data, note := readSupportFile(path)
+		if data == nil && note == "" {
+			return
+		}
+		add(name, data, note)
+	}
+
+	for _, name := range []string{"log.md", "log.json", "log.ndjson"} {
+		addFile("logs/"+name, filepath.Join(projectDir, name))
+	}
+	for _, path := range latestHistoryLogs(projectDir, supportHistoryLogs) {
+		addFile("logs/history/"+filepath.Base(path), path)
+	}
+	addFile("options.json", filepath.Join(projectDir, ".evo", logging.OptionsSnapshotFile))
+
+	if raw, err := installengine.SystemStatusReport(ctx); err != nil {
+		add("system-status.json", raw, "system-status failed: "+err.Error())
+	} else {
+		add("system-status.json", raw, "")
+	}
+	add("environment.txt", []byte(supportEnvironment(ctx, projectDir)), "")
+
+	composerDir := projectDir
+	if fileExists(filepath.Join(projectDir, "core", "composer.json")) {
+		composerDir = filepath.Join(projectDir, "core")
+	}
+	addFile("composer/composer.json", filepath.Join(composerDir, "composer.json"))
+	if lock, err := os.ReadFile(filepath.Join(composerDir, "composer.lock")); err == nil {
+		add("composer/composer.lock.packages.txt", []byte(composerLockPackages(lock)), "")
+	}
+	if diff := gitDiff(ctx, composerDir, "composer.json", "composer.lock"); diff != "" {
+		add("composer/composer.diff", []byte(diff), "")
+	}
+	if report, err := json.MarshalIndent(vendorHealthReport(composerDir), "", "  "); err == nil {
+		add("composer/vendor-health.json", report, "")
+	}
+
+	addFile("extras/composer.json", filepath.Join(projectDir, "core", "custom", "composer.json"))
+	addFile("extras/composer.lock", filepath.Join(projectDir, "core", "custom", "composer.lock"))
+	addFile("skills/.evo-skills.lock.json", filepath.Join(projectDir, "core", "custom", "skills", ".evo-skills.lock.json"))
+
+	for i := range files {
+		files[i].Data = []byte(logging.RedactDocument(string(files[i].Data)))
+	}
+	return files
+}
+
+func readSupportFile(path string) ([]byte, string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ""
+		}
+		return nil, "unreadable: " + err.Error()
+	}
+	if info.IsDir() {
+		return nil, ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, "unreadable: " + err.Error()
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, supportMaxFileBytes))
+	if err != nil {
+		return nil, "unreadable: " + err.Error()
+	}
+	if info.Size() > supportMaxFileBytes {
+		return data, fmt.Sprintf("truncated to %d bytes", supportMaxFileBytes)
+	}
+	return data, ""
+}
+
+func latestHistoryLogs(projectDir string, limit int) []string {
+	dir := logging.HistoryDir(projectDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	if len(names) > limit {
+		names = names[:limit]
+	}
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		out = append(out, filepath.Join(dir, name))
+	}
+	return out
+}
+
+func supportEnvironment(ctx context.Context, projectDir string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "evo: %s\n", Version)
+	fmt.Fprintf(&b, "os/arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "project: %s\n", projectDir)
+
+	php, err := exec.LookPath("php")
+	if err != nil {
+		fmt.Fprintf(&b, "\n## php\nnot found: %v\n", err)
+	} else {
+		fmt.Fprintf(&b, "\n## php (%s)\n", php)
+		b.WriteString(commandOutput(ctx, php, "-v"))
+		b.WriteString("\n## php -m\n")
+		b.WriteString(commandOutput(ctx, php, "-m"))
+		b.WriteString("\n## php --ini\n")
+		b.WriteString(commandOutput(ctx, php, "--ini"))
+	}
+
+	b.WriteString("\n## composer\n")
+	found := false
+	for _, bin := range composerCandidates() {
+		path, err := exec.LookPath(bin)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s: %s", path, commandOutput(ctx, path, "--no-ansi", "--version"))
+		found = true
+		break
+	}
+	if !found {
+		b.WriteString("not found\n")
+	}
+
+	if runtime.GOOS != "windows" {
+		b.WriteString("\n## disk\n")
+		b.WriteString(commandOutput(ctx, "df", "-h", projectDir))
+	}
+	return b.String()
+}
+
+func commandOutput(ctx context.Context, name string, args ...string) string {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	text := strings.TrimRight(string(out), "\n")
+	if err != nil {
+		text = strings.TrimSpace(text + "\n(" + err.Error() + ")")
+	}
+	return text + "\n"
+}
+
+func gitDiff(ctx context.Context, dir string, paths ...string) string {
+	if _, err := exec.LookPath("git"); err != nil {
+		return ""
+	}
+	args := append([]string{"-C", dir, "diff", "--no-color", "--"}, paths...)
+	out, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// composerLockPackages lists name and version from composer.lock instead of
+// shipping the full lock file.
+func composerLockPackages(raw []byte) string {
+	var lock struct {
+		ContentHash string `json:"content-hash"`
+		Packages    []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"packages"`
+		PackagesDev []struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(raw, &lock); err != nil {
+		return "invalid composer.lock: " + err.Error() + "\n"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "content-hash: %s\n", lock.ContentHash)
+	for _, p := range lock.Packages {
+		fmt.Fprintf(&b, "%s %s\n", p.Name, p.Version)
+	}
+	for _, p := range lock.PackagesDev {
+		fmt.Fprintf(&b, "%s %s (dev)\n", p.Name, p.Version)
+	}
+	return b.String()
+}
+
+type vendorHealth struct {
+	ComposerDir      string   `json:"composer_dir"`
+	Healthy          bool     `json:"healthy"`
+	PackageCount     int      `json:"package_count"`
+	MissingArtifacts []string `json:"missing_artifacts"`
+	MissingPackages  []string `json:"missing_packages"`
+}
+
+// vendorHealthReport mirrors the artifact and package checks the PHP
+// installer runs after Composer (InstallCommand::composerVendorIssues).
+func vendorHealthReport(composerDir string) vendorHealth {
+	report := vendorHealth{ComposerDir: composerDir, MissingArtifacts: []string{}, MissingPackages: []string{}}
+	for _, rel := range []string{
+		"vendor/autoload.php",
+		"vendor/composer/autoload_real.php",
+		"vendor/composer/autoload_psr4.php",
+		"vendor/composer/autoload_classmap.php",
+		"vendor/symfony/console/Application.php",
+		"vendor/symfony/http-kernel/Kernel.php",
+		"vendor/symfony/routing/RouteCollection.php",
+		"vendor/illuminate/console/Application.php",
+	} {
+		if info, err := os.Stat(filepath.Join(composerDir, filepath.FromSlash(rel))); err != nil || info.Size() == 0 {
+			report.MissingArtifacts = append(report.MissingArtifacts, rel)
+		}
+	}
+
+	installed := map[string]bool{}
+	if raw, err := os.ReadFile(filepath.Join(composerDir, "vendor", "composer", "installed.json")); err == nil {
+		var parsed struct {
+			Packages []struct {
+				Name string `json:"name"`
+			} `json:"packages"`
+		}
+		if json.Unmarshal(raw, &parsed) == nil {
+			for _, p := range parsed.Packages {
+				installed[p.Name] = true
+			}
+		}
+	} else {
+		report.MissingArtifacts = append(report.MissingArtifacts, "vendor/composer/installed.json")
+	}
+	report.PackageCount = len(installed)
+	if len(installed) > 0 {
+		for _, pkg := range []string{"symfony/console", "symfony/http-kernel", "symfony/routing", "illuminate/support", "illuminate/console"} {
+			if !installed[pkg] {
+				report.MissingPackages = append(report.MissingPackages, pkg)
+			}
+		}
+	}
+	report.Healthy = len(report.MissingArtifacts) == 0 && len(report.MissingPackages) == 0
+	return report
+}
+
+func supportManifest(files []bundleFile) string {
+	var b strings.Builder
+	b.WriteString("Evolution CMS installer support bundle\n")
+	fmt.Fprintf(&b, "Created: %s\n\n", time.Now().Format(time.RFC3339))
+	for _, f := range files {
+		line := fmt.Sprintf("  %-40s %8d bytes", f.Name, len(f.Data))
+		if f.Note != "" {
+			line += "  (" + f.Note + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	return b.String()
+}
+
+func writeSupportArchive(path string, files []bundleFile) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	now := time.Now()
+	for _, f := range files {
+		hdr := &tar.Header{
+			Name:    "evo-support/" + f.Name,
+			Mode:    0o644,
+			Size:    int64(len(f.Data)),
+			ModTime: now,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVendorHealthReportFlagsMissingPackages(t *testing.T) {
+	dir := t.TempDir()
+	composerDir := filepath.Join(dir, "vendor", "composer")
+	if err := os.MkdirAll(composerDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	installed := `{"packages":[{"name":"symfony/console"},{"name":"illuminate/support"}]}`
+	if err := os.WriteFile(filepath.Join(composerDir, "installed.json"), []byte(installed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report := vendorHealthReport(dir)
+	if report.Healthy {
+		t.Fatal("expected unhealthy vendor report")
+	}
+	if report.PackageCount != 2 {
+		t.Fatalf("package count = %d, want 2", report.PackageCount)
+	}
+	if got := strings.Join(report.MissingPackages, ","); got != "symfony/http-kernel,symfony/routing,illuminate/console" {
+		t.Fatalf("missing packages = %q", got)
+	}
+	if len(report.MissingArtifacts) == 0 || report.MissingArtifacts[0] != "vendor/autoload.php" {
+		t.Fatalf("missing artifacts = %#v", report.MissingArtifacts)
+	}
+}
+
+func TestComposerLockPackagesListsVersions(t *testing.T) {
+	raw := []byte(`{"content-hash":"abc","packages":[{"name":"evolution-cms/core","version":"3.5.0"}],"packages-dev":[{"name":"phpunit/phpunit","version":"11.0.0"}]}`)
+
+	got := composerLockPackages(raw)
+	want := "content-hash: abc\nevolution-cms/core 3.5.0\nphpunit/phpunit 11.0.0 (dev)\n"
+	if got != want {
+		t.Fatalf("packages = %q, want %q", got, want)
+	}
+}
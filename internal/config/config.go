@@ -0,0 +1,56 @@
+// Package config reads the optional user configuration file of the installer.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds user preferences. Command-line flags and environment variables
+// take precedence over it.
+type Config struct {
+	// Theme is the TUI theme: auto, dark, light, high-contrast or monochrome.
+	Theme string `json:"theme"`
+	// UILang is the installer interface language (en, uk, ru or de).
+	UILang string `json:"ui_lang"`
+	// TermProgress turns terminal tab progress, window titles and
+	// notifications off when false.
+	TermProgress *bool `json:"term_progress,omitempty"`
+}
+
+// Path returns the config file: EVO_CONFIG or config.json in the user config
+// directory (next to redact.txt).
+func Path() string {
+	if p := strings.TrimSpace(os.Getenv("EVO_CONFIG")); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "evo-installer", "config.json")
+}
+
+// Load reads the config file at path. A missing file is not an error.
+func Load(path string) (Config, error) {
+	var cfg Config
+	if strings.TrimSpace(path) == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg, err := Load(filepath.Join(dir, "missing.json"))
+	if err != nil || cfg != (Config{}) {
+		t.Fatalf("Load(missing) = %+v, %v; want empty config", cfg, err)
+	}
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"theme": "light"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(path)
+	if err != nil || cfg.Theme != "light" {
+		t.Fatalf("Load() = %+v, %v; want theme light", cfg, err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{theme}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("Load(invalid JSON) = nil error, want error")
+	}
+}
@@ -6,6 +6,8 @@ const (
 	ActionAnswerSelect   ActionType = "answer_select"
 	ActionAnswerInput    ActionType = "answer_input"
 	ActionExtrasDecision ActionType = "extras_decision"
+	// ActionBack returns from QuestionID to the previous question.
+	ActionBack ActionType = "back"
 )
 
 type Action struct {
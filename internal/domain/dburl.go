@@ -0,0 +1,139 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DatabaseURL is a parsed connection URL such as
+// mysql://user:pass@db:3306/site or sqlite:///abs/path.sqlite.
+type DatabaseURL struct {
+	Type     string
+	Host     string
+	Port     int
+	Name     string
+	User     string
+	Password string
+	// Options are the query parameters, passed on to the driver as DSN
+	// options (e.g. sslmode=require).
+	Options map[string]string
+}
+
+// databaseURLSchemes maps URL schemes and their common aliases to driver IDs.
+var databaseURLSchemes = map[string]string{
+	"mysql":      "mysql",
+	"mariadb":    "mysql",
+	"pgsql":      "pgsql",
+	"postgres":   "pgsql",
+	"postgresql": "pgsql",
+	"sqlite":     "sqlite",
+	"sqlite3":    "sqlite",
+	"sqlsrv":     "sqlsrv",
+	"mssql":      "sqlsrv",
+	"sqlserver":  "sqlsrv",
+}
+
+var dbOptionKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// mysqlURLOptions are the DSN options pdo_mysql reads; it silently ignores
+// any other, TLS settings included.
+var mysqlURLOptions = []string{"unix_socket", "charset"}
+
+// ParseDatabaseURL parses a database connection URL. User, password and
+// database name are percent-decoded. Errors never quote the URL, so the
+// password cannot leak into logs or terminal output.
+func ParseDatabaseURL(raw string) (DatabaseURL, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return DatabaseURL{}, errors.New("database URL is empty")
+	}
+	scheme, _, ok := strings.Cut(raw, ":")
+	if !ok {
+		return DatabaseURL{}, errors.New("database URL must start with a driver, e.g. mysql://")
+	}
+	dbType, ok := databaseURLSchemes[strings.ToLower(scheme)]
+	if !ok {
+		return DatabaseURL{}, fmt.Errorf("unknown database URL driver %q (use mysql, mariadb, pgsql, postgres, sqlite or sqlsrv)", scheme)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		// url.Error quotes the whole URL, password included.
+		return DatabaseURL{}, errors.New("database URL is malformed; percent-encode special characters in the user name and password")
+	}
+
+	out := DatabaseURL{Type: dbType}
+	if len(u.RawQuery) > 0 {
+		query, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			return DatabaseURL{}, errors.New("database URL has a malformed query string")
+		}
+		out.Options = make(map[string]string, len(query))
+		for key, values := range query {
+			value := values[len(values)-1]
+			if !dbOptionKeyRe.MatchString(key) {
+				return DatabaseURL{}, fmt.Errorf("database URL option %q is not a valid option name", key)
+			}
+			if strings.ContainsAny(value, ";\x00\r\n") {
+				return DatabaseURL{}, fmt.Errorf("database URL option %q contains an invalid character", key)
+			}
+			if dbType == "mysql" && !slices.Contains(mysqlURLOptions, key) {
+				lower := strings.ToLower(key)
+				if strings.HasPrefix(lower, "ssl") || strings.HasPrefix(lower, "tls") {
+					return DatabaseURL{}, fmt.Errorf("database URL option %q is ignored by MySQL; use --db-ssl-mode, --db-ssl-ca, --db-ssl-cert and --db-ssl-key for TLS", key)
+				}
+				return DatabaseURL{}, fmt.Errorf("database URL option %q is ignored by MySQL, which only reads unix_socket and charset", key)
+			}
+			out.Options[key] = value
+		}
+	}
+
+	if dbType == "sqlite" {
+		// sqlite:///abs/path, sqlite://relative.sqlite and sqlite::memory:.
+		switch {
+		case u.Opaque != "":
+			out.Name, err = url.PathUnescape(u.Opaque)
+			if err != nil {
+				return DatabaseURL{}, errors.New("database URL has a malformed path")
+			}
+		default:
+			out.Name = u.Host + u.Path
+		}
+		if out.Name == "" {
+			return DatabaseURL{}, errors.New("SQLite database URL needs a file path, e.g. sqlite:///var/db/site.sqlite")
+		}
+		return out, nil
+	}
+
+	if u.Opaque != "" || u.Host == "" {
+		return DatabaseURL{}, fmt.Errorf("database URL needs a host, e.g. %s://user:pass@localhost/site", scheme)
+	}
+	out.Host = u.Hostname()
+	if p := u.Port(); p != "" {
+		port, err := strconv.Atoi(p)
+		if err != nil || port < 1 || port > 65535 {
+			return DatabaseURL{}, errors.New("database URL port must be a number between 1 and 65535")
+		}
+		out.Port = port
+	}
+	if u.User != nil {
+		out.User = u.User.Username()
+		out.Password, _ = u.User.Password()
+	}
+	out.Name = strings.TrimPrefix(u.Path, "/")
+	return out, nil
+}
+
+// EncodeDatabaseOptions renders driver options as a query string, the form
+// the PHP installer accepts in --db-options.
+func EncodeDatabaseOptions(options map[string]string) string {
+	values := url.Values{}
+	for k, v := range options {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
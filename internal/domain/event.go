@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type EventType string
 
@@ -15,17 +19,96 @@ const (
 	EventError        EventType = "error"
 	EventExecRequest  EventType = "exec_request"
 	EventExtras       EventType = "extras"
+	EventTiming       EventType = "timing"
+	EventSummary      EventType = "summary"
 )
 
 type Severity string
 
 const (
 	SeverityTrace Severity = "trace"
+	SeverityDebug Severity = "debug"
 	SeverityInfo  Severity = "info"
 	SeverityWarn  Severity = "warn"
 	SeverityError Severity = "error"
 )
 
+// ParseSeverity accepts a --log-level value.
+func ParseSeverity(s string) (Severity, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace":
+		return SeverityTrace, nil
+	case "debug":
+		return SeverityDebug, nil
+	case "info", "":
+		return SeverityInfo, nil
+	case "warn", "warning":
+		return SeverityWarn, nil
+	case "error":
+		return SeverityError, nil
+	default:
+		return "", fmt.Errorf("invalid log level %q (use trace, debug, info, warn or error)", s)
+	}
+}
+
+func (s Severity) rank() int {
+	switch s {
+	case SeverityTrace:
+		return 0
+	case SeverityDebug:
+		return 1
+	case SeverityWarn:
+		return 3
+	case SeverityError:
+		return 4
+	default:
+		return 2
+	}
+}
+
+// Enabled reports whether s passes the minimum severity min. An empty
+// severity counts as info.
+func (s Severity) Enabled(min Severity) bool {
+	return s.rank() >= min.rank()
+}
+
+// Below reports whether s is more verbose than other.
+func (s Severity) Below(other Severity) bool {
+	return s.rank() < other.rank()
+}
+
+// LogLevel maps a severity to the level stored in log entries.
+func (s Severity) LogLevel() LogLevel {
+	switch s {
+	case SeverityTrace:
+		return LogTrace
+	case SeverityDebug:
+		return LogDebug
+	case SeverityWarn:
+		return LogWarning
+	case SeverityError:
+		return LogError
+	default:
+		return LogInfo
+	}
+}
+
+// EventSeverity returns the event severity, falling back to one derived from
+// the event type when the emitter left it empty.
+func EventSeverity(ev Event) Severity {
+	if ev.Severity != "" {
+		return ev.Severity
+	}
+	switch ev.Type {
+	case EventError:
+		return SeverityError
+	case EventWarning:
+		return SeverityWarn
+	default:
+		return SeverityInfo
+	}
+}
+
 type Event struct {
 	Type     EventType
 	StepID   string
@@ -39,6 +122,11 @@ type StepStartPayload struct {
 	Label string
 	Index int
 	Total int
+	// Key is the message catalog key of Label.
+	Key string
+	// Variant distinguishes runs whose duration depends on the setup (the
+	// database driver); step duration history is keyed by step ID and variant.
+	Variant string
 }
 
 type StepDonePayload struct {
@@ -51,15 +139,32 @@ type ProgressPayload struct {
 	Unit    string
 }
 
+// TimingPayload reports how long a sub-phase of the event's step took, e.g. a
+// Composer run, the seeders or one extras item.
+type TimingPayload struct {
+	Label    string
+	Duration time.Duration
+}
+
 type LogPayload struct {
 	Message string
 	Fields  map[string]string
+	// Key and Params identify Message in the message catalog (package i18n)
+	// so the UI can show it in the selected language; Message stays the
+	// English text written to log files.
+	Key    string
+	Params map[string]string
 }
 
 type QuestionPayload struct {
 	Question QuestionState
 }
 
+// SummaryPayload is sent once after a successful installation.
+type SummaryPayload struct {
+	Summary InstallSummary
+}
+
 type StepsPayload struct {
 	Steps []StepState
 }
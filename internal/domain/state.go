@@ -77,6 +77,8 @@ type StepState struct {
 	ID     string
 	Label  string
 	Status StepStatus
+	// Key is the message catalog key of Label (see package i18n).
+	Key string
 }
 
 type DBDriverState struct {
@@ -89,6 +91,8 @@ type DBDriverState struct {
 type LogLevel string
 
 const (
+	LogTrace   LogLevel = "trace"
+	LogDebug   LogLevel = "debug"
 	LogInfo    LogLevel = "info"
 	LogWarning LogLevel = "warning"
 	LogError   LogLevel = "error"
@@ -101,6 +105,9 @@ type LogEntry struct {
 	StepID  string
 	Message string
 	Fields  map[string]string
+	// Key and Params identify Message in the message catalog.
+	Key    string
+	Params map[string]string
 }
 
 type LogState struct {
@@ -116,9 +123,43 @@ type QuestionState struct {
 	Options  []QuestionOption
 	Selected int
 
+	// Key and Params identify Prompt in the message catalog; Prompt stays the
+	// English fallback.
+	Key    string
+	Params map[string]string
+
 	// Input-mode only (Kind == QuestionInput).
 	Default string
 	Secret  bool
+	// Value prefills the input with a previous answer when the question is
+	// revisited.
+	Value string
+
+	// CanGoBack allows the UI to answer with ActionBack to return to the
+	// previous question.
+	CanGoBack bool
+
+	// Validate lists the checks an answer must pass (see ValidateAnswer); the
+	// UI runs them before sending, the engine again before accepting.
+	Validate []ValidationRule
+	// Error explains why the previous answer was rejected; ErrorKey and
+	// ErrorParams identify it in the message catalog.
+	Error       string
+	ErrorKey    string
+	ErrorParams map[string]string
+
+	// Details are label/value rows shown between the prompt and the options
+	// (e.g. the settings listed on the review screen).
+	Details []QuestionDetail
+}
+
+type QuestionDetail struct {
+	Label string
+	Value string
+	// Key is the catalog key of Label; ValueKey is set when Value is fixed
+	// installer text rather than user data.
+	Key      string
+	ValueKey string
 }
 
 type QuestionKind string
@@ -133,6 +174,12 @@ type QuestionOption struct {
 	Label   string
 	Enabled bool
 	Reason  string
+
+	// Catalog keys and parameters of Label and Reason.
+	Key          string
+	Params       map[string]string
+	ReasonKey    string
+	ReasonParams map[string]string
 }
 
 type ProgressState struct {
@@ -0,0 +1,39 @@
+package domain
+
+import "strings"
+
+// InstallSummary describes a finished installation for the final screen.
+type InstallSummary struct {
+	ProjectPath string
+	// ManagerPath is the manager URL path, e.g. "/manager/".
+	ManagerPath string
+
+	AdminUsername string
+	// AdminPassword is only used to copy the credentials to the clipboard;
+	// consumers must never print or log it.
+	AdminPassword string
+
+	// Details are the label/value rows of the summary (project, manager,
+	// admin, database, version, preset), rendered like review details.
+	Details []QuestionDetail
+	// Extras lists the extras results; empty when extras were skipped.
+	Extras []ExtrasItemResult
+	// NextSteps are follow-up hints (docroot, permissions, cron), rendered
+	// like log messages.
+	NextSteps []LogPayload
+}
+
+// Credentials returns the text copied to the clipboard from the summary.
+func (s InstallSummary) Credentials() string {
+	lines := []string{}
+	if s.ManagerPath != "" {
+		lines = append(lines, "Manager: "+s.ManagerPath)
+	}
+	if s.AdminUsername != "" {
+		lines = append(lines, "Username: "+s.AdminUsername)
+	}
+	if s.AdminPassword != "" {
+		lines = append(lines, "Password: "+s.AdminPassword)
+	}
+	return strings.Join(lines, "\n")
+}
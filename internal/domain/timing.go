@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimingDetail is a measured sub-phase of a step: a subprocess (Composer,
+// seeders, artisan) or a single extras item.
+type TimingDetail struct {
+	Label    string
+	Duration time.Duration
+}
+
+// StepTiming is the wall-clock time of one step plus the sub-phases reported
+// for it by EventTiming.
+type StepTiming struct {
+	ID      string
+	Label   string
+	Variant string
+	Started time.Time
+	Ended   time.Time
+	Done    bool
+	OK      bool
+	Details []TimingDetail
+}
+
+// Duration returns the elapsed step time; running steps report zero.
+func (s StepTiming) Duration() time.Duration {
+	if !s.Done || s.Started.IsZero() || s.Ended.Before(s.Started) {
+		return 0
+	}
+	return s.Ended.Sub(s.Started)
+}
+
+// SubprocessTime sums the reported sub-phases of the step.
+func (s StepTiming) SubprocessTime() time.Duration {
+	var total time.Duration
+	for _, d := range s.Details {
+		total += d.Duration
+	}
+	return total
+}
+
+// Timings collects per-step durations from the event stream. The zero value
+// is ready to use.
+type Timings struct {
+	Steps []StepTiming
+
+	index map[string]int
+	first time.Time
+	last  time.Time
+}
+
+// Observe updates timings from a single event.
+func (t *Timings) Observe(ev Event) {
+	ts := ev.TS
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	if t.first.IsZero() || ts.Before(t.first) {
+		t.first = ts
+	}
+	if ts.After(t.last) {
+		t.last = ts
+	}
+	if strings.TrimSpace(ev.StepID) == "" {
+		return
+	}
+
+	switch ev.Type {
+	case EventStepStart:
+		s := t.step(ev.StepID)
+		if p, ok := ev.Payload.(StepStartPayload); ok {
+			if strings.TrimSpace(p.Label) != "" {
+				s.Label = strings.TrimSpace(p.Label)
+			}
+			if p.Variant != "" {
+				s.Variant = p.Variant
+			}
+		}
+		if s.Started.IsZero() || s.Done {
+			s.Started = ts
+			s.Ended = time.Time{}
+			s.Done = false
+		}
+	case EventStepDone:
+		s := t.step(ev.StepID)
+		if s.Done {
+			return
+		}
+		if s.Started.IsZero() {
+			s.Started = ts
+		}
+		s.Ended = ts
+		s.Done = true
+		s.OK = true
+		if p, ok := ev.Payload.(StepDonePayload); ok {
+			s.OK = p.OK
+		}
+	case EventTiming:
+		p, ok := ev.Payload.(TimingPayload)
+		if !ok || p.Duration <= 0 {
+			return
+		}
+		s := t.step(ev.StepID)
+		s.Details = append(s.Details, TimingDetail{Label: strings.TrimSpace(p.Label), Duration: p.Duration})
+	}
+}
+
+// Step returns the timing of a step by ID.
+func (t *Timings) Step(id string) (StepTiming, bool) {
+	if t == nil || t.index == nil {
+		return StepTiming{}, false
+	}
+	i, ok := t.index[id]
+	if !ok {
+		return StepTiming{}, false
+	}
+	return t.Steps[i], true
+}
+
+// Total returns the time between the first and the last observed event.
+func (t *Timings) Total() time.Duration {
+	if t == nil || t.first.IsZero() {
+		return 0
+	}
+	return t.last.Sub(t.first)
+}
+
+// Table renders finished steps and their sub-phases as aligned plain-text
+// lines, ending with the total. It returns nil when no step finished.
+func (t *Timings) Table() []string {
+	if t == nil {
+		return nil
+	}
+	type row struct{ label, took, sub string }
+	rows := []row{}
+	for _, s := range t.Steps {
+		if !s.Done || s.Label == "" {
+			continue
+		}
+		r := row{label: s.Label, took: FormatDuration(s.Duration())}
+		if !s.OK {
+			r.took += " (failed)"
+		}
+		if sub := s.SubprocessTime(); sub > 0 {
+			r.sub = FormatDuration(sub)
+		}
+		rows = append(rows, r)
+		for _, d := range s.Details {
+			rows = append(rows, row{label: "  " + d.Label, took: FormatDuration(d.Duration)})
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	rows = append(rows, row{label: "Total", took: FormatDuration(t.Total())})
+
+	labelWidth, tookWidth := len("Step"), len("Time")
+	for _, r := range rows {
+		labelWidth = max(labelWidth, len([]rune(r.label)))
+		tookWidth = max(tookWidth, len(r.took))
+	}
+	format := fmt.Sprintf("%%-%ds  %%%ds  %%s", labelWidth, tookWidth)
+	lines := []string{strings.TrimRight(fmt.Sprintf(format, "Step", "Time", "Subprocess"), " ")}
+	for _, r := range rows {
+		lines = append(lines, strings.TrimRight(fmt.Sprintf(format, r.label, r.took, r.sub), " "))
+	}
+	return lines
+}
+
+func (t *Timings) step(id string) *StepTiming {
+	if t.index == nil {
+		t.index = map[string]int{}
+	}
+	i, ok := t.index[id]
+	if !ok {
+		t.Steps = append(t.Steps, StepTiming{ID: id})
+		i = len(t.Steps) - 1
+		t.index[id] = i
+	}
+	return &t.Steps[i]
+}
+
+// FormatDuration renders a duration compactly: 850ms, 12.3s, 4m02s, 1h05m.
+func FormatDuration(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "0s"
+	case d < time.Second:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	case d < time.Minute:
+		return fmt.Sprintf("%.1fs", d.Seconds())
+	case d < time.Hour:
+		d = d.Round(time.Second)
+		return fmt.Sprintf("%dm%02ds", int(d/time.Minute), int(d%time.Minute/time.Second))
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("%dh%02dm", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// Tracer receives trace-level diagnostics from code that has no event emitter
+// of its own (subprocess runners, HTTP clients).
+type Tracer func(source string, message string)
+
+type tracerKey struct{}
+
+// WithTracer attaches a tracer to ctx.
+func WithTracer(ctx context.Context, t Tracer) context.Context {
+	return context.WithValue(ctx, tracerKey{}, t)
+}
+
+// Tracef reports a trace message through the tracer attached to ctx, if any.
+func Tracef(ctx context.Context, source string, format string, args ...any) {
+	if ctx == nil {
+		return
+	}
+	t, _ := ctx.Value(tracerKey{}).(Tracer)
+	if t == nil {
+		return
+	}
+	t(source, fmt.Sprintf(format, args...))
+}
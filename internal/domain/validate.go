@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ValidationRule names a check applied to a question answer. The engine, the
+// UI and the CLI flag parser share the checks, so the same value is accepted
+// or rejected with the same message everywhere.
+type ValidationRule string
+
+const (
+	ValidateRequired     ValidationRule = "required"
+	ValidateUsername     ValidationRule = "username"
+	ValidateEmail        ValidationRule = "email"
+	ValidatePassword     ValidationRule = "password"
+	ValidateDBIdentifier ValidationRule = "db_identifier"
+	ValidateDBUser       ValidationRule = "db_user"
+	ValidatePort         ValidationRule = "port"
+	ValidateAdminDir     ValidationRule = "admin_dir"
+	ValidateDatabaseURL  ValidationRule = "db_url"
+	ValidateTablePrefix  ValidationRule = "table_prefix"
+)
+
+const (
+	MinPasswordLength = 6
+	MaxPasswordLength = 128
+)
+
+var (
+	usernameRe     = regexp.MustCompile(`^[A-Za-z0-9._@-]{1,64}$`)
+	dbIdentifierRe = regexp.MustCompile(`^[A-Za-z0-9_$-]{1,64}$`)
+	dbUserRe       = regexp.MustCompile(`^[^\s'"\x60\\;]{1,128}$`)
+	adminDirRe     = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+	tablePrefixRe  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,18}_$`)
+)
+
+// reservedAdminDirs are project root directories the manager cannot use.
+var reservedAdminDirs = []string{"assets", "core", "install", "themes", "vendor", "views"}
+
+// ValidationError is a rejected answer. Key and Params identify the message in
+// the UI message catalog; Error returns the English text.
+type ValidationError struct {
+	Key    string
+	Params map[string]string
+	msg    string
+}
+
+func (e *ValidationError) Error() string {
+	return e.msg
+}
+
+func invalid(key string, params map[string]string, msg string) error {
+	return &ValidationError{Key: key, Params: params, msg: msg}
+}
+
+// NewValidationError rejects an answer for a reason outside the shared rules,
+// e.g. one that depends on the system status.
+func NewValidationError(key string, params map[string]string, msg string) error {
+	return invalid(key, params, msg)
+}
+
+// ValidateAnswer runs the rules in order and returns the first failure as a
+// message that can be shown to the user as is. Rules other than
+// ValidateRequired accept an empty value.
+func ValidateAnswer(value string, rules ...ValidationRule) error {
+	value = strings.TrimSpace(value)
+	for _, rule := range rules {
+		if value == "" {
+			if rule == ValidateRequired {
+				return invalid("validate.required", nil, "This value cannot be empty.")
+			}
+			continue
+		}
+		if err := validateRule(value, rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateRule(value string, rule ValidationRule) error {
+	switch rule {
+	case ValidateUsername:
+		if !usernameRe.MatchString(value) {
+			return invalid("validate.username", nil, "Username may only contain letters, digits, '.', '_', '-' and '@' (up to 64 characters).")
+		}
+	case ValidateEmail:
+		addr, err := mail.ParseAddress(value)
+		if err != nil || addr.Address != value {
+			return invalid("validate.email", nil, "Please enter a valid email address.")
+		}
+	case ValidatePassword:
+		n := len([]rune(value))
+		if n < MinPasswordLength {
+			return invalid("validate.password_short", map[string]string{"min": strconv.Itoa(MinPasswordLength)},
+				fmt.Sprintf("Password must be at least %d characters long.", MinPasswordLength))
+		}
+		if n > MaxPasswordLength {
+			return invalid("validate.password_long", map[string]string{"max": strconv.Itoa(MaxPasswordLength)},
+				fmt.Sprintf("Password must be at most %d characters long.", MaxPasswordLength))
+		}
+	case ValidateDBIdentifier:
+		if !dbIdentifierRe.MatchString(value) {
+			return invalid("validate.db_identifier", nil, "Database name may only contain letters, digits, '_', '-' and '$' (up to 64 characters).")
+		}
+	case ValidateDBUser:
+		if !dbUserRe.MatchString(value) {
+			return invalid("validate.db_user", nil, "Database user cannot contain spaces, quotes, backslashes or ';' (up to 128 characters).")
+		}
+	case ValidatePort:
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return invalid("validate.port", nil, "Port must be a number between 1 and 65535.")
+		}
+	case ValidateDatabaseURL:
+		u, err := ParseDatabaseURL(value)
+		if err != nil {
+			return invalid("validate.db_url", map[string]string{"error": err.Error()}, "Invalid connection URL: "+err.Error())
+		}
+		// The URL replaces the database name and user questions, so their
+		// rules apply to its parts.
+		if u.Type == "sqlite" {
+			return nil
+		}
+		if u.Name == "" {
+			const msg = "the URL must end with the database name, e.g. /site"
+			return invalid("validate.db_url", map[string]string{"error": msg}, "Invalid connection URL: "+msg)
+		}
+		if err := validateRule(u.Name, ValidateDBIdentifier); err != nil {
+			return err
+		}
+		if u.User != "" {
+			return validateRule(u.User, ValidateDBUser)
+		}
+	case ValidateTablePrefix:
+		if !tablePrefixRe.MatchString(value) {
+			return invalid("validate.table_prefix", nil, "Table prefix must start with a letter and may only contain letters, digits and '_', and end with '_' (up to 20 characters).")
+		}
+	case ValidateAdminDir:
+		if !adminDirRe.MatchString(value) {
+			return invalid("validate.admin_dir", nil, "Admin directory may only contain letters, digits, '_' and '-' (up to 64 characters).")
+		}
+		for _, reserved := range reservedAdminDirs {
+			if strings.EqualFold(value, reserved) {
+				return invalid("validate.admin_dir_reserved", map[string]string{"dir": value},
+					fmt.Sprintf("'%s' is a reserved Evolution CMS directory; choose another admin directory.", value))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package install
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// backupSkipDirs are project directories left out of the files archive:
+// Composer and npm rebuild them.
+var backupSkipDirs = []string{"core/vendor", "vendor", "node_modules"}
+
+// formatSize renders a byte count compactly: 512 B, 12.3 KB, 4.0 MB.
+func formatSize(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// backupBeforeForce backs up the current database and project files before a
+// --force reinstall may overwrite them. The backups go to plan.BackupDir,
+// which is empty with --no-backup, and are recorded in plan for the summary.
+// A failed backup stops the run.
+func (e *Engine) backupBeforeForce(ctx context.Context, emit func(domain.Event) bool, stepID string, plan *installPlan) bool {
+	if plan.BackupDir == "" {
+		return true
+	}
+	a := plan.Answers
+	files := projectHasFiles(plan.WorkDir)
+	if len(a.DBTables) == 0 && !files {
+		return true
+	}
+	log := func(ev domain.EventType, sev domain.Severity, msg, key string, params, fields map[string]string) {
+		_ = emit(domain.Event{
+			Type:     ev,
+			StepID:   stepID,
+			Source:   "install",
+			Severity: sev,
+			Payload:  domain.LogPayload{Message: msg, Key: key, Params: params, Fields: fields},
+		})
+	}
+	fail := func(err error) bool {
+		log(domain.EventError, domain.SeverityError, "Backup failed: "+err.Error()+". Re-run with --no-backup to install without a backup.",
+			"log.backup_failed", map[string]string{"error": err.Error()}, nil)
+		return false
+	}
+
+	dir := plan.BackupDir
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fail(err)
+	}
+	stamp := time.Now().Format("20060102-150405")
+	done := func(file string, size int64) {
+		plan.Backups = append(plan.Backups, file)
+		log(domain.EventLog, domain.SeverityInfo, fmt.Sprintf("✔ Backup written to %s (%s).", file, formatSize(size)),
+			"log.backup_done", map[string]string{"file": file, "size": formatSize(size)}, map[string]string{"op": "replace_last"})
+	}
+
+	if len(a.DBTables) > 0 {
+		log(domain.EventLog, domain.SeverityInfo, fmt.Sprintf("Backing up database %s...", a.DBName),
+			"log.backup_db", map[string]string{"name": a.DBName}, nil)
+		file, err := e.dumpDatabase(ctx, emit, stepID, plan.WorkDir, a, dir, stamp)
+		if err != nil {
+			return fail(err)
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			return fail(err)
+		}
+		done(file, info.Size())
+		plan.DBBackups = append(plan.DBBackups, file)
+	}
+
+	if files {
+		file := filepath.Join(dir, fmt.Sprintf("%s-files-%s.tar.gz", filepath.Base(absDir(plan.WorkDir)), stamp))
+		log(domain.EventLog, domain.SeverityInfo, "Archiving project files...", "log.backup_files", nil, nil)
+		size, err := archiveProject(ctx, plan.WorkDir, file)
+		if err != nil {
+			_ = os.Remove(file)
+			return fail(err)
+		}
+		done(file, size)
+	}
+	return true
+}
+
+// projectHasFiles reports whether dir exists and is not empty.
+func projectHasFiles(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// dumpDatabase writes a full database backup to dir. SQLite files are copied;
+// the other drivers are dumped by dumpSQL.
+func (e *Engine) dumpDatabase(ctx context.Context, emit func(domain.Event) bool, stepID string, workDir string, a preflightAnswers, dir string, stamp string) (string, error) {
+	cfg := e.connectionConfig(a)
+	name := strings.TrimSuffix(filepath.Base(a.DBName), filepath.Ext(a.DBName))
+	if a.DBType == "sqlite" {
+		src := sqliteDatabasePath(workDir, a.DBName)
+		file := filepath.Join(dir, fmt.Sprintf("%s-%s%s", name, stamp, filepath.Ext(src)))
+		return file, copyFile(src, file, 0o600)
+	}
+
+	file := filepath.Join(dir, fmt.Sprintf("%s-%s.sql", name, stamp))
+	return file, e.dumpSQL(ctx, emit, stepID, workDir, cfg, nil, file, false)
+}
+
+// dumpSQL writes tables (every table when empty) of cfg to file as SQL. It
+// uses mysqldump or pg_dump when installed, and the PDO dumper otherwise or
+// when the tool fails. The PDO dumper writes no table structure for
+// PostgreSQL and SQL Server: when structure is set dumpSQL then fails rather
+// than leave a backup that cannot recreate the tables, otherwise it warns and
+// writes the data-only dump.
+func (e *Engine) dumpSQL(ctx context.Context, emit func(domain.Event) bool, stepID string, workDir string, cfg dbConfig, tables []string, file string, structure bool) error {
+	if cmd, cleanup, ok := nativeDumpCommand(ctx, cfg, tables, file); ok {
+		tool := filepath.Base(cmd.Path)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		started := time.Now()
+		runErr := cmd.Run()
+		traceExit(ctx, "exec", cmd, runErr, time.Since(started))
+		cleanup()
+		if runErr == nil {
+			return nil
+		}
+		_ = os.Remove(file)
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = runErr.Error()
+		}
+		if _, dataOnly := pdoDumpDataOnly(cfg); dataOnly && structure {
+			return fmt.Errorf("%s failed: %s", tool, msg)
+		}
+		_ = emit(domain.Event{
+			Type:     domain.EventWarning,
+			StepID:   stepID,
+			Source:   "install",
+			Severity: domain.SeverityWarn,
+			Payload: domain.LogPayload{
+				Message: fmt.Sprintf("%s failed (%s); falling back to the built-in dumper.", tool, msg),
+				Key:     "log.backup_tool_failed",
+				Params:  map[string]string{"tool": tool, "error": msg},
+			},
+		})
+	}
+
+	if msg, dataOnly := pdoDumpDataOnly(cfg); dataOnly {
+		if structure {
+			return errors.New("only a backup without the table structure is possible (" + msg + ")")
+		}
+		_ = emit(domain.Event{
+			Type:     domain.EventWarning,
+			StepID:   stepID,
+			Source:   "install",
+			Severity: domain.SeverityWarn,
+			Payload: domain.LogPayload{
+				Message: fmt.Sprintf("The database backup has no table structure (%s); it can only refill tables that already exist.", msg),
+				Key:     "log.backup_data_only",
+				Params:  map[string]string{"reason": msg},
+			},
+		})
+	}
+	run := runDBTablesScript
+	if e.dbTables != nil {
+		run = e.dbTables
+	}
+	return run(ctx, workDir, dbTablesRequest{dbConfig: cfg, Tables: tables, File: file})
+}
+
+// pdoDumpDataOnly reports whether the PDO dumper would leave out the table
+// structure for cfg, with the reason no native dump is possible.
+func pdoDumpDataOnly(cfg dbConfig) (string, bool) {
+	if cfg.Type != "pgsql" && cfg.Type != "sqlsrv" {
+		return "", false
+	}
+	msg, _ := structureDumpMissing(cfg)
+	if msg == "" {
+		msg = "pg_dump could not be started"
+	}
+	return msg, true
+}
+
+// sqliteDatabasePath resolves the SQLite file like evo_sqlite_path does:
+// relative names live in core/database of the project.
+func sqliteDatabasePath(workDir string, name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(workDir, "core", "database", filepath.Base(filepath.FromSlash(name)))
+}
+
+// pgEnvOptions maps DSN options to the libpq environment pg_dump reads.
+var pgEnvOptions = map[string]string{
+	"sslmode":          "PGSSLMODE",
+	"sslrootcert":      "PGSSLROOTCERT",
+	"sslcert":          "PGSSLCERT",
+	"sslkey":           "PGSSLKEY",
+	"connect_timeout":  "PGCONNECT_TIMEOUT",
+	"application_name": "PGAPPNAME",
+}
+
+// structureDumpMissing explains why a backup of cfg cannot include the
+// table structure, or returns "" when it can: the PDO dumper writes it for
+// MySQL and SQLite, PostgreSQL needs pg_dump, and SQL Server tables are only
+// dumped as data.
+func structureDumpMissing(cfg dbConfig) (msg string, key string) {
+	switch cfg.Type {
+	case "pgsql":
+		if _, err := exec.LookPath("pg_dump"); err != nil {
+			return "pg_dump is not installed", "reason.backup_needs_pg_dump"
+		}
+		if _, ok := pgDumpEnv(cfg); !ok {
+			return "pg_dump cannot use the --db-options settings", "reason.backup_pg_dump_options"
+		}
+	case "sqlsrv":
+		return "SQL Server tables can only be backed up without their structure", "reason.backup_sqlsrv_data_only"
+	}
+	return "", ""
+}
+
+// pgDumpEnv is the libpq environment for pg_dump. It reports false when a
+// DSN option has no environment equivalent.
+func pgDumpEnv(cfg dbConfig) ([]string, bool) {
+	env := []string{"PGPASSWORD=" + cfg.Password}
+	for k, v := range cfg.Options {
+		name, known := pgEnvOptions[k]
+		if !known {
+			return nil, false
+		}
+		env = append(env, name+"="+v)
+	}
+	return env, true
+}
+
+// nativeDumpCommand prepares mysqldump or pg_dump for tables (every table
+// when empty) of cfg. It reports false when the tool is not installed or
+// cannot express the connection settings (MySQL TLS, unknown DSN options);
+// the PDO dumper handles those. cleanup removes the temporary credentials
+// file.
+func nativeDumpCommand(ctx context.Context, cfg dbConfig, tables []string, file string) (cmd *exec.Cmd, cleanup func(), ok bool) {
+	noop := func() {}
+	switch cfg.Type {
+	case "mysql":
+		bin, err := exec.LookPath("mysqldump")
+		if err != nil {
+			if bin, err = exec.LookPath("mariadb-dump"); err != nil {
+				return nil, noop, false
+			}
+		}
+		if cfg.MySQLSSL != nil {
+			return nil, noop, false
+		}
+		args := []string{}
+		for k, v := range cfg.Options {
+			if k != "unix_socket" {
+				return nil, noop, false
+			}
+			args = append(args, "--socket="+v)
+		}
+		// The password goes through an option file so it never shows up
+		// in the process list.
+		cnf, err := os.CreateTemp("", "evo-mysqldump-*.cnf")
+		if err != nil {
+			return nil, noop, false
+		}
+		cleanup = func() { _ = os.Remove(cnf.Name()) }
+		password := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(cfg.Password)
+		_, werr := fmt.Fprintf(cnf, "[client]\npassword=\"%s\"\n", password)
+		if cerr := cnf.Close(); werr != nil || cerr != nil {
+			cleanup()
+			return nil, noop, false
+		}
+		args = append([]string{"--defaults-extra-file=" + cnf.Name()}, args...)
+		args = append(args,
+			"--single-transaction", "--routines", "--triggers", "--no-tablespaces",
+			"--default-character-set=utf8mb4",
+			"--host="+cfg.Host,
+			"--user="+cfg.User,
+			"--result-file="+file,
+		)
+		if cfg.Port > 0 {
+			args = append(args, "--port="+strconv.Itoa(cfg.Port))
+		}
+		args = append(args, cfg.Name)
+		args = append(args, tables...)
+		cmd = exec.CommandContext(ctx, bin, args...)
+		traceExec(ctx, "exec", cmd.Args, "", nil)
+		return cmd, cleanup, true
+	case "pgsql":
+		bin, err := exec.LookPath("pg_dump")
+		if err != nil {
+			return nil, noop, false
+		}
+		env, known := pgDumpEnv(cfg)
+		if !known {
+			return nil, noop, false
+		}
+		args := []string{"--no-password", "--format=plain", "--no-owner", "--file=" + file, "--host=" + cfg.Host, "--username=" + cfg.User}
+		if cfg.Port > 0 {
+			args = append(args, "--port="+strconv.Itoa(cfg.Port))
+		}
+		for _, t := range tables {
+			// Quoted, the name matches exactly: no case folding or wildcards.
+			args = append(args, `--table="`+strings.ReplaceAll(t, `"`, `""`)+`"`)
+		}
+		args = append(args, cfg.Name)
+		cmd = exec.CommandContext(ctx, bin, args...)
+		cmd.Env = append(os.Environ(), env...)
+		traceExec(ctx, "exec", cmd.Args, "", env)
+		return cmd, noop, true
+	}
+	return nil, noop, false
+}
+
+// archiveProject writes dir as a gzipped tarball to file, leaving out
+// backupSkipDirs, and returns the archive size.
+func archiveProject(ctx context.Context, dir string, file string) (int64, error) {
+	root := absDir(dir)
+	out, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	base := filepath.Base(root)
+
+	walkErr := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		for _, skip := range backupSkipDirs {
+			if d.IsDir() && rel == skip {
+				return filepath.SkipDir
+			}
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		link := ""
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		case !info.Mode().IsRegular() && !info.IsDir():
+			// Sockets, pipes and devices are not project files.
+			return nil
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = base
+		if rel != "." {
+			hdr.Name += "/" + rel
+		}
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if walkErr != nil {
+		return 0, walkErr
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+	info, err := out.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
@@ -0,0 +1,147 @@
+package install
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestBackupBeforeForceCopiesDatabaseAndArchivesProject(t *testing.T) {
+	t.Parallel()
+
+	workDir := filepath.Join(t.TempDir(), "site")
+	for path, data := range map[string]string{
+		"core/database/database.sqlite": "sqlite data",
+		"core/custom/config.php":        "<?php return [];",
+		"core/vendor/autoload.php":      "<?php",
+		"index.php":                     "<?php",
+	} {
+		full := filepath.Join(workDir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e := &Engine{opt: Options{Force: true}}
+	plan := installPlan{
+		WorkDir:   workDir,
+		BackupDir: backupDir(workDir),
+		Answers:   preflightAnswers{DBType: "sqlite", DBName: "database.sqlite", DBTables: []string{"evo_site_content"}},
+	}
+	if !e.backupBeforeForce(context.Background(), func(domain.Event) bool { return true }, reviewStepID, &plan) {
+		t.Fatalf("backupBeforeForce failed")
+	}
+	if len(plan.Backups) != 2 || filepath.Dir(plan.Backups[0]) != workDir+"-backups" {
+		t.Fatalf("backups = %q", plan.Backups)
+	}
+	if len(plan.DBBackups) != 1 || plan.DBBackups[0] != plan.Backups[0] {
+		t.Fatalf("database backups = %q, want %q", plan.DBBackups, plan.Backups[:1])
+	}
+	if raw, err := os.ReadFile(plan.Backups[0]); err != nil || string(raw) != "sqlite data" {
+		t.Fatalf("database copy = %q, %v", raw, err)
+	}
+
+	f, err := os.Open(plan.Backups[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, hdr.Name)
+	}
+	got := strings.Join(names, " ")
+	if !strings.Contains(got, "site/core/custom/config.php") || !strings.Contains(got, "site/index.php") {
+		t.Fatalf("archive = %s", got)
+	}
+	if strings.Contains(got, "vendor") {
+		t.Fatalf("archive includes core/vendor: %s", got)
+	}
+
+	skipped := installPlan{WorkDir: workDir, Answers: plan.Answers}
+	if !e.backupBeforeForce(context.Background(), func(domain.Event) bool { return true }, reviewStepID, &skipped) || len(skipped.Backups) != 0 {
+		t.Fatalf("--no-backup still wrote %q", skipped.Backups)
+	}
+}
+
+func TestBackupBeforeForceWritesDataOnlySQLServerDump(t *testing.T) {
+	t.Parallel()
+
+	var reqs []dbTablesRequest
+	var dumpErr error
+	e := &Engine{opt: Options{Force: true}, dbTables: func(_ context.Context, _ string, req dbTablesRequest) error {
+		reqs = append(reqs, req)
+		if dumpErr != nil {
+			return dumpErr
+		}
+		return os.WriteFile(req.File, []byte("-- Structure not included\n"), 0o600)
+	}}
+	workDir := filepath.Join(t.TempDir(), "site")
+	answers := preflightAnswers{DBType: "sqlsrv", DBName: "evo", DBTables: []string{"evo_site_content"}}
+	var keys []string
+	emit := func(ev domain.Event) bool {
+		if p, ok := ev.Payload.(domain.LogPayload); ok {
+			keys = append(keys, p.Key)
+		}
+		return true
+	}
+
+	plan := installPlan{WorkDir: workDir, BackupDir: backupDir(workDir), Answers: answers}
+	if !e.backupBeforeForce(context.Background(), emit, reviewStepID, &plan) {
+		t.Fatalf("backupBeforeForce failed, log keys %q", keys)
+	}
+	if len(reqs) != 1 || reqs[0].Drop || reqs[0].File == "" {
+		t.Fatalf("requests = %+v, want one dump", reqs)
+	}
+	if len(plan.DBBackups) != 1 || plan.DBBackups[0] != reqs[0].File {
+		t.Fatalf("database backups = %q, want %q", plan.DBBackups, reqs[0].File)
+	}
+	if !slices.Contains(keys, "log.backup_data_only") {
+		t.Fatalf("log keys = %q, want a log.backup_data_only warning", keys)
+	}
+
+	// Only a dump that cannot be written stops the installation.
+	dumpErr = errors.New("login failed")
+	keys = nil
+	failed := installPlan{WorkDir: workDir, BackupDir: backupDir(workDir), Answers: answers}
+	if e.backupBeforeForce(context.Background(), emit, reviewStepID, &failed) || len(failed.Backups) != 0 {
+		t.Fatalf("failed dump went ahead: backups = %q", failed.Backups)
+	}
+	if last := keys[len(keys)-1]; last != "log.backup_failed" {
+		t.Fatalf("last log key = %q, want %q", last, "log.backup_failed")
+	}
+}
+
+func TestFormatSize(t *testing.T) {
+	t.Parallel()
+
+	for n, want := range map[int64]string{512: "512 B", 1536: "1.5 KB", 5 << 20: "5.0 MB"} {
+		if got := formatSize(n); got != want {
+			t.Fatalf("formatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
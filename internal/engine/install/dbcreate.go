@@ -0,0 +1,329 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// dbScriptFunctions are PHP helpers shared by the database scripts: PDO
+// attributes and DSN options from the config, connecting to the configured
+// database and listing its tables, and the detection of a missing database
+// (MySQL 1049, PostgreSQL 3D000, SQL Server 4060) together with whether the
+// user may create it.
+const dbScriptFunctions = `
+function evo_db_attributes(array $cfg): array {
+  $attrs = [\PDO::ATTR_TIMEOUT => 5];
+  if (($cfg["type"] ?? "") === "mysql" && is_array($cfg["mysql_ssl"] ?? null)) {
+    $ssl = $cfg["mysql_ssl"];
+    $ca = $ssl["ca"] ?? "";
+    if ($ca !== "") {
+      $attrs[\PDO::MYSQL_ATTR_SSL_CA] = $ca;
+    } else {
+      // pdo_mysql only enables TLS when a CA, CA path, certificate or key is
+      // set; same system CA fallback as the installer's defaultCaPath().
+      $dir = function_exists("openssl_get_cert_locations") ? (openssl_get_cert_locations()["default_cert_dir"] ?? "") : "";
+      $attrs[\PDO::MYSQL_ATTR_SSL_CAPATH] = is_string($dir) && $dir !== "" ? $dir : "/etc/ssl/certs";
+    }
+    if (($ssl["cert"] ?? "") !== "") { $attrs[\PDO::MYSQL_ATTR_SSL_CERT] = $ssl["cert"]; }
+    if (($ssl["key"] ?? "") !== "") { $attrs[\PDO::MYSQL_ATTR_SSL_KEY] = $ssl["key"]; }
+    $attrs[\PDO::MYSQL_ATTR_SSL_VERIFY_SERVER_CERT] = (bool)($ssl["verify"] ?? false);
+  }
+  return $attrs;
+}
+function evo_db_extra(array $cfg): string {
+  $extra = "";
+  foreach (($cfg["options"] ?? []) as $k => $v) { $extra .= ";{$k}={$v}"; }
+  return $extra;
+}
+function evo_sqlite_path(string $name): string {
+  // Relative names live in core/database, where the installer puts the file.
+  if ($name !== ":memory:" && !str_starts_with($name, "file:") && !preg_match('/^(\/|[A-Za-z]:[\\\\\\/])/', $name)) {
+    return "core/database/" . basename(str_replace("\\", "/", $name));
+  }
+  return $name;
+}
+function evo_db_connect(array $cfg): \PDO {
+  $type = $cfg["type"] ?? "mysql";
+  $host = $cfg["host"] ?? "localhost";
+  $port = (int)($cfg["port"] ?? 0);
+  $name = $cfg["name"] ?? "";
+  if ($type === "sqlite") {
+    return new \PDO("sqlite:".evo_sqlite_path($name), null, null, evo_db_attributes($cfg));
+  }
+  $dsn = match($type) {
+    "pgsql" => $port > 0 ? "pgsql:host={$host};port={$port};dbname={$name}" : "pgsql:host={$host};dbname={$name}",
+    "sqlsrv" => $port > 0 ? "sqlsrv:Server={$host},{$port};Database={$name}" : "sqlsrv:Server={$host};Database={$name}",
+    default => "mysql:host={$host};port={$port};dbname={$name};charset=utf8mb4",
+  };
+  return new \PDO($dsn.evo_db_extra($cfg), $cfg["user"] ?? "", $cfg["password"] ?? "", evo_db_attributes($cfg));
+}
+function evo_db_tables(string $type, \PDO $dbh): array {
+  $sql = match($type) {
+    "mysql" => "SHOW FULL TABLES WHERE Table_type = 'BASE TABLE'",
+    "pgsql" => "SELECT tablename FROM pg_tables WHERE schemaname = current_schema()",
+    "sqlsrv" => "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE'",
+    default => "SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%'",
+  };
+  $tables = $dbh->query($sql)->fetchAll(\PDO::FETCH_COLUMN, 0);
+  sort($tables);
+  return $tables;
+}
+function evo_db_quote_ident(string $type, string $name): string {
+  return match($type) {
+    "mysql" => chr(96) . str_replace(chr(96), chr(96).chr(96), $name) . chr(96),
+    "sqlsrv" => "[" . str_replace("]", "]]", $name) . "]",
+    default => '"' . str_replace('"', '""', $name) . '"',
+  };
+}
+function evo_db_missing(string $type, \Throwable $e): bool {
+  $msg = $e->getMessage();
+  $code = (string)$e->getCode();
+  $info = $e instanceof \PDOException && is_array($e->errorInfo) ? $e->errorInfo : [];
+  $driverCode = (int)($info[1] ?? 0);
+  return match($type) {
+    "mysql" => $code === "1049" || $driverCode === 1049 || str_contains($msg, "Unknown database"),
+    "pgsql" => $code === "3D000" || str_contains($msg, "3D000") || (bool)preg_match('/database ".*" does not exist/', $msg),
+    "sqlsrv" => $driverCode === 4060 || str_contains($msg, "4060") || str_contains($msg, "Cannot open database"),
+    default => false,
+  };
+}
+function evo_db_can_create(string $type, ?\PDO $dbh, string $name): array {
+  if (!$dbh) { return [false, "no server-level connection to check privileges"]; }
+  try {
+    if ($type === "pgsql") {
+      $ok = (bool)$dbh->query("SELECT rolcreatedb OR rolsuper FROM pg_roles WHERE rolname = current_user")->fetchColumn();
+      return [$ok, $ok ? "" : "the role has no CREATEDB privilege"];
+    }
+    if ($type === "sqlsrv") {
+      $ok = (int)$dbh->query("SELECT HAS_PERMS_BY_NAME(NULL, NULL, 'CREATE ANY DATABASE')")->fetchColumn() === 1;
+      return [$ok, $ok ? "" : "the login has no CREATE ANY DATABASE permission"];
+    }
+    $unknown = false;
+    foreach ($dbh->query("SHOW GRANTS FOR CURRENT_USER()")->fetchAll(\PDO::FETCH_COLUMN) as $grant) {
+      if (!preg_match('/^GRANT (.+?) ON (\S+)\.\* TO /i', $grant, $m)) {
+        // Role grants (MySQL 8) are not resolved here; CREATE DATABASE decides.
+        if (preg_match('/^GRANT /i', $grant) && !preg_match('/ ON /i', $grant)) { $unknown = true; }
+        continue;
+      }
+      $privs = array_map(fn($p) => strtoupper(trim($p)), explode(",", $m[1]));
+      if (!in_array("ALL PRIVILEGES", $privs, true) && !in_array("CREATE", $privs, true)) { continue; }
+      $scope = trim($m[2], chr(96));
+      if ($scope === "*") { return [true, ""]; }
+      $re = "";
+      for ($i = 0, $n = strlen($scope); $i < $n; $i++) {
+        $c = $scope[$i];
+        if ($c === "\\" && $i + 1 < $n) { $re .= preg_quote($scope[++$i], "/"); continue; }
+        $re .= $c === "%" ? ".*" : ($c === "_" ? "." : preg_quote($c, "/"));
+      }
+      if (preg_match("/^{$re}\$/i", $name)) { return [true, ""]; }
+    }
+    return [$unknown, $unknown ? "" : "the user has no CREATE privilege for this database"];
+  } catch (\Throwable $e) {
+    // Privileges could not be read; CREATE DATABASE decides.
+    return [true, ""];
+  }
+}
+function evo_db_report_ok(string $type, ?\PDO $dbh): void {
+  $tables = [];
+  try { if ($dbh) { $tables = evo_db_tables($type, $dbh); } } catch (\Throwable $ignored) {}
+  echo json_encode(["ok"=>true,"tables"=>$tables]);
+  exit(0);
+}
+function evo_db_report_missing(string $type, ?\PDO $dbh, string $name, \Throwable $e): void {
+  [$can, $why] = evo_db_can_create($type, $dbh, $name);
+  $version = "";
+  try { if ($dbh) { $version = (string)$dbh->getAttribute(\PDO::ATTR_SERVER_VERSION); } } catch (\Throwable $ignored) {}
+  echo json_encode(["ok"=>false,"error"=>$e->getMessage(),"missing_db"=>true,"can_create"=>$can,"grant_error"=>$why,"server_version"=>$version]);
+  exit(0);
+}
+`
+
+// dbCreateScript creates the configured database. The name has passed
+// ValidateDBIdentifier; the collation is MySQL only.
+const dbCreateScript = `
+$cfg = json_decode(base64_decode($argv[1] ?? ''), true);
+if (!is_array($cfg)) { echo json_encode(["ok"=>false,"error"=>"Invalid config"]); exit(0); }
+$type = $cfg["type"] ?? "mysql";
+` + dbScriptFunctions + `
+try {
+  $host = $cfg["host"] ?? "localhost";
+  $port = (int)($cfg["port"] ?? 0);
+  $name = $cfg["name"] ?? "";
+  $user = $cfg["user"] ?? "";
+  $pass = $cfg["password"] ?? "";
+  $attrs = evo_db_attributes($cfg);
+  $extra = evo_db_extra($cfg);
+  if (!preg_match('/^[A-Za-z0-9_$-]+$/', $name)) { throw new \Exception("Invalid database name."); }
+  if ($type === "pgsql") {
+    $dbh = null;
+    $err = null;
+    foreach (["postgres", "template1"] as $db) {
+      try {
+        $dsn = $port > 0 ? "pgsql:host={$host};port={$port};dbname={$db}" : "pgsql:host={$host};dbname={$db}";
+        $dbh = new \PDO($dsn.$extra, $user, $pass, $attrs);
+        break;
+      } catch (\Throwable $e) {
+        $err = $e;
+      }
+    }
+    if (!$dbh) { throw $err ?? new \Exception("PostgreSQL connection failed."); }
+    $dbh->exec("CREATE DATABASE " . evo_db_quote_ident($type, $name) . " ENCODING 'UTF8'");
+  } elseif ($type === "sqlsrv") {
+    $dsn = $port > 0 ? "sqlsrv:Server={$host},{$port}" : "sqlsrv:Server={$host}";
+    $dbh = new \PDO($dsn.$extra, $user, $pass, $attrs);
+    $dbh->exec("CREATE DATABASE " . evo_db_quote_ident($type, $name));
+  } else {
+    $collation = (string)($cfg["collation"] ?? "");
+    if (!preg_match('/^[A-Za-z0-9]+_[A-Za-z0-9_]+$/', $collation)) { $collation = "utf8mb4_unicode_520_ci"; }
+    $charset = explode("_", $collation)[0];
+    $dbh = new \PDO("mysql:host={$host};port={$port}".$extra, $user, $pass, $attrs);
+    $dbh->exec("CREATE DATABASE " . evo_db_quote_ident($type, $name) . " CHARACTER SET {$charset} COLLATE {$collation}");
+  }
+  echo json_encode(["ok"=>true]);
+} catch (\Throwable $e) {
+  echo json_encode(["ok"=>false,"error"=>$e->getMessage()]);
+}
+`
+
+func createDatabase(ctx context.Context, workDir string, cfg dbConfig, collation string) error {
+	payload := struct {
+		dbConfig
+		Collation string `json:"collation,omitempty"`
+	}{cfg, collation}
+	var res dbTestResult
+	if err := runDBScript(ctx, workDir, dbCreateScript, "<db create>", payload, &res); err != nil {
+		return err
+	}
+	if !res.OK {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+var mysqlVersionRe = regexp.MustCompile(`^(\d+)\.\d+`)
+
+// mysqlCollationOptions lists the utf8mb4 collations offered for a new MySQL
+// database, the recommended one first. The recommendation follows
+// HandlesCollations::getRecommendedCollation in the PHP installer.
+func mysqlCollationOptions(serverVersion string) []domain.QuestionOption {
+	recommended := "utf8mb4_unicode_520_ci"
+	if m := mysqlVersionRe.FindStringSubmatch(serverVersion); m != nil && !strings.Contains(strings.ToLower(serverVersion), "mariadb") {
+		if major, _ := strconv.Atoi(m[1]); major >= 8 {
+			recommended = "utf8mb4_0900_ai_ci"
+		}
+	}
+	opts := []domain.QuestionOption{{
+		ID:      recommended,
+		Label:   recommended + " (recommended)",
+		Enabled: true,
+		Key:     "option.db_collation.recommended",
+		Params:  map[string]string{"collation": recommended},
+	}}
+	for _, c := range []string{"utf8mb4_unicode_520_ci", "utf8mb4_unicode_ci", "utf8mb4_general_ci", "utf8mb4_bin"} {
+		if c != recommended {
+			opts = append(opts, domain.QuestionOption{ID: c, Label: c, Enabled: true})
+		}
+	}
+	return opts
+}
+
+// createDBOutcome is how offerCreateDatabase left a missing database.
+type createDBOutcome int
+
+const (
+	// createDBFailed: not created; the usual retry-or-exit question follows.
+	createDBFailed createDBOutcome = iota
+	createDBDone
+	// createDBEdit: the user wants to edit the database settings.
+	createDBEdit
+	// createDBStop: the user exited or the run was cancelled.
+	createDBStop
+)
+
+// offerCreateDatabase handles a connection test that found the server but
+// not the database: it reports missing privileges, or creates the database
+// after asking (--db-create answers yes). MySQL also asks for the collation.
+func (e *Engine) offerCreateDatabase(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, stepID string, workDir string, a *preflightAnswers, cfg dbConfig, res dbTestResult) createDBOutcome {
+	log := func(ev domain.EventType, sev domain.Severity, msg, key string, params, fields map[string]string) {
+		_ = emit(domain.Event{
+			Type:     ev,
+			StepID:   stepID,
+			Source:   "install",
+			Severity: sev,
+			Payload:  domain.LogPayload{Message: msg, Key: key, Params: params, Fields: fields},
+		})
+	}
+
+	if !res.CanCreate {
+		reason := strings.TrimSpace(res.GrantError)
+		if reason == "" {
+			reason = "missing privileges"
+		}
+		log(domain.EventWarning, domain.SeverityWarn,
+			fmt.Sprintf("Database %s does not exist and user %s cannot create it: %s. Create the database or grant the privilege, then try again.", a.DBName, a.DBUser, reason),
+			"log.db_create_denied", map[string]string{"name": a.DBName, "user": a.DBUser, "reason": reason}, nil)
+		return createDBFailed
+	}
+
+	if !e.opt.DBCreate {
+		choice, ok := askSelect(ctx, emit, actions, stepID, domain.QuestionState{
+			Active: true,
+			ID:     "db_create",
+			Kind:   domain.QuestionSelect,
+			Prompt: fmt.Sprintf("Database %s does not exist. Create it now?", a.DBName),
+			Params: map[string]string{"name": a.DBName},
+			Options: []domain.QuestionOption{
+				{ID: "create", Label: "Create the database", Enabled: true, Key: "option.db_create.create"},
+				{ID: "edit", Label: "Edit database settings", Enabled: true, Key: "option.db_create.edit"},
+				{ID: "exit", Label: "Exit installation", Enabled: true, Key: "option.db_create.exit"},
+			},
+		})
+		if !ok {
+			return createDBStop
+		}
+		switch choice {
+		case "edit":
+			return createDBEdit
+		case "exit":
+			emitCancelledByUser(emit, stepID)
+			return createDBStop
+		}
+		if a.DBType == "mysql" && a.DBCollation == "" {
+			collation, ok := askSelect(ctx, emit, actions, stepID, domain.QuestionState{
+				Active:  true,
+				ID:      "db_collation",
+				Kind:    domain.QuestionSelect,
+				Prompt:  "Which collation should the new database use?",
+				Options: mysqlCollationOptions(res.ServerVersion),
+			})
+			if !ok {
+				return createDBStop
+			}
+			a.DBCollation = collation
+		}
+	}
+	if a.DBType == "mysql" && a.DBCollation == "" {
+		a.DBCollation = mysqlCollationOptions(res.ServerVersion)[0].ID
+	}
+
+	params := map[string]string{"name": a.DBName}
+	log(domain.EventLog, domain.SeverityInfo, fmt.Sprintf("Creating database %s...", a.DBName), "log.db_creating", params, nil)
+	createDB := createDatabase
+	if e.createDB != nil {
+		createDB = e.createDB
+	}
+	if err := createDB(ctx, workDir, cfg, a.DBCollation); err != nil {
+		log(domain.EventWarning, domain.SeverityWarn, fmt.Sprintf("Could not create database %s: %v", a.DBName, err),
+			"log.db_create_failed", map[string]string{"name": a.DBName, "error": err.Error()}, nil)
+		return createDBFailed
+	}
+	log(domain.EventLog, domain.SeverityInfo, fmt.Sprintf("✔ Database %s created.", a.DBName), "log.db_created", params,
+		map[string]string{"op": "replace_last"})
+	return createDBDone
+}
@@ -0,0 +1,433 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// DefaultTablePrefix is the table prefix of a new installation.
+const DefaultTablePrefix = "evo_"
+
+// ExistingTablesActions are the accepted --db-existing values: what to do
+// when tables with the chosen prefix already exist. A different prefix is
+// chosen with --db-table-prefix instead.
+var ExistingTablesActions = []string{"abort", "drop", "keep"}
+
+// evolutionMarkerTable identifies an Evolution CMS installation, as in
+// DetectsInstallType in the PHP installer.
+const evolutionMarkerTable = "site_content"
+
+// evolutionTablePrefixes returns the prefixes of the Evolution CMS
+// installations found among tables.
+func evolutionTablePrefixes(tables []string) []string {
+	var prefixes []string
+	for _, t := range tables {
+		if len(t) < len(evolutionMarkerTable) || !strings.EqualFold(t[len(t)-len(evolutionMarkerTable):], evolutionMarkerTable) {
+			continue
+		}
+		if p := t[:len(t)-len(evolutionMarkerTable)]; !slices.Contains(prefixes, p) {
+			prefixes = append(prefixes, p)
+		}
+	}
+	slices.Sort(prefixes)
+	return prefixes
+}
+
+// tablesWithPrefix returns the tables an installation with prefix would
+// collide with. Prefixes compare case-insensitively, as MySQL on Windows and
+// macOS does.
+func tablesWithPrefix(tables []string, prefix string) []string {
+	var out []string
+	for _, t := range tables {
+		if strings.HasPrefix(strings.ToLower(t), strings.ToLower(prefix)) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// suggestTablePrefix proposes a free prefix based on prefix: evo2_, evo3_...
+func suggestTablePrefix(tables []string, prefix string) string {
+	base := strings.TrimRight(prefix, "_")
+	if base == "" {
+		base = strings.TrimRight(DefaultTablePrefix, "_")
+	}
+	for n := 2; ; n++ {
+		candidate := base + strconv.Itoa(n) + "_"
+		if len(tablesWithPrefix(tables, candidate)) == 0 {
+			return candidate
+		}
+	}
+}
+
+// checkExistingTables looks at the tables of a database that passed the
+// connection test. Tables with other prefixes are only reported; when tables
+// with the chosen prefix exist, the user (or --db-existing) decides between
+// exiting, another prefix, backing them up and dropping them, or keeping an
+// Evolution CMS installation to update it. Dropping waits until the review
+// screen is confirmed.
+func (e *Engine) checkExistingTables(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, stepID string, a *preflightAnswers, tables []string) (ok bool, retry bool) {
+	a.DBTables, a.DBExisting, a.DBDropTables = tables, "", nil
+	if len(tables) == 0 {
+		return true, false
+	}
+	log := func(ev domain.EventType, sev domain.Severity, msg, key string, params map[string]string) {
+		_ = emit(domain.Event{
+			Type:     ev,
+			StepID:   stepID,
+			Source:   "install",
+			Severity: sev,
+			Payload:  domain.LogPayload{Message: msg, Key: key, Params: params},
+		})
+	}
+
+	count := strconv.Itoa(len(tables))
+	log(domain.EventLog, domain.SeverityInfo, fmt.Sprintf("Database %s already contains %s tables.", a.DBName, count),
+		"log.db_tables_found", map[string]string{"name": a.DBName, "count": count})
+	evoPrefixes := evolutionTablePrefixes(tables)
+	if len(evoPrefixes) > 0 {
+		shown := make([]string, len(evoPrefixes))
+		for i, p := range evoPrefixes {
+			shown[i] = strconv.Quote(p)
+		}
+		prefixes := strings.Join(shown, ", ")
+		log(domain.EventLog, domain.SeverityInfo, "Evolution CMS tables found with prefix "+prefixes+".",
+			"log.db_tables_evolution", map[string]string{"prefixes": prefixes})
+	}
+
+	conflicting := tablesWithPrefix(tables, a.DBTablePrefix)
+	if len(conflicting) == 0 {
+		log(domain.EventLog, domain.SeverityInfo, fmt.Sprintf("No table uses the prefix %s; the existing tables are left untouched.", a.DBTablePrefix),
+			"log.db_tables_untouched", map[string]string{"prefix": a.DBTablePrefix})
+		return true, false
+	}
+	isEvolution := slices.ContainsFunc(evoPrefixes, func(p string) bool { return strings.EqualFold(p, a.DBTablePrefix) })
+
+	choice := e.opt.DBExisting
+	if choice == "keep" && !isEvolution {
+		log(domain.EventWarning, domain.SeverityWarn,
+			fmt.Sprintf("--db-existing=keep only applies to an Evolution CMS installation, but the tables with prefix %s are not one.", a.DBTablePrefix),
+			"log.db_existing_keep_ignored", map[string]string{"prefix": a.DBTablePrefix})
+		choice = ""
+	}
+	// Tables are only dropped with a backup that can recreate them.
+	noBackup, noBackupKey := structureDumpMissing(e.connectionConfig(*a))
+	if choice == "drop" && noBackup != "" {
+		log(domain.EventWarning, domain.SeverityWarn,
+			fmt.Sprintf("--db-existing=drop needs a backup with the table structure, but %s.", noBackup),
+			"log.db_existing_drop_ignored", map[string]string{"reason": noBackup})
+		choice = ""
+	}
+	params := map[string]string{"count": strconv.Itoa(len(conflicting)), "prefix": a.DBTablePrefix}
+	for {
+		if choice == "" {
+			opts := []domain.QuestionOption{
+				{ID: "abort", Label: "Exit installation", Enabled: true, Key: "option.db_existing.abort"},
+				{ID: "prefix", Label: "Use a different table prefix", Enabled: true, Key: "option.db_existing.prefix"},
+				{ID: "drop", Label: "Back up and drop these tables", Enabled: noBackup == "", Key: "option.db_existing.drop",
+					Reason: noBackup, ReasonKey: noBackupKey},
+			}
+			if isEvolution {
+				opts = append(opts, domain.QuestionOption{ID: "keep", Label: "Keep them and update the existing installation", Enabled: true, Key: "option.db_existing.keep"})
+			}
+			var answered bool
+			choice, answered = askSelect(ctx, emit, actions, stepID, domain.QuestionState{
+				Active:   true,
+				ID:       "db_existing",
+				Kind:     domain.QuestionSelect,
+				Prompt:   fmt.Sprintf("%d tables already use the prefix %s. What should the installer do?", len(conflicting), a.DBTablePrefix),
+				Params:   params,
+				Options:  opts,
+				Selected: 1,
+			})
+			if !answered {
+				return false, false
+			}
+		}
+
+		switch choice {
+		case "abort":
+			emitCancelledByUser(emit, stepID)
+			return false, false
+		case "prefix":
+			prefix, back, answered := askNewTablePrefix(ctx, emit, actions, stepID, tables, a.DBTablePrefix)
+			if !answered {
+				return false, false
+			}
+			if back {
+				choice = ""
+				continue
+			}
+			a.DBTablePrefix = prefix
+			log(domain.EventLog, domain.SeverityInfo, "Using table prefix "+prefix+".", "log.db_table_prefix", map[string]string{"prefix": prefix})
+		case "drop":
+			a.DBExisting, a.DBDropTables = "drop", conflicting
+			log(domain.EventLog, domain.SeverityInfo,
+				fmt.Sprintf("The %d tables with prefix %s will be backed up and dropped once the installation is confirmed.", len(conflicting), a.DBTablePrefix),
+				"log.db_tables_drop_planned", params)
+		case "keep":
+			a.DBExisting = "keep"
+			log(domain.EventLog, domain.SeverityInfo,
+				fmt.Sprintf("Keeping the existing tables; the Evolution CMS installation with prefix %s will be updated.", a.DBTablePrefix),
+				"log.db_tables_keep", params)
+		}
+		return true, false
+	}
+}
+
+// askNewTablePrefix asks for a valid prefix that no existing table uses.
+func askNewTablePrefix(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, stepID string, tables []string, current string) (prefix string, back bool, ok bool) {
+	q := domain.QuestionState{
+		Active:    true,
+		ID:        "db_table_prefix",
+		Kind:      domain.QuestionInput,
+		Prompt:    "Enter the new table prefix:",
+		Default:   suggestTablePrefix(tables, current),
+		Validate:  []domain.ValidationRule{domain.ValidateRequired, domain.ValidateTablePrefix},
+		CanGoBack: true,
+	}
+	for {
+		value, back, ok := askQuestion(ctx, emit, actions, stepID, q)
+		if !ok || back {
+			return "", back, ok
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			value = q.Default
+		}
+		q.Value = value
+		if err := domain.ValidateAnswer(value, q.Validate...); err != nil {
+			var verr *domain.ValidationError
+			if errors.As(err, &verr) {
+				q.Error, q.ErrorKey, q.ErrorParams = verr.Error(), verr.Key, verr.Params
+			}
+			continue
+		}
+		if len(tablesWithPrefix(tables, value)) > 0 {
+			q.Error = fmt.Sprintf("Tables with the prefix %s already exist; choose another prefix.", value)
+			q.ErrorKey, q.ErrorParams = "validate.table_prefix_taken", map[string]string{"prefix": value}
+			continue
+		}
+		return value, false, true
+	}
+}
+
+// dbTablesScript dumps tables to an SQL file and drops tables. The dump is
+// the PDO fallback when no native dump tool is available: MySQL and SQLite
+// dumps include the table structure, PostgreSQL and SQL Server dumps hold the
+// data only. Tables are dropped without CASCADE, so objects outside the list
+// that depend on them stop the drop.
+const dbTablesScript = `
+$req = json_decode(base64_decode($argv[1] ?? ''), true);
+if (!is_array($req)) { echo json_encode(["ok"=>false,"error"=>"Invalid config"]); exit(0); }
+$type = $req["type"] ?? "mysql";
+` + dbScriptFunctions + `
+function evo_db_dump(string $type, \PDO $dbh, array $tables, string $file): void {
+  $out = @fopen($file, "xb");
+  if (!$out) { throw new \Exception("Cannot create backup file {$file}"); }
+  @chmod($file, 0600);
+  fwrite($out, "-- Evolution CMS installer backup, " . date("c") . "\n-- Driver: {$type}\n\n");
+  if ($type === "mysql") { fwrite($out, "SET FOREIGN_KEY_CHECKS=0;\n\n"); }
+  foreach ($tables as $table) {
+    $q = evo_db_quote_ident($type, $table);
+    $ddl = null;
+    if ($type === "mysql") {
+      $ddl = $dbh->query("SHOW CREATE TABLE {$q}")->fetch(\PDO::FETCH_NUM)[1] ?? null;
+    } elseif ($type === "sqlite") {
+      $st = $dbh->prepare("SELECT sql FROM sqlite_master WHERE type = 'table' AND name = ?");
+      $st->execute([$table]);
+      $ddl = $st->fetchColumn() ?: null;
+    }
+    fwrite($out, "-- Table {$table}\n");
+    if ($ddl) {
+      fwrite($out, "DROP TABLE IF EXISTS {$q};\n{$ddl};\n");
+    } else {
+      fwrite($out, "-- Structure not included; create the table before loading the data.\n");
+    }
+    $rows = $dbh->query("SELECT * FROM {$q}");
+    while ($row = $rows->fetch(\PDO::FETCH_ASSOC)) {
+      $cols = [];
+      $vals = [];
+      foreach ($row as $col => $v) {
+        $cols[] = evo_db_quote_ident($type, (string)$col);
+        if (is_resource($v)) { $v = stream_get_contents($v); }
+        $vals[] = match(true) {
+          $v === null => "NULL",
+          is_bool($v) => $type === "pgsql" ? ($v ? "TRUE" : "FALSE") : ($v ? "1" : "0"),
+          is_int($v), is_float($v) => (string)$v,
+          default => $dbh->quote((string)$v),
+        };
+      }
+      fwrite($out, "INSERT INTO {$q} (" . implode(", ", $cols) . ") VALUES (" . implode(", ", $vals) . ");\n");
+    }
+    fwrite($out, "\n");
+  }
+  if ($type === "mysql") { fwrite($out, "SET FOREIGN_KEY_CHECKS=1;\n"); }
+  if (!fclose($out)) { throw new \Exception("Cannot write backup file {$file}"); }
+}
+function evo_db_drop(string $type, \PDO $dbh, array $tables): void {
+  if ($type === "mysql") { $dbh->exec("SET FOREIGN_KEY_CHECKS=0"); }
+  // PostgreSQL drops in one transaction, so a table that other objects
+  // depend on leaves every table in place; the savepoint keeps the
+  // transaction usable after a failed DROP.
+  $tx = $type === "pgsql";
+  if ($tx) { $dbh->beginTransaction(); }
+  try {
+    // A table referenced by another listed table only drops after it; retry
+    // until every table is gone or no progress is made.
+    $pending = $tables;
+    while ($pending) {
+      $failed = [];
+      $err = null;
+      foreach ($pending as $table) {
+        try {
+          if ($tx) { $dbh->exec("SAVEPOINT evo_drop"); }
+          $dbh->exec("DROP TABLE " . evo_db_quote_ident($type, $table));
+          if ($tx) { $dbh->exec("RELEASE SAVEPOINT evo_drop"); }
+        } catch (\Throwable $e) {
+          if ($tx) { $dbh->exec("ROLLBACK TO SAVEPOINT evo_drop"); }
+          $failed[] = $table;
+          $err = $e;
+        }
+      }
+      if ($err && count($failed) === count($pending)) {
+        $info = $err instanceof \PDOException && is_array($err->errorInfo) ? $err->errorInfo : [];
+        // 2BP01: PostgreSQL dependent objects; 3726: SQL Server foreign key.
+        if (($info[0] ?? "") === "2BP01" || (int)($info[1] ?? 0) === 3726) {
+          $kept = $tx ? " No table was dropped." : "";
+          throw new \Exception("Views or tables outside the selection depend on " . implode(", ", $failed) . "; remove them first or choose another table prefix." . $kept . " " . $err->getMessage());
+        }
+        throw $err;
+      }
+      $pending = $failed;
+    }
+    if ($tx) { $dbh->commit(); }
+  } catch (\Throwable $e) {
+    if ($tx && $dbh->inTransaction()) { $dbh->rollBack(); }
+    throw $e;
+  } finally {
+    if ($type === "mysql") { $dbh->exec("SET FOREIGN_KEY_CHECKS=1"); }
+  }
+}
+try {
+  $dbh = evo_db_connect($req);
+  $dbh->setAttribute(\PDO::ATTR_ERRMODE, \PDO::ERRMODE_EXCEPTION);
+  $tables = $req["tables"] ?? [];
+  if (!$tables) { $tables = evo_db_tables($type, $dbh); }
+  $file = (string)($req["file"] ?? "");
+  if ($file !== "") { evo_db_dump($type, $dbh, $tables, $file); }
+  // Only an explicit table list is ever dropped.
+  if (!empty($req["drop"]) && !empty($req["tables"])) { evo_db_drop($type, $dbh, $tables); }
+  echo json_encode(["ok"=>true]);
+} catch (\Throwable $e) {
+  echo json_encode(["ok"=>false,"error"=>$e->getMessage()]);
+}
+`
+
+// dbTablesRequest is the dbTablesScript payload.
+type dbTablesRequest struct {
+	dbConfig
+	// Tables limits the dump to these tables; empty dumps every table.
+	Tables []string `json:"tables,omitempty"`
+	// File receives the SQL dump; Drop drops the listed tables afterwards.
+	File string `json:"file,omitempty"`
+	Drop bool   `json:"drop,omitempty"`
+}
+
+func runDBTablesScript(ctx context.Context, workDir string, req dbTablesRequest) error {
+	var res dbTestResult
+	if err := runDBScript(ctx, workDir, dbTablesScript, "<db tables>", req, &res); err != nil {
+		return err
+	}
+	if !res.OK {
+		return errors.New(res.Error)
+	}
+	return nil
+}
+
+// backupDir holds the installer's backups. It sits next to the project
+// directory, so reinstalling or removing the project keeps them.
+func backupDir(workDir string) string {
+	dir := absDir(workDir)
+	return filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-backups")
+}
+
+// dropExistingTables carries out the "drop" choice of checkExistingTables
+// once the review is confirmed: it backs up the tables, with mysqldump or
+// pg_dump when installed, and drops them. The backup path is recorded in
+// plan for the summary.
+func (e *Engine) dropExistingTables(ctx context.Context, emit func(domain.Event) bool, stepID string, plan *installPlan) bool {
+	a := plan.Answers
+	if a.DBExisting != "drop" || len(a.DBDropTables) == 0 {
+		return true
+	}
+	fail := func(msg string, err error) bool {
+		_ = emit(domain.Event{
+			Type:     domain.EventError,
+			StepID:   stepID,
+			Source:   "install",
+			Severity: domain.SeverityError,
+			Payload: domain.LogPayload{
+				Message: msg + ": " + err.Error(),
+				Key:     "log.db_tables_drop_failed",
+				Params:  map[string]string{"error": err.Error()},
+			},
+		})
+		return false
+	}
+
+	dir := backupDir(plan.WorkDir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return fail("Could not back up and drop the existing tables", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(a.DBName), filepath.Ext(a.DBName))
+	file := filepath.Join(dir, fmt.Sprintf("%s-%stables-%s.sql", name, a.DBTablePrefix, time.Now().Format("20060102-150405")))
+	params := map[string]string{"count": strconv.Itoa(len(a.DBDropTables)), "prefix": a.DBTablePrefix, "file": file}
+	_ = emit(domain.Event{
+		Type:     domain.EventLog,
+		StepID:   stepID,
+		Source:   "install",
+		Severity: domain.SeverityInfo,
+		Payload: domain.LogPayload{
+			Message: fmt.Sprintf("Backing up %d tables to %s and dropping them...", len(a.DBDropTables), file),
+			Key:     "log.db_tables_dropping",
+			Params:  params,
+		},
+	})
+
+	cfg := e.connectionConfig(a)
+	if err := e.dumpSQL(ctx, emit, stepID, plan.WorkDir, cfg, a.DBDropTables, file, true); err != nil {
+		return fail("Could not back up and drop the existing tables", err)
+	}
+	plan.Backups = append(plan.Backups, file)
+	plan.DBBackups = append(plan.DBBackups, file)
+	run := runDBTablesScript
+	if e.dbTables != nil {
+		run = e.dbTables
+	}
+	if err := run(ctx, plan.WorkDir, dbTablesRequest{dbConfig: cfg, Tables: a.DBDropTables, Drop: true}); err != nil {
+		return fail("Could not back up and drop the existing tables", err)
+	}
+	_ = emit(domain.Event{
+		Type:     domain.EventLog,
+		StepID:   stepID,
+		Source:   "install",
+		Severity: domain.SeverityInfo,
+		Payload: domain.LogPayload{
+			Message: fmt.Sprintf("✔ Backed up and dropped %d tables with prefix %s (backup: %s).", len(a.DBDropTables), a.DBTablePrefix, file),
+			Key:     "log.db_tables_dropped",
+			Params:  params,
+			Fields:  map[string]string{"op": "replace_last"},
+		},
+	})
+	return true
+}
@@ -0,0 +1,160 @@
+package install
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// SSLModes are the accepted --db-ssl-mode values, in libpq terms.
+var SSLModes = []string{"disable", "prefer", "require", "verify-ca", "verify-full"}
+
+// dbTransport is how the database server is reached: a Unix socket instead of
+// host and port, and TLS. It comes from flags only and applies to whichever
+// driver is chosen in Step 2.
+type dbTransport struct {
+	Socket  string
+	SSLMode string
+	SSLCA   string
+	SSLCert string
+	SSLKey  string
+}
+
+// mysqlSSL carries the PDO::MYSQL_ATTR_SSL_* attributes; MySQL takes TLS
+// settings as PDO attributes rather than DSN options.
+type mysqlSSL struct {
+	CA     string `json:"ca,omitempty"`
+	Cert   string `json:"cert,omitempty"`
+	Key    string `json:"key,omitempty"`
+	Verify bool   `json:"verify"`
+}
+
+func (o Options) dbTransport() dbTransport {
+	return dbTransport{
+		Socket:  strings.TrimSpace(o.DBSocket),
+		SSLMode: strings.ToLower(strings.TrimSpace(o.DBSSLMode)),
+		SSLCA:   strings.TrimSpace(o.DBSSLCA),
+		SSLCert: strings.TrimSpace(o.DBSSLCert),
+		SSLKey:  strings.TrimSpace(o.DBSSLKey),
+	}
+}
+
+// tls reports whether TLS is requested, by mode or by certificate files.
+func (t dbTransport) tls() bool {
+	switch t.SSLMode {
+	case "require", "verify-ca", "verify-full":
+		return true
+	case "disable":
+		return false
+	}
+	return t.SSLCA != "" || t.SSLCert != "" || t.SSLKey != ""
+}
+
+// args are the matching InstallCommand options.
+func (t dbTransport) args() []string {
+	var args []string
+	for _, o := range []struct{ name, value string }{
+		{"db-socket", t.Socket},
+		{"db-ssl-mode", t.SSLMode},
+		{"db-ssl-ca", t.SSLCA},
+		{"db-ssl-cert", t.SSLCert},
+		{"db-ssl-key", t.SSLKey},
+	} {
+		if o.value != "" {
+			args = append(args, "--"+o.name+"="+o.value)
+		}
+	}
+	return args
+}
+
+// unsupported returns the flags that dbType ignores.
+func (t dbTransport) unsupported(dbType string) []string {
+	var flags []string
+	add := func(set bool, flag string) {
+		if set {
+			flags = append(flags, flag)
+		}
+	}
+	switch dbType {
+	case "sqlite":
+		add(t.Socket != "", "--db-socket")
+		add(t.SSLMode != "", "--db-ssl-mode")
+		add(t.SSLCA != "", "--db-ssl-ca")
+		add(t.SSLCert != "", "--db-ssl-cert")
+		add(t.SSLKey != "", "--db-ssl-key")
+	case "sqlsrv":
+		// pdo_sqlsrv only switches encryption and certificate trust.
+		add(t.Socket != "", "--db-socket")
+		add(t.SSLCA != "", "--db-ssl-ca")
+		add(t.SSLCert != "", "--db-ssl-cert")
+		add(t.SSLKey != "", "--db-ssl-key")
+	}
+	return flags
+}
+
+// apply resolves t into the connection test config: the socket becomes the
+// MySQL unix_socket or the PostgreSQL host directory, and TLS becomes DSN
+// options (PostgreSQL, SQL Server) or PDO attributes (MySQL). Transport
+// flags win over options from --db-url.
+func (t dbTransport) apply(cfg dbConfig) dbConfig {
+	if cfg.Type == "sqlite" {
+		return cfg
+	}
+	options := make(map[string]string, len(cfg.Options)+4)
+	for k, v := range cfg.Options {
+		options[k] = v
+	}
+	set := func(key, value string) {
+		if value != "" {
+			options[key] = value
+		}
+	}
+
+	switch cfg.Type {
+	case "mysql":
+		if t.Socket != "" {
+			// pdo_mysql only uses unix_socket when the host is localhost.
+			cfg.Host = "localhost"
+			set("unix_socket", t.Socket)
+		}
+		if t.tls() {
+			cfg.MySQLSSL = &mysqlSSL{
+				CA:     t.SSLCA,
+				Cert:   t.SSLCert,
+				Key:    t.SSLKey,
+				Verify: strings.HasPrefix(t.SSLMode, "verify-"),
+			}
+		}
+	case "pgsql":
+		if t.Socket != "" {
+			cfg.Host = pgsqlSocketDir(t.Socket)
+		}
+		set("sslmode", t.SSLMode)
+		set("sslrootcert", t.SSLCA)
+		set("sslcert", t.SSLCert)
+		set("sslkey", t.SSLKey)
+	case "sqlsrv":
+		switch t.SSLMode {
+		case "disable":
+			set("Encrypt", "no")
+		case "require":
+			set("Encrypt", "yes")
+			set("TrustServerCertificate", "yes")
+		case "verify-ca", "verify-full":
+			set("Encrypt", "yes")
+			set("TrustServerCertificate", "no")
+		}
+	}
+	if len(options) > 0 {
+		cfg.Options = options
+	}
+	return cfg
+}
+
+// pgsqlSocketDir returns the directory libpq expects as host; users often
+// pass the socket file itself (/var/run/postgresql/.s.PGSQL.5432).
+func pgsqlSocketDir(socket string) string {
+	if strings.HasPrefix(filepath.Base(socket), ".s.PGSQL.") {
+		return filepath.Dir(socket)
+	}
+	return socket
+}
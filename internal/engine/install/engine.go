@@ -9,13 +9,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"net/mail"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -28,6 +28,8 @@ import (
 type Options struct {
 	Force bool
 	Dir   string
+	// Yes skips the review-and-confirm screen before Step 4.
+	Yes bool
 
 	SelfVersion string
 
@@ -42,6 +44,30 @@ type Options struct {
 	DBName     string
 	DBUser     string
 	DBPassword string
+	// DBOptions are driver DSN options, e.g. the query of --db-url.
+	DBOptions map[string]string
+	// DBSocket is a Unix socket used instead of host and port. DBSSLMode is
+	// one of SSLModes; the DBSSL* files are PEM paths.
+	DBSocket  string
+	DBSSLMode string
+	DBSSLCA   string
+	DBSSLCert string
+	DBSSLKey  string
+	// DBCreate creates a missing database without asking; DBCollation is the
+	// MySQL collation for it.
+	DBCreate    bool
+	DBCollation string
+	// DBTablePrefix is the table prefix (default DefaultTablePrefix).
+	// DBExisting is one of ExistingTablesActions and answers the question
+	// asked when tables with that prefix already exist.
+	DBTablePrefix string
+	DBExisting    string
+	// NoBackup skips the database and project files backup taken before a
+	// --force reinstall.
+	NoBackup bool
+	// DBImport is an absolute path to a .sql or .sql.gz dump loaded in
+	// Step 5 instead of running the seeders.
+	DBImport string
 
 	AdminUsername  string
 	AdminEmail     string
@@ -52,15 +78,41 @@ type Options struct {
 	GithubPat string
 	Extras    []domain.ExtrasSelection
 
-	Skills       []string
-	SkillsSource string
-	SkillsRef    string
-	SkillsLink   bool
-	SkillsDryRun bool
+	Skills        []string
+	SkillsTargets []string
+	SkillsSource  string
+	SkillsRef     string
+	SkillsLink    bool
+	SkillsDryRun  bool
+}
+
+// Redacted returns a copy safe to write into logs and support bundles.
+func (o Options) Redacted() Options {
+	redact := func(v string) string {
+		if strings.TrimSpace(v) == "" {
+			return v
+		}
+		return "<redacted>"
+	}
+	out := o
+	out.DBUser = redact(o.DBUser)
+	out.DBPassword = redact(o.DBPassword)
+	out.DBOptions = redactDatabaseOptions(o.DBOptions)
+	out.AdminUsername = redact(o.AdminUsername)
+	out.AdminEmail = redact(o.AdminEmail)
+	out.AdminPassword = redact(o.AdminPassword)
+	out.GithubPat = redact(o.GithubPat)
+	return out
 }
 
 type Engine struct {
 	opt Options
+
+	// testDB, createDB and dbTables override testDatabaseConnection,
+	// createDatabase and runDBTablesScript (tests only).
+	testDB   func(ctx context.Context, workDir string, cfg dbConfig) (dbTestResult, error)
+	createDB func(ctx context.Context, workDir string, cfg dbConfig, collation string) error
+	dbTables func(ctx context.Context, workDir string, req dbTablesRequest) error
 }
 
 func New(opt Options) *Engine { return &Engine{opt: opt} }
@@ -80,6 +132,16 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 				return true
 			}
 		}
+		ctx = domain.WithTracer(ctx, func(source string, message string) {
+			_ = emit(domain.Event{
+				Type:     domain.EventLog,
+				Source:   source,
+				Severity: domain.SeverityTrace,
+				Payload: domain.LogPayload{
+					Message: message,
+				},
+			})
+		})
 
 		var sysStatus domain.SystemStatus
 
@@ -90,13 +152,14 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 			Severity: domain.SeverityInfo,
 			Payload: domain.StepsPayload{
 				Steps: []domain.StepState{
-					{ID: "php", Label: "Step 1: Validate PHP version", Status: domain.StepPending},
-					{ID: "database", Label: "Step 2: Check database connection", Status: domain.StepPending},
-					{ID: "project_preset", Label: "Step 3: Choose project preset", Status: domain.StepPending},
-					{ID: "download", Label: "Step 4: Download Evolution CMS", Status: domain.StepPending},
-					{ID: "install", Label: "Step 5: Install Evolution CMS", Status: domain.StepPending},
-					{ID: "finalize", Label: "Step 6: Finalize installation", Status: domain.StepPending},
-					{ID: "extras", Label: "Step 7: Install Extras", Status: domain.StepPending},
+					{ID: "php", Label: "Step 1: Validate PHP version", Status: domain.StepPending, Key: "step.php"},
+					{ID: "database", Label: "Step 2: Check database connection", Status: domain.StepPending, Key: "step.database"},
+					{ID: "project_preset", Label: "Step 3: Choose project preset", Status: domain.StepPending, Key: "step.project_preset"},
+					{ID: reviewStepID, Label: "Review installation settings", Status: domain.StepPending, Key: "step.review"},
+					{ID: "download", Label: "Step 4: Download Evolution CMS", Status: domain.StepPending, Key: "step.download"},
+					{ID: "install", Label: "Step 5: Install Evolution CMS", Status: domain.StepPending, Key: "step.install"},
+					{ID: "finalize", Label: "Step 6: Finalize installation", Status: domain.StepPending, Key: "step.finalize"},
+					{ID: "extras", Label: "Step 7: Install Extras", Status: domain.StepPending, Key: "step.extras"},
 				},
 			},
 		})
@@ -340,6 +403,7 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 			Severity: domain.SeverityInfo,
 			Payload: domain.StepStartPayload{
 				Label: "Step 1: Validate PHP version",
+				Key:   "step.php",
 				Index: 1,
 				Total: 7,
 			},
@@ -412,551 +476,16 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 			Severity: domain.SeverityInfo,
 			Payload: domain.StepStartPayload{
 				Label: "Step 2: Check database connection",
+				Key:   "step.database",
 				Index: 2,
 				Total: 7,
 			},
 		})
 
-		var (
-			dbType     string
-			dbHost     string
-			dbPort     int
-			dbName     string
-			dbUser     string
-			dbPassword string
-		)
-
-		for {
-			opts := dbDriverQuestionOptions(sysStatus)
-			enabled := enabledOptionIndexes(opts)
-			if len(enabled) == 0 {
-				_ = emit(domain.Event{
-					Type:     domain.EventError,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityError,
-					Payload: domain.LogPayload{
-						Message: "No supported PDO database drivers are available. Please install one of: pdo_mysql, pdo_pgsql, pdo_sqlite, pdo_sqlsrv.",
-					},
-				})
-				_ = emit(domain.Event{
-					Type:     domain.EventStepDone,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityError,
-					Payload:  domain.StepDonePayload{OK: false},
-				})
-				return
-			}
-
-			var (
-				selectedDriver string
-				okSel          bool
-			)
-
-			requestedDriver := strings.ToLower(strings.TrimSpace(e.opt.DBType))
-			if requestedDriver != "" {
-				requestedIdx := -1
-				for i := range opts {
-					if opts[i].ID == requestedDriver {
-						requestedIdx = i
-						break
-					}
-				}
-				if requestedIdx >= 0 && opts[requestedIdx].Enabled {
-					selectedDriver = requestedDriver
-					okSel = true
-				} else if requestedIdx >= 0 && !opts[requestedIdx].Enabled {
-					reason := strings.TrimSpace(opts[requestedIdx].Reason)
-					if reason == "" {
-						reason = "not available"
-					}
-					_ = emit(domain.Event{
-						Type:     domain.EventWarning,
-						StepID:   dbStepID,
-						Source:   "install",
-						Severity: domain.SeverityWarn,
-						Payload: domain.LogPayload{
-							Message: "Requested database driver '" + requestedDriver + "' cannot be used: " + reason + ".",
-						},
-					})
-				} else {
-					_ = emit(domain.Event{
-						Type:     domain.EventWarning,
-						StepID:   dbStepID,
-						Source:   "install",
-						Severity: domain.SeverityWarn,
-						Payload: domain.LogPayload{
-							Message: "Unknown database driver requested: '" + requestedDriver + "'.",
-						},
-					})
-				}
-			}
-
-			if okSel {
-				// Selected from CLI defaults above.
-			} else if len(enabled) == 1 {
-				selectedDriver = opts[enabled[0]].ID
-				okSel = true
-			} else {
-				selectedDriver, okSel = askSelect(ctx, emit, actions, dbStepID, domain.QuestionState{
-					Active:   true,
-					ID:       "db_driver",
-					Kind:     domain.QuestionSelect,
-					Prompt:   "Which database driver do you want to use?",
-					Options:  opts,
-					Selected: enabled[0],
-				})
-			}
-			if !okSel {
-				return
-			}
-			dbType = selectedDriver
-			driverLabel := dbDriverLabel(dbType)
-			_ = emit(domain.Event{
-				Type:     domain.EventLog,
-				StepID:   dbStepID,
-				Source:   "install",
-				Severity: domain.SeverityInfo,
-				Payload: domain.LogPayload{
-					Message: "Selected database driver: " + driverLabel + ".",
-				},
-			})
-
-			switch dbType {
-			case "sqlite":
-				dbName = strings.TrimSpace(e.opt.DBName)
-				if dbName == "" {
-					dbName, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-						Active:  true,
-						ID:      "db_sqlite_path",
-						Kind:    domain.QuestionInput,
-						Prompt:  "What is the name of your SQLite database file?",
-						Default: defaultSQLiteDatabaseName(),
-					})
-					if !ok {
-						return
-					}
-				}
-				dbHost, dbUser, dbPassword = "", "", ""
-				dbPort = 0
-				_ = emit(domain.Event{
-					Type:     domain.EventLog,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityInfo,
-					Payload: domain.LogPayload{
-						Message: "Selected database name: " + dbName + ".",
-					},
-				})
-			default:
-				dbHost = strings.TrimSpace(e.opt.DBHost)
-				if dbHost == "" {
-					dbHost, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-						Active:  true,
-						ID:      "db_host",
-						Kind:    domain.QuestionInput,
-						Prompt:  "Where is your database server located?",
-						Default: "localhost",
-					})
-					if !ok {
-						return
-					}
-				}
-				_ = emit(domain.Event{
-					Type:     domain.EventLog,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityInfo,
-					Payload: domain.LogPayload{
-						Message: "Selected database host: " + dbHost + ".",
-					},
-				})
-
-				dbName = strings.TrimSpace(e.opt.DBName)
-				if dbName == "" {
-					dbName, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-						Active:  true,
-						ID:      "db_name",
-						Kind:    domain.QuestionInput,
-						Prompt:  "What is your database name?",
-						Default: "evo_db",
-					})
-					if !ok {
-						return
-					}
-				}
-				_ = emit(domain.Event{
-					Type:     domain.EventLog,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityInfo,
-					Payload: domain.LogPayload{
-						Message: "Selected database name: " + dbName + ".",
-					},
-				})
-
-				dbUser = strings.TrimSpace(e.opt.DBUser)
-				if dbUser == "" {
-					dbUser, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-						Active:  true,
-						ID:      "db_user",
-						Kind:    domain.QuestionInput,
-						Prompt:  "What is your database username?",
-						Default: "root",
-					})
-					if !ok {
-						return
-					}
-				}
-				_ = emit(domain.Event{
-					Type:     domain.EventLog,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityInfo,
-					Payload: domain.LogPayload{
-						Message: "Selected database user: " + dbUser + ".",
-					},
-				})
-
-				dbPassword = e.opt.DBPassword
-				if dbPassword == "" {
-					dbPassword, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-						Active:  true,
-						ID:      "db_password",
-						Kind:    domain.QuestionInput,
-						Prompt:  "What is your database password?",
-						Default: "",
-						Secret:  true,
-					})
-					if !ok {
-						return
-					}
-				}
-				pwLabel := "(empty)"
-				if strings.TrimSpace(dbPassword) != "" {
-					pwLabel = "••••••••"
-				}
-				_ = emit(domain.Event{
-					Type:     domain.EventLog,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityInfo,
-					Payload: domain.LogPayload{
-						Message: "Selected database password: " + pwLabel + ".",
-					},
-				})
-
-				dbPort = defaultPort(dbType)
-				if e.opt.DBPort > 0 {
-					dbPort = e.opt.DBPort
-				}
-			}
-
-			_ = emit(domain.Event{
-				Type:     domain.EventLog,
-				StepID:   dbStepID,
-				Source:   "install",
-				Severity: domain.SeverityInfo,
-				Payload: domain.LogPayload{
-					Message: "Testing database connection...",
-				},
-			})
-
-			okConn, msg, err := testDatabaseConnection(ctx, workDir, dbConfig{
-				Type:     dbType,
-				Host:     dbHost,
-				Port:     dbPort,
-				Name:     dbName,
-				User:     dbUser,
-				Password: dbPassword,
-			})
-			if err != nil {
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Database connection check failed unexpectedly.",
-						Fields:  map[string]string{"error": err.Error()},
-					},
-				})
-				return
-			}
-			if okConn {
-				_ = emit(domain.Event{
-					Type:     domain.EventLog,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityInfo,
-					Payload: domain.LogPayload{
-						Message: "✔ Database connection successful!",
-						Fields:  map[string]string{"op": "replace_last"},
-					},
-				})
-				break
-			}
-
-			_ = emit(domain.Event{
-				Type:     domain.EventWarning,
-				StepID:   dbStepID,
-				Source:   "install",
-				Severity: domain.SeverityWarn,
-				Payload: domain.LogPayload{
-					Message: "Database connection failed: " + msg,
-				},
-			})
-
-			promptMsg := strings.TrimSpace(msg)
-			promptMsg = strings.ReplaceAll(promptMsg, "\r", " ")
-			promptMsg = strings.ReplaceAll(promptMsg, "\n", " ")
-			promptMsg = strings.Join(strings.Fields(promptMsg), " ")
-			if len(promptMsg) > 160 {
-				promptMsg = promptMsg[:160] + "..."
-			}
-
-			retry, ok := askSelect(ctx, emit, actions, dbStepID, domain.QuestionState{
-				Active: true,
-				ID:     "db_retry",
-				Kind:   domain.QuestionSelect,
-				Prompt: "Database connection failed: " + promptMsg + " — try again or exit installation?",
-				Options: []domain.QuestionOption{
-					{ID: "exit", Label: "Exit installation", Enabled: true},
-					{ID: "retry", Label: "Try again", Enabled: true},
-				},
-				Selected: 1,
-			})
-			if !ok {
-				return
-			}
-			if retry == "exit" {
-				_ = emit(domain.Event{
-					Type:     domain.EventError,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityError,
-					Payload: domain.LogPayload{
-						Message: "Installation cancelled by user.",
-					},
-				})
-				_ = emit(domain.Event{
-					Type:     domain.EventStepDone,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityError,
-					Payload:  domain.StepDonePayload{OK: false},
-				})
-				return
-			}
-		}
-
-		// Admin + language questions (still part of gatherInputs() before continuing).
-		adminUser := strings.TrimSpace(e.opt.AdminUsername)
-		if adminUser == "" {
-			adminUser, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-				Active:  true,
-				ID:      "admin_username",
-				Kind:    domain.QuestionInput,
-				Prompt:  "Enter your Admin username:",
-				Default: "admin",
-			})
-			if !ok {
-				return
-			}
-		}
-		if strings.TrimSpace(adminUser) == "" {
-			adminUser = "admin"
-		}
-		_ = emit(domain.Event{
-			Type:     domain.EventLog,
-			StepID:   dbStepID,
-			Source:   "install",
-			Severity: domain.SeverityInfo,
-			Payload: domain.LogPayload{
-				Message: "Your Admin username: " + adminUser + ".",
-			},
-		})
-
-		var adminEmail string
-		adminEmailTried := false
-		for {
-			if !adminEmailTried && strings.TrimSpace(e.opt.AdminEmail) != "" && adminEmail == "" {
-				adminEmailTried = true
-				email := strings.TrimSpace(e.opt.AdminEmail)
-				_, parseErr := mail.ParseAddress(email)
-				if parseErr == nil {
-					adminEmail = email
-					break
-				}
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Provided --admin-email is invalid; please enter it again.",
-						Fields:  map[string]string{"error": parseErr.Error()},
-					},
-				})
-			}
-			email, ok := askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-				Active: true,
-				ID:     "admin_email",
-				Kind:   domain.QuestionInput,
-				Prompt: "Enter your Admin email:",
-			})
-			if !ok {
-				return
-			}
-			email = strings.TrimSpace(email)
-			if email == "" {
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Email address cannot be empty. Please try again.",
-					},
-				})
-				continue
-			}
-			if _, err := mail.ParseAddress(email); err != nil {
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Please enter a valid email address. Try again.",
-					},
-				})
-				continue
-			}
-			adminEmail = email
-			break
-		}
-		_ = emit(domain.Event{
-			Type:     domain.EventLog,
-			StepID:   dbStepID,
-			Source:   "install",
-			Severity: domain.SeverityInfo,
-			Payload: domain.LogPayload{
-				Message: "Your Admin email: " + adminEmail + ".",
-			},
-		})
-
-		var adminPass string
-		adminPassTried := false
-		for {
-			pwOpt := strings.TrimSpace(e.opt.AdminPassword)
-			if !adminPassTried && adminPass == "" && pwOpt != "" {
-				adminPassTried = true
-				if len([]rune(pwOpt)) >= 6 {
-					adminPass = pwOpt
-					break
-				}
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Provided --admin-password is too short; please enter it again.",
-					},
-				})
-			}
-			pw, ok := askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-				Active: true,
-				ID:     "admin_password",
-				Kind:   domain.QuestionInput,
-				Prompt: "Enter your Admin password:",
-				Secret: true,
-			})
-			if !ok {
-				return
-			}
-			pw = strings.TrimSpace(pw)
-			if pw == "" {
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Password cannot be empty. Please try again.",
-					},
-				})
-				continue
-			}
-			if len([]rune(pw)) < 6 {
-				_ = emit(domain.Event{
-					Type:     domain.EventWarning,
-					StepID:   dbStepID,
-					Source:   "install",
-					Severity: domain.SeverityWarn,
-					Payload: domain.LogPayload{
-						Message: "Password must be at least 6 characters long. Try again.",
-					},
-				})
-				continue
-			}
-			adminPass = pw
-			break
-		}
-		_ = adminPass
-		_ = emit(domain.Event{
-			Type:     domain.EventLog,
-			StepID:   dbStepID,
-			Source:   "install",
-			Severity: domain.SeverityInfo,
-			Payload: domain.LogPayload{
-				Message: "Your Admin password: ••••••••.",
-			},
-		})
-
-		adminDir := strings.TrimSpace(e.opt.AdminDirectory)
-		if adminDir == "" {
-			adminDir, ok = askInput(ctx, emit, actions, dbStepID, domain.QuestionState{
-				Active:  true,
-				ID:      "admin_directory",
-				Kind:    domain.QuestionInput,
-				Prompt:  "Enter your Admin directory:",
-				Default: "manager",
-			})
-			if !ok {
-				return
-			}
-		}
-		adminDir = sanitizeAdminDir(adminDir)
-		_ = emit(domain.Event{
-			Type:     domain.EventLog,
-			StepID:   dbStepID,
-			Source:   "install",
-			Severity: domain.SeverityInfo,
-			Payload: domain.LogPayload{
-				Message: "Your Admin directory: " + adminDir + ".",
-			},
-		})
-
-		lang := strings.ToLower(strings.TrimSpace(e.opt.Language))
-		if lang == "" {
-			lang, ok = askSelect(ctx, emit, actions, dbStepID, languageQuestion())
-			if !ok {
-				return
-			}
+		answers, ok := e.askPreflight(ctx, emit, actions, dbStepID, workDir, sysStatus)
+		if !ok {
+			return
 		}
-		langLabel := languageLabel(lang)
-		_ = emit(domain.Event{
-			Type:     domain.EventLog,
-			StepID:   dbStepID,
-			Source:   "install",
-			Severity: domain.SeverityInfo,
-			Payload: domain.LogPayload{
-				Message: "Selected language: " + langLabel + ".",
-			},
-		})
 
 		_ = emit(domain.Event{
 			Type:     domain.EventStepDone,
@@ -971,6 +500,23 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 			return
 		}
 
+		plan := installPlan{
+			WorkDir:   workDir,
+			Version:   reviewVersion(e.opt.Branch, releaseInfo),
+			Answers:   answers,
+			Preset:    selectedPreset,
+			Extras:    e.opt.Extras,
+			Transport: e.opt.dbTransport(),
+			Import:    e.opt.DBImport,
+		}
+		if e.opt.Force && !e.opt.NoBackup {
+			plan.BackupDir = backupDir(workDir)
+		}
+		if !e.runReviewStep(ctx, emit, actions, sysStatus, &plan) {
+			return
+		}
+		answers, selectedPreset = plan.Answers, plan.Preset
+
 		// Step 3+: follow InstallCommand pipeline (next).
 		_ = emit(domain.Event{
 			Type:     domain.EventStepStart,
@@ -979,23 +525,31 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 			Severity: domain.SeverityInfo,
 			Payload: domain.StepStartPayload{
 				Label: "Step 4: Download Evolution CMS",
+				Key:   "step.download",
 				Index: 4,
 				Total: 7,
 			},
 		})
 
 		if err := runPHPNewCommand(ctx, emit, phpNewOptions{
-			DBType:             dbType,
-			DBHost:             dbHost,
-			DBPort:             dbPort,
-			DBName:             dbName,
-			DBUser:             dbUser,
-			DBPassword:         dbPassword,
-			AdminUsername:      adminUser,
-			AdminEmail:         adminEmail,
-			AdminPassword:      adminPass,
-			AdminDirectory:     adminDir,
-			Language:           lang,
+			DBType:             answers.DBType,
+			DBHost:             answers.DBHost,
+			DBPort:             answers.DBPort,
+			DBName:             answers.DBName,
+			DBUser:             answers.DBUser,
+			DBPassword:         answers.DBPassword,
+			DBOptions:          answers.DBOptions,
+			DBTransport:        e.opt.dbTransport(),
+			DBCollation:        answers.DBCollation,
+			DBTablePrefix:      answers.DBTablePrefix,
+			DBImport:           e.opt.DBImport,
+			DBExisting:         answers.DBExisting,
+			DBBackups:          plan.DBBackups,
+			AdminUsername:      answers.AdminUsername,
+			AdminEmail:         answers.AdminEmail,
+			AdminPassword:      answers.AdminPassword,
+			AdminDirectory:     answers.AdminDirectory,
+			Language:           answers.Language,
 			Force:              e.opt.Force,
 			Branch:             strings.TrimSpace(e.opt.Branch),
 			Preset:             selectedPreset,
@@ -1030,9 +584,10 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan
 				},
 			})
 		}
-		e.maybeRunExtras(ctx, emit, actions, workDir, requiredExtras)
+		extrasResults := e.maybeRunExtras(ctx, emit, actions, workDir, requiredExtras)
 		e.maybeRunSkillsInstall(ctx, emit, workDir)
 		e.cleanupExtrasRuntimeArtifacts(emit, workDir)
+		emitInstallSummary(emit, plan, extrasResults)
 	}()
 }
 
@@ -1045,6 +600,7 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 		Severity: domain.SeverityInfo,
 		Payload: domain.StepStartPayload{
 			Label: "Step 3: Choose project preset",
+			Key:   "step.project_preset",
 			Index: 3,
 			Total: 7,
 		},
@@ -1079,6 +635,14 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 		return preset, true
 	}
 
+	choice, ok := askProjectPreset(ctx, emit, actions, stepID, "")
+	done(ok)
+	return choice, ok
+}
+
+// askProjectPreset asks for the project preset, preselecting current when it
+// is one of the catalog choices.
+func askProjectPreset(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, stepID string, current string) (string, bool) {
 	_ = emit(domain.Event{
 		Type:     domain.EventLog,
 		StepID:   stepID,
@@ -1102,6 +666,27 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 			},
 		})
 	}
+	custom := ""
+	if current = strings.TrimSpace(current); current != "" {
+		want := current
+		if want == "evolution" {
+			want = projectPresetCoreOnlyID
+		}
+		matched := false
+		for i, o := range options {
+			if o.ID == want && o.Enabled {
+				selected, matched = i, true
+			}
+		}
+		if !matched {
+			custom = current
+			for i, o := range options {
+				if o.ID == projectPresetCustomID {
+					selected = i
+				}
+			}
+		}
+	}
 
 	choice, ok := askSelect(ctx, emit, actions, stepID, domain.QuestionState{
 		Active:   true,
@@ -1112,7 +697,6 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 		Selected: selected,
 	})
 	if !ok {
-		done(false)
 		return "", false
 	}
 
@@ -1125,9 +709,9 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 				Kind:    domain.QuestionInput,
 				Prompt:  "Enter preset repository, Git URL, or local path (optional @branch):",
 				Default: "owner/repo",
+				Value:   custom,
 			})
 			if !ok {
-				done(false)
 				return "", false
 			}
 			custom = strings.TrimSpace(custom)
@@ -1152,7 +736,6 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 					Message: "Selected custom project preset: " + custom + ".",
 				},
 			})
-			done(true)
 			return custom, true
 		}
 	case projectPresetCoreOnlyID:
@@ -1165,7 +748,6 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 				Message: "Selected project preset: Evolution core only.",
 			},
 		})
-		done(true)
 		return "evolution", true
 	default:
 		_ = emit(domain.Event{
@@ -1177,7 +759,6 @@ func (e *Engine) chooseProjectPreset(ctx context.Context, emit func(domain.Event
 				Message: "Selected project preset: " + choice + ".",
 			},
 		})
-		done(true)
 		return choice, true
 	}
 }
@@ -1222,6 +803,18 @@ type phpNewOptions struct {
 	DBName     string
 	DBUser     string
 	DBPassword string
+	DBOptions  map[string]string
+
+	DBTransport   dbTransport
+	DBCollation   string
+	DBTablePrefix string
+	DBImport      string
+	// DBExisting "keep" updates the Evolution CMS installation already in
+	// the database instead of seeding a new one.
+	DBExisting string
+	// DBBackups are the database backups taken before the install; a failed
+	// import names them.
+	DBBackups []string
 
 	AdminUsername  string
 	AdminEmail     string
@@ -1243,6 +836,7 @@ type phpNewOptions struct {
 
 var consoleTagRe = regexp.MustCompile(`<[^>]+>`)
 var plainProgressLineRe = regexp.MustCompile(`^([A-Za-z][A-Za-z ]+)\s+\[[^\]]+\]\s+(\d{1,3})%\s*(\([^)]*\))?\s*$`)
+var byteProgressTailRe = regexp.MustCompile(`^\((\d+) bytes / (\d+) bytes\)$`)
 var adminDirSanitizeRe = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
 
 func stripConsoleTags(s string) string {
@@ -1271,6 +865,21 @@ func parsePlainProgressLine(line string) (label string, pct int, tail string, ok
 	return label, pct, tail, true
 }
 
+// parseByteProgressTail reads the exact counts of a progress line reported in
+// bytes, e.g. the database import: "(1048576 bytes / 5242880 bytes)".
+func parseByteProgressTail(tail string) (current int64, total int64, ok bool) {
+	m := byteProgressTailRe.FindStringSubmatch(tail)
+	if m == nil {
+		return 0, 0, false
+	}
+	current, err1 := strconv.ParseInt(m[1], 10, 64)
+	total, err2 := strconv.ParseInt(m[2], 10, 64)
+	if err1 != nil || err2 != nil {
+		return 0, 0, false
+	}
+	return current, total, true
+}
+
 func sanitizeAdminDir(s string) string {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -1302,15 +911,9 @@ func shouldSuppressPHPSubprocessLine(line string) bool {
 	return false
 }
 
-func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt phpNewOptions) error {
-	tracker := newStepTracker(emit)
-
-	entry, err := findPHPSymfonyCLIEntry()
-	if err != nil {
-		tracker.FailRemaining()
-		return err
-	}
-
+// phpNewArgs is the InstallCommand command line for opt; entry is the PHP
+// installer script.
+func phpNewArgs(entry string, opt phpNewOptions) []string {
 	args := []string{
 		entry,
 		"install",
@@ -1329,6 +932,24 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 		"--admin-directory=" + opt.AdminDirectory,
 		"--language=" + opt.Language,
 	}
+	if len(opt.DBOptions) > 0 {
+		args = append(args, "--db-options="+domain.EncodeDatabaseOptions(opt.DBOptions))
+	}
+	if opt.DBType != "sqlite" {
+		args = append(args, opt.DBTransport.args()...)
+	}
+	if opt.DBType == "mysql" && opt.DBCollation != "" {
+		args = append(args, "--db-collation="+opt.DBCollation)
+	}
+	if opt.DBTablePrefix != "" {
+		args = append(args, "--db-prefix="+opt.DBTablePrefix)
+	}
+	if opt.DBImport != "" {
+		args = append(args, "--db-import="+opt.DBImport)
+	}
+	if opt.DBExisting == "keep" {
+		args = append(args, "--db-existing=keep")
+	}
 	if strings.TrimSpace(opt.Branch) != "" {
 		args = append(args, "--branch="+strings.TrimSpace(opt.Branch))
 	}
@@ -1347,6 +968,21 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 	if opt.ComposerClearCache {
 		args = append(args, "--composer-clear-cache")
 	}
+	return args
+}
+
+func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt phpNewOptions) error {
+	tracker := newStepTracker(emit, opt.DBType)
+
+	entry, err := probePHPSymfonyCLIEntry(func(candidate string, result string) {
+		domain.Tracef(ctx, "exec", "php entry candidate %s: %s", candidate, result)
+	})
+	if err != nil {
+		tracker.FailRemaining()
+		return err
+	}
+
+	args := phpNewArgs(entry, opt)
 
 	runCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
@@ -1355,15 +991,19 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 	if strings.TrimSpace(opt.WorkDir) != "" {
 		cmd.Dir = opt.WorkDir
 	}
-	env := append([]string(nil), os.Environ()...)
-	env = append(env, "CI=1")
+	envAdded := []string{"CI=1", "EVO_LOG_SEVERITY=1"}
 	// Some older PHP installer versions connect to PostgreSQL without dbname to validate
 	// credentials. PostgreSQL defaults dbname to the username in that case. Setting
 	// PGDATABASE ensures those connection attempts use a maintenance database instead.
 	if strings.EqualFold(strings.TrimSpace(opt.DBType), "pgsql") {
-		env = append(env, "PGDATABASE=template1")
+		envAdded = append(envAdded, "PGDATABASE=template1")
+	}
+	if opt.DBImport != "" && len(opt.DBBackups) > 0 {
+		envAdded = append(envAdded, "EVO_DB_BACKUPS="+strings.Join(opt.DBBackups, string(os.PathListSeparator)))
 	}
-	cmd.Env = env
+	cmd.Env = append(append([]string(nil), os.Environ()...), envAdded...)
+	traceExec(ctx, "exec", cmd.Args, cmd.Dir, envAdded)
+	cmdStarted := time.Now()
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
@@ -1381,8 +1021,9 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 	}
 
 	type subprocessLine struct {
-		text   string
-		stderr bool
+		text     string
+		stderr   bool
+		severity domain.Severity
 	}
 
 	linesCh := make(chan subprocessLine, 256)
@@ -1392,11 +1033,11 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 		sc := bufio.NewScanner(r)
 		sc.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 		for sc.Scan() {
-			line := strings.TrimSpace(stripConsoleTags(sc.Text()))
+			sev, line := splitPlainSeverity(strings.TrimSpace(stripConsoleTags(sc.Text())), isStderr)
 			if line == "" {
 				continue
 			}
-			linesCh <- subprocessLine{text: line, stderr: isStderr}
+			linesCh <- subprocessLine{text: line, stderr: isStderr, severity: sev}
 		}
 	}
 
@@ -1408,6 +1049,9 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 		close(linesCh)
 	}()
 
+	phases := &phaseTimer{emit: emit}
+	defer phases.finish()
+
 	lastWasSeederStart := false
 	lastSeeder := ""
 	lastStep := ""
@@ -1424,6 +1068,7 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 			cancel()
 		}
 		stepID := tracker.CurrentStepID()
+		phases.OnLine(stepID, line)
 
 		if shouldSuppressPHPSubprocessLine(line) {
 			continue
@@ -1455,6 +1100,16 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 		}
 
 		if label, pct, tail, ok := parsePlainProgressLine(line); ok {
+			if current, total, ok := parseByteProgressTail(tail); ok {
+				_ = emit(domain.Event{
+					Type:     domain.EventProgress,
+					StepID:   stepID,
+					Source:   "php",
+					Severity: domain.SeverityInfo,
+					Payload:  domain.ProgressPayload{Current: current, Total: total, Unit: "bytes"},
+				})
+				tail = fmt.Sprintf("(%s / %s)", formatSize(current), formatSize(total))
+			}
 			_ = emit(domain.Event{
 				Type:     domain.EventLog,
 				StepID:   stepID,
@@ -1476,10 +1131,9 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 		}
 
 		evType := domain.EventLog
-		sev := domain.SeverityInfo
-		if l.stderr {
+		sev := l.severity
+		if l.stderr && sev == domain.SeverityWarn {
 			evType = domain.EventWarning
-			sev = domain.SeverityWarn
 		}
 
 		fields := map[string]string(nil)
@@ -1503,7 +1157,9 @@ func runPHPNewCommand(ctx context.Context, emit func(domain.Event) bool, opt php
 		})
 	}
 
-	if err := cmd.Wait(); err != nil {
+	err = cmd.Wait()
+	traceExit(ctx, "exec", cmd, err, time.Since(cmdStarted))
+	if err != nil {
 		tracker.FailRemaining()
 		return err
 	}
@@ -1534,8 +1190,71 @@ func parseSeederDoneLine(line string) (seeder string, ok bool) {
 	return m[1], true
 }
 
+// phaseTimer measures the subprocess-heavy phases of the PHP installer
+// (Composer, migrations, seeders, dump import) from their output markers and
+// reports each as an EventTiming on the step it ran in.
+type phaseTimer struct {
+	emit    func(domain.Event) bool
+	label   string
+	stepID  string
+	started time.Time
+}
+
+func (p *phaseTimer) OnLine(stepID, line string) {
+	lower := strings.ToLower(line)
+	switch {
+	case strings.HasPrefix(lower, "installing dependencies"), strings.HasPrefix(lower, "updating dependencies"):
+		p.begin(stepID, "Composer dependencies")
+	case strings.Contains(line, "Running database migrations"):
+		p.begin(stepID, "Database migrations")
+	case strings.Contains(line, "Running database seeders"):
+		p.begin(stepID, "Database seeders")
+	case strings.HasPrefix(line, "Importing database dump"):
+		p.begin(stepID, "Database import")
+	case strings.Contains(lower, "dependencies installed successfully"),
+		strings.Contains(lower, "dependencies updated successfully"),
+		strings.Contains(line, "All seeders completed successfully"),
+		strings.Contains(line, "Database import completed successfully"),
+		strings.Contains(line, "Finalizing installation"):
+		p.finish()
+	}
+}
+
+func (p *phaseTimer) begin(stepID, label string) {
+	if p.label == label {
+		return
+	}
+	p.finish()
+	p.label = label
+	p.stepID = stepID
+	p.started = time.Now()
+}
+
+func (p *phaseTimer) finish() {
+	if p.label == "" {
+		return
+	}
+	emitTiming(p.emit, p.stepID, "php", p.label, time.Since(p.started))
+	p.label = ""
+}
+
+func emitTiming(emit func(domain.Event) bool, stepID, source, label string, d time.Duration) {
+	_ = emit(domain.Event{
+		Type:     domain.EventTiming,
+		StepID:   stepID,
+		Source:   source,
+		Severity: domain.SeverityInfo,
+		Payload: domain.TimingPayload{
+			Label:    label,
+			Duration: d,
+		},
+	})
+}
+
 type stepTracker struct {
 	emit func(domain.Event) bool
+	// variant is reported with every step start (the database driver).
+	variant string
 
 	current string
 
@@ -1545,9 +1264,10 @@ type stepTracker struct {
 	failed  bool
 }
 
-func newStepTracker(emit func(domain.Event) bool) *stepTracker {
+func newStepTracker(emit func(domain.Event) bool, variant string) *stepTracker {
 	return &stepTracker{
 		emit:    emit,
+		variant: variant,
 		current: "download",
 		done:    map[string]bool{},
 		started: map[string]bool{},
@@ -1578,9 +1298,11 @@ func (t *stepTracker) start(stepID, label string, index int) {
 		Source:   "install",
 		Severity: domain.SeverityInfo,
 		Payload: domain.StepStartPayload{
-			Label: label,
-			Index: index,
-			Total: 7,
+			Label:   label,
+			Index:   index,
+			Total:   7,
+			Key:     "step." + stepID,
+			Variant: t.variant,
 		},
 	})
 }
@@ -1620,11 +1342,12 @@ func (t *stepTracker) OnLine(line string) {
 	if strings.Contains(line, "Setting up database") {
 		t.start("install", "Step 5: Install Evolution CMS", 5)
 	}
-	if strings.Contains(line, "All seeders completed successfully") {
+	if strings.Contains(line, "All seeders completed successfully") || strings.Contains(line, "Database import completed successfully") {
 		t.doneStep("install", true)
 	}
 	// Install command now reports migrations and composer install as part of Step 5.
-	if strings.Contains(line, "Running database migrations") || strings.Contains(line, "Running database seeders") {
+	if strings.Contains(line, "Running database migrations") || strings.Contains(line, "Running database seeders") ||
+		strings.HasPrefix(line, "Importing database dump") {
 		t.start("install", "Step 5: Install Evolution CMS", 5)
 	}
 
@@ -1640,7 +1363,7 @@ func (t *stepTracker) OnLine(line string) {
 	if strings.Contains(strings.ToLower(line), "failed to download evolution cms") {
 		t.doneStep("download", false)
 	}
-	if strings.Contains(strings.ToLower(line), "migration failed") {
+	if strings.Contains(strings.ToLower(line), "migration failed") || strings.Contains(strings.ToLower(line), "database import failed") {
 		t.doneStep("install", false)
 	}
 	if strings.Contains(strings.ToLower(line), "failed to install dependencies") {
@@ -1676,9 +1399,11 @@ func (t *stepTracker) FailRemaining() {
 	}
 }
 
-func askSelect(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, stepID string, q domain.QuestionState) (string, bool) {
-	if actions == nil {
-		return "", false
+// emitQuestion sends q to the UI. Its prompt is looked up in the message
+// catalog as question.<ID> unless the question names its own key.
+func emitQuestion(emit func(domain.Event) bool, stepID string, q domain.QuestionState) {
+	if q.Key == "" {
+		q.Key = "question." + q.ID
 	}
 	_ = emit(domain.Event{
 		Type:     domain.EventLog,
@@ -1689,6 +1414,13 @@ func askSelect(ctx context.Context, emit func(domain.Event) bool, actions <-chan
 			Question: q,
 		},
 	})
+}
+
+func askSelect(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, stepID string, q domain.QuestionState) (string, bool) {
+	if actions == nil {
+		return "", false
+	}
+	emitQuestion(emit, stepID, q)
 	for {
 		select {
 		case <-ctx.Done():
@@ -1706,15 +1438,7 @@ func askInput(ctx context.Context, emit func(domain.Event) bool, actions <-chan
 	if actions == nil {
 		return "", false
 	}
-	_ = emit(domain.Event{
-		Type:     domain.EventLog,
-		StepID:   stepID,
-		Source:   "install",
-		Severity: domain.SeverityInfo,
-		Payload: domain.QuestionPayload{
-			Question: q,
-		},
-	})
+	emitQuestion(emit, stepID, q)
 	for {
 		select {
 		case <-ctx.Done():
@@ -1839,8 +1563,9 @@ func (e *Engine) maybeOfferSelfUpdate(ctx context.Context, emit func(domain.Even
 		Kind:   domain.QuestionSelect,
 		Prompt: "A new installer version is available. Update now?",
 		Options: []domain.QuestionOption{
-			{ID: "update", Label: "Update now (" + cmdStr + ")", Enabled: updateEnabled, Reason: reason},
-			{ID: "skip", Label: "Continue without updating", Enabled: true},
+			{ID: "update", Label: "Update now (" + cmdStr + ")", Enabled: updateEnabled, Reason: reason,
+				Key: "option.self_update.update", Params: map[string]string{"command": cmdStr}},
+			{ID: "skip", Label: "Continue without updating", Enabled: true, Key: "option.self_update.skip"},
 		},
 		Selected: 1,
 	})
@@ -1913,7 +1638,10 @@ func validatePHPVersion(ctx context.Context) (string, bool, error) {
 	const minMajor, minMinor, minPatch = 8, 3, 0
 
 	cmd := exec.CommandContext(ctx, "php", "-r", "echo PHP_VERSION;")
+	traceExec(ctx, "exec", cmd.Args, cmd.Dir, nil)
+	started := time.Now()
 	out, err := cmd.Output()
+	traceExit(ctx, "exec", cmd, err, time.Since(started))
 	if err != nil {
 		return "", false, err
 	}
@@ -1968,11 +1696,25 @@ type dbConfig struct {
 	Name     string `json:"name,omitempty"`
 	User     string `json:"user,omitempty"`
 	Password string `json:"password,omitempty"`
+	// Options are appended to the PDO DSN as key=value pairs.
+	Options map[string]string `json:"options,omitempty"`
+	// MySQLSSL turns on TLS for MySQL connections.
+	MySQLSSL *mysqlSSL `json:"mysql_ssl,omitempty"`
 }
 
 type dbTestResult struct {
 	OK    bool   `json:"ok"`
 	Error string `json:"error,omitempty"`
+	// MissingDB reports that the server is reachable but the named database
+	// does not exist. CanCreate tells whether the user may create it;
+	// GrantError explains why not.
+	MissingDB     bool   `json:"missing_db,omitempty"`
+	CanCreate     bool   `json:"can_create,omitempty"`
+	GrantError    string `json:"grant_error,omitempty"`
+	ServerVersion string `json:"server_version,omitempty"`
+	// Tables lists the tables already in the database after a successful
+	// connection.
+	Tables []string `json:"tables,omitempty"`
 }
 
 func defaultPort(dbType string) int {
@@ -2077,8 +1819,8 @@ func hasProjectPresetCatalogOption(options []domain.QuestionOption) bool {
 
 func appendProjectPresetSpecialOptions(options []domain.QuestionOption) ([]domain.QuestionOption, int) {
 	head := []domain.QuestionOption{
-		{ID: projectPresetCoreOnlyID, Label: "No project preset (Evolution core only)", Enabled: true},
-		{ID: projectPresetCustomID, Label: "Custom repository, Git URL, or local path", Enabled: true},
+		{ID: projectPresetCoreOnlyID, Label: "No project preset (Evolution core only)", Enabled: true, Key: "option.project_preset.core_only"},
+		{ID: projectPresetCustomID, Label: "Custom repository, Git URL, or local path", Enabled: true, Key: "option.project_preset.custom"},
 	}
 	options = append(head, options...)
 
@@ -2150,13 +1892,15 @@ func dbDriverQuestionOptions(status domain.SystemStatus) []domain.QuestionOption
 
 func dbDriverOption(status domain.SystemStatus, pdoOK bool, id string, label string, statusKey string) domain.QuestionOption {
 	if !pdoOK {
-		return domain.QuestionOption{ID: id, Label: label, Enabled: false, Reason: "Missing PHP extension: pdo"}
+		return domain.QuestionOption{ID: id, Label: label, Enabled: false, Reason: "Missing PHP extension: pdo",
+			ReasonKey: "reason.missing_php_extension", ReasonParams: map[string]string{"extension": "pdo"}}
 	}
 	level, ok := statusLevelForKey(status, statusKey)
 	if ok && level == domain.StatusOK {
 		return domain.QuestionOption{ID: id, Label: label, Enabled: true}
 	}
-	return domain.QuestionOption{ID: id, Label: label, Enabled: false, Reason: "Missing PDO driver: " + statusKey}
+	return domain.QuestionOption{ID: id, Label: label, Enabled: false, Reason: "Missing PDO driver: " + statusKey,
+		ReasonKey: "reason.missing_pdo_driver", ReasonParams: map[string]string{"driver": statusKey}}
 }
 
 func statusLevelForKey(status domain.SystemStatus, key string) (domain.StatusLevel, bool) {
@@ -2172,6 +1916,7 @@ const dbConnectionTestScript = `
 $cfg = json_decode(base64_decode($argv[1] ?? ''), true);
 if (!is_array($cfg)) { echo json_encode(["ok"=>false,"error"=>"Invalid config"]); exit(0); }
 $type = $cfg["type"] ?? "mysql";
+` + dbScriptFunctions + `
 $driverMap = ["mysql"=>"mysql","pgsql"=>"pgsql","sqlite"=>"sqlite","sqlsrv"=>"sqlsrv"];
 $required = $driverMap[$type] ?? null;
 if ($required && !in_array($required, \PDO::getAvailableDrivers(), true)) {
@@ -2186,12 +1931,11 @@ try {
   $name = $cfg["name"] ?? "";
   $user = $cfg["user"] ?? "";
   $pass = $cfg["password"] ?? "";
-  $timeout = [\PDO::ATTR_TIMEOUT => 5];
+  $timeout = evo_db_attributes($cfg);
+  $extra = evo_db_extra($cfg);
   if ($type === "sqlite") {
     if ($name === "") { echo json_encode(["ok"=>false,"error"=>"SQLite database name is required."]); exit(0); }
-    if ($name !== ":memory:" && !str_starts_with($name, "file:") && !preg_match('/^(\/|[A-Za-z]:[\\\\\\/])/', $name)) {
-      $name = "core/database/" . basename(str_replace("\\", "/", $name));
-    }
+    $name = evo_sqlite_path($name);
     if ($name !== ":memory:" && !str_starts_with($name, "file:")) {
       $dir = dirname($name);
       if ($dir !== "" && $dir !== "." && !is_dir($dir)) {
@@ -2201,16 +1945,17 @@ try {
         @touch($name);
       }
     }
-    new \PDO("sqlite:".$name, null, null, $timeout);
-    echo json_encode(["ok"=>true]); exit(0);
+    $dbh = new \PDO("sqlite:".$name, null, null, $timeout);
+    evo_db_report_ok($type, $dbh);
   }
   if ($type === "pgsql") {
     $maintenanceOk = false;
     $maintenanceErr = null;
+    $maintenanceDbh = null;
     foreach (["postgres", "template1"] as $db) {
       try {
         $dsnMaint = $port > 0 ? "pgsql:host={$host};port={$port};dbname={$db}" : "pgsql:host={$host};dbname={$db}";
-        new \PDO($dsnMaint, $user, $pass, $timeout);
+        $maintenanceDbh = new \PDO($dsnMaint.$extra, $user, $pass, $timeout);
         $maintenanceOk = true;
         break;
       } catch (\Throwable $e) {
@@ -2220,34 +1965,46 @@ try {
 
     $targetOk = false;
     $targetErr = null;
+    $targetDbh = null;
     if ($name !== "") {
       try {
         $dsn = $port > 0 ? "pgsql:host={$host};port={$port};dbname={$name}" : "pgsql:host={$host};dbname={$name}";
-        new \PDO($dsn, $user, $pass, $timeout);
+        $targetDbh = new \PDO($dsn.$extra, $user, $pass, $timeout);
         $targetOk = true;
       } catch (\Throwable $e) {
         $targetErr = $e;
       }
     }
+    if ($maintenanceOk && !$targetOk && $targetErr && evo_db_missing($type, $targetErr)) {
+      evo_db_report_missing($type, $maintenanceDbh, $name, $targetErr);
+    }
 
     if (!$maintenanceOk && !$targetOk) {
       throw $targetErr ?? $maintenanceErr ?? new \Exception("PostgreSQL connection failed.");
     }
 
-    echo json_encode(["ok"=>true]); exit(0);
+    evo_db_report_ok($type, $targetDbh);
   }
 
   $dsnNoDb = match($type) {
     "sqlsrv" => $port > 0 ? "sqlsrv:Server={$host},{$port}" : "sqlsrv:Server={$host}",
     default => "mysql:host={$host};port={$port};charset=utf8mb4",
   };
-  new \PDO($dsnNoDb, $user, $pass, $timeout);
+  $serverDbh = new \PDO($dsnNoDb.$extra, $user, $pass, $timeout);
   if ($name !== "") {
     $dsn = match($type) {
       "sqlsrv" => ($port > 0 ? "sqlsrv:Server={$host},{$port};Database={$name}" : "sqlsrv:Server={$host};Database={$name}"),
       default => "mysql:host={$host};port={$port};dbname={$name};charset=utf8mb4",
     };
-    new \PDO($dsn, $user, $pass, $timeout);
+    try {
+      $targetDbh = new \PDO($dsn.$extra, $user, $pass, $timeout);
+    } catch (\Throwable $e) {
+      if (evo_db_missing($type, $e)) {
+        evo_db_report_missing($type, $serverDbh, $name, $e);
+      }
+      throw $e;
+    }
+    evo_db_report_ok($type, $targetDbh);
   }
   echo json_encode(["ok"=>true]); exit(0);
 } catch (\Throwable $e) {
@@ -2261,34 +2018,39 @@ try {
 }
 `
 
-func testDatabaseConnection(ctx context.Context, workDir string, cfg dbConfig) (bool, string, error) {
-	raw, err := json.Marshal(cfg)
+func testDatabaseConnection(ctx context.Context, workDir string, cfg dbConfig) (dbTestResult, error) {
+	var res dbTestResult
+	err := runDBScript(ctx, workDir, dbConnectionTestScript, "<db connection test>", cfg, &res)
+	return res, err
+}
+
+// runDBScript runs a PHP database script with payload as its base64 JSON
+// argument and decodes the JSON it prints into out.
+func runDBScript(ctx context.Context, workDir string, script string, label string, payload any, out any) error {
+	raw, err := json.Marshal(payload)
 	if err != nil {
-		return false, "", err
+		return err
 	}
 	encoded := base64.StdEncoding.EncodeToString(raw)
 
-	script := dbConnectionTestScript
-
 	cmd := exec.CommandContext(ctx, "php", "-r", script, encoded)
 	if strings.TrimSpace(workDir) != "" {
 		cmd.Dir = workDir
 	}
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
-	out, execErr := cmd.Output()
+	// The script and its base64 payload (credentials included) are not traced.
+	traceExec(ctx, "exec", []string{"php", "-r", label, "<payload>"}, cmd.Dir, nil)
+	started := time.Now()
+	stdout, execErr := cmd.Output()
+	traceExit(ctx, "exec", cmd, execErr, time.Since(started))
 	if execErr != nil {
 		if stderr.Len() > 0 {
-			return false, "", fmt.Errorf("%w: %s", execErr, strings.TrimSpace(stderr.String()))
+			return fmt.Errorf("%w: %s", execErr, strings.TrimSpace(stderr.String()))
 		}
-		return false, "", execErr
+		return execErr
 	}
-
-	var res dbTestResult
-	if err := json.Unmarshal(out, &res); err != nil {
-		return false, "", err
-	}
-	return res.OK, res.Error, nil
+	return json.Unmarshal(stdout, out)
 }
 
 func languageQuestion() domain.QuestionState {
@@ -2397,10 +2159,19 @@ type systemStatusJSON struct {
 	} `json:"items"`
 }
 
-func fetchSystemStatus(ctx context.Context) (domain.SystemStatus, error) {
+// SystemStatusReport returns the raw `system-status --format=json` output.
+func SystemStatusReport(ctx context.Context) ([]byte, error) {
+	out, stderr, err := runSystemStatus(ctx)
+	if err != nil && stderr != "" {
+		return out, fmt.Errorf("%w: %s", err, stderr)
+	}
+	return out, err
+}
+
+func runSystemStatus(ctx context.Context) ([]byte, string, error) {
 	entry, err := findPHPSystemStatusEntry()
 	if err != nil {
-		return domain.SystemStatus{}, err
+		return nil, "", err
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, 25*time.Second)
@@ -2409,13 +2180,21 @@ func fetchSystemStatus(ctx context.Context) (domain.SystemStatus, error) {
 	cmd := exec.CommandContext(ctx, "php", entry, "system-status", "--format=json", "--no-ansi", "--no-interaction")
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
+	traceExec(ctx, "exec", cmd.Args, cmd.Dir, nil)
+	started := time.Now()
 	out, execErr := cmd.Output()
+	traceExit(ctx, "exec", cmd, execErr, time.Since(started))
+	return out, strings.TrimSpace(stderr.String()), execErr
+}
+
+func fetchSystemStatus(ctx context.Context) (domain.SystemStatus, error) {
+	out, stderr, execErr := runSystemStatus(ctx)
 
 	var parsed systemStatusJSON
 	if unmarshalErr := json.Unmarshal(out, &parsed); unmarshalErr != nil {
 		if execErr != nil {
-			if stderr.Len() > 0 {
-				return domain.SystemStatus{}, fmt.Errorf("%w: %s", execErr, strings.TrimSpace(stderr.String()))
+			if stderr != "" {
+				return domain.SystemStatus{}, fmt.Errorf("%w: %s", execErr, stderr)
 			}
 			return domain.SystemStatus{}, execErr
 		}
@@ -2485,6 +2264,15 @@ func looksLikePHPScript(path string) bool {
 }
 
 func findPHPSymfonyCLIEntry() (string, error) {
+	return probePHPSymfonyCLIEntry(nil)
+}
+
+// probePHPSymfonyCLIEntry is findPHPSymfonyCLIEntry with a callback that is
+// told about every candidate path and why it was accepted or skipped.
+func probePHPSymfonyCLIEntry(trace func(candidate string, result string)) (string, error) {
+	if trace == nil {
+		trace = func(string, string) {}
+	}
 	// This entrypoint must be the internal Symfony Console runner (installer/bin/evo),
 	// not the end-user bootstrapper (bin/evo). The bootstrapper proxies to the Go
 	// binary and will fail when we pass PHP-only flags like --no-ansi/--no-interaction.
@@ -2511,14 +2299,18 @@ func findPHPSymfonyCLIEntry() (string, error) {
 		}
 		fi, err := os.Stat(p)
 		if err != nil || fi.IsDir() {
+			trace(p, "not found")
 			continue
 		}
 		if !looksLikePHPScript(p) {
+			trace(p, "not a PHP script")
 			continue
 		}
 		if !looksLikeSymfonyEntry(p) {
+			trace(p, "not the Symfony console entry")
 			continue
 		}
+		trace(p, "selected")
 
 		abs, absErr := filepath.Abs(p)
 		if absErr != nil {
@@ -4,6 +4,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/evolution-cms/installer/internal/domain"
 	"github.com/evolution-cms/installer/internal/services/github"
 )
 
@@ -132,3 +133,180 @@ func TestFallbackProjectPresetOptionsIncludesCustomAndCoreOnly(t *testing.T) {
 		t.Fatalf("expected custom option second, got %#v", options)
 	}
 }
+
+func TestPhaseTimerReportsPHPPhases(t *testing.T) {
+	t.Parallel()
+
+	var got []domain.Event
+	p := &phaseTimer{emit: func(ev domain.Event) bool {
+		got = append(got, ev)
+		return true
+	}}
+	p.OnLine("install", "Installing dependencies with Composer...")
+	p.OnLine("install", "Dependencies installed successfully.")
+	p.OnLine("install", "Running database migrations...")
+	p.OnLine("install", "Running database seeders...")
+	p.OnLine("install", "All seeders completed successfully.")
+	p.finish()
+
+	labels := []string{}
+	for _, ev := range got {
+		payload, ok := ev.Payload.(domain.TimingPayload)
+		if ev.Type != domain.EventTiming || ev.StepID != "install" || !ok {
+			t.Fatalf("unexpected event %#v", ev)
+		}
+		labels = append(labels, payload.Label)
+	}
+	if strings.Join(labels, ",") != "Composer dependencies,Database migrations,Database seeders" {
+		t.Fatalf("labels = %#v", labels)
+	}
+}
+
+func TestDatabaseImportProgress(t *testing.T) {
+	t.Parallel()
+
+	label, pct, tail, ok := parsePlainProgressLine("Importing database dump [██████████░░░░░░░░░░] 50% (1048576 bytes / 2097152 bytes)")
+	if !ok || label != "Importing database dump" || pct != 50 {
+		t.Fatalf("parsePlainProgressLine = %q, %d, %v", label, pct, ok)
+	}
+	current, total, ok := parseByteProgressTail(tail)
+	if !ok || current != 1<<20 || total != 2<<20 {
+		t.Fatalf("parseByteProgressTail(%q) = %d, %d, %v", tail, current, total, ok)
+	}
+	if _, _, ok := parseByteProgressTail("(1.5 MB / 3 MB)"); ok {
+		t.Fatalf("parseByteProgressTail accepted formatted sizes")
+	}
+
+	var done []domain.Event
+	tracker := newStepTracker(func(ev domain.Event) bool {
+		if ev.Type == domain.EventStepDone {
+			done = append(done, ev)
+		}
+		return true
+	}, "mysql")
+	tracker.OnLine("Importing database dump site.sql.gz...")
+	if got := tracker.CurrentStepID(); got != "install" {
+		t.Fatalf("step = %q, want install", got)
+	}
+	tracker.OnLine("✔ Database import completed successfully.")
+	if len(done) != 1 || done[0].StepID != "install" || !done[0].Payload.(domain.StepDonePayload).OK {
+		t.Fatalf("step done events = %#v", done)
+	}
+}
+
+func TestSplitPlainSeverity(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		line   string
+		stderr bool
+		want   domain.Severity
+		msg    string
+	}{
+		{"[evo:debug] - Installing symfony/error-handler (v7.1.0)", true, domain.SeverityDebug, "- Installing symfony/error-handler (v7.1.0)"},
+		{"[evo:info] Running database migrations...", false, domain.SeverityInfo, "Running database migrations..."},
+		{"[evo:warn] ⚠ composer.json not found.", false, domain.SeverityWarn, "⚠ composer.json not found."},
+		{"[evo:error] ✗ Package discovery failed.", false, domain.SeverityError, "✗ Package discovery failed."},
+		{"Migration failed: table exists", false, domain.SeverityInfo, "Migration failed: table exists"},
+		{"PHP Deprecated: something", true, domain.SeverityWarn, "PHP Deprecated: something"},
+	}
+	for _, tc := range cases {
+		got, msg := splitPlainSeverity(tc.line, tc.stderr)
+		if got != tc.want || msg != tc.msg {
+			t.Fatalf("splitPlainSeverity(%q) = %q, %q, want %q, %q", tc.line, got, msg, tc.want, tc.msg)
+		}
+	}
+}
+
+func TestMaskSensitiveAssignment(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"--db-password=s3cret": "--db-password=<redacted>",
+		"--github-pat=ghp_x":   "--github-pat=<redacted>",
+		"GITHUB_PAT=ghp_x":     "GITHUB_PAT=<redacted>",
+		"--db-host=localhost":  "--db-host=localhost",
+		"install":              "install",
+		"--db-options=sslmode=require&sslpassword=k3y": "--db-options=sslmode=require&sslpassword=<redacted>",
+	}
+	for in, want := range cases {
+		if got := maskSensitiveAssignment(in); got != want {
+			t.Fatalf("maskSensitiveAssignment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestOptionsRedactedMasksSecretDBOptions(t *testing.T) {
+	t.Parallel()
+
+	opt := Options{DBOptions: map[string]string{"sslmode": "require", "sslpassword": "k3y", "auth_token": "t0k"}}
+	got := opt.Redacted().DBOptions
+	if got["sslmode"] != "require" || got["sslpassword"] != "<redacted>" || got["auth_token"] != "<redacted>" {
+		t.Fatalf("Redacted().DBOptions = %v", got)
+	}
+	if opt.DBOptions["sslpassword"] != "k3y" {
+		t.Fatalf("Redacted changed the original options: %v", opt.DBOptions)
+	}
+}
+
+func TestDBTransportApply(t *testing.T) {
+	t.Parallel()
+
+	tr := dbTransport{Socket: "/run/postgresql/.s.PGSQL.5432", SSLMode: "verify-full", SSLCA: "/etc/ca.pem"}
+	pg := tr.apply(dbConfig{Type: "pgsql", Host: "db", Options: map[string]string{"sslmode": "disable", "application_name": "evo"}})
+	if pg.Host != "/run/postgresql" {
+		t.Fatalf("pgsql host = %q, want %q", pg.Host, "/run/postgresql")
+	}
+	if pg.Options["sslmode"] != "verify-full" || pg.Options["sslrootcert"] != "/etc/ca.pem" || pg.Options["application_name"] != "evo" {
+		t.Fatalf("pgsql options = %v", pg.Options)
+	}
+
+	my := dbTransport{Socket: "/run/mysqld/mysqld.sock", SSLMode: "require"}.apply(dbConfig{Type: "mysql", Host: "127.0.0.1"})
+	if my.Host != "localhost" || my.Options["unix_socket"] != "/run/mysqld/mysqld.sock" {
+		t.Fatalf("mysql config = %+v", my)
+	}
+	if my.MySQLSSL == nil || my.MySQLSSL.Verify {
+		t.Fatalf("mysql TLS = %+v, want TLS without verification", my.MySQLSSL)
+	}
+
+	ms := dbTransport{SSLMode: "require"}.apply(dbConfig{Type: "sqlsrv", Host: "db"})
+	if ms.Options["Encrypt"] != "yes" || ms.Options["TrustServerCertificate"] != "yes" {
+		t.Fatalf("sqlsrv options = %v", ms.Options)
+	}
+	if got := (dbTransport{Socket: "/tmp/s", SSLMode: "require"}).unsupported("sqlsrv"); len(got) != 1 || got[0] != "--db-socket" {
+		t.Fatalf("unsupported(sqlsrv) = %v, want [--db-socket]", got)
+	}
+
+	plain := dbTransport{}.apply(dbConfig{Type: "mysql", Host: "db"})
+	if plain.Options != nil || plain.MySQLSSL != nil {
+		t.Fatalf("empty transport changed the config: %+v", plain)
+	}
+}
+
+func TestPhpNewArgsPassesDBExisting(t *testing.T) {
+	t.Parallel()
+
+	base := phpNewOptions{DBType: "mysql", DBHost: "localhost", DBPort: 3306, DBName: "evo", DBUser: "root", DBTablePrefix: "evo_"}
+	hasFlag := func(args []string, flag string) bool {
+		for _, a := range args {
+			if a == flag || strings.HasPrefix(a, flag+"=") {
+				return true
+			}
+		}
+		return false
+	}
+
+	if got := phpNewArgs("bin/evo", base); hasFlag(got, "--db-existing") {
+		t.Fatalf("phpNewArgs() = %v, want no --db-existing", got)
+	}
+
+	keep := base
+	keep.DBExisting = "keep"
+	got := phpNewArgs("bin/evo", keep)
+	if got[0] != "bin/evo" || got[1] != "install" {
+		t.Fatalf("phpNewArgs() = %v, want the install command first", got)
+	}
+	if !hasFlag(got, "--db-existing=keep") {
+		t.Fatalf("phpNewArgs() = %v, want --db-existing=keep", got)
+	}
+}
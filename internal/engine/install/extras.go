@@ -119,13 +119,13 @@ func fetchExtrasList(ctx context.Context, coreDir string, token string) ([]domai
 	args := []string{artisan, "extras", "--list", "--json", "--no-ansi", "--no-interaction"}
 	cmd := exec.CommandContext(ctx, "php", args...)
 	cmd.Dir = coreDir
-	cmd.Env = append([]string(nil), os.Environ()...)
-	cmd.Env = append(cmd.Env, "CI=1")
-	if strings.TrimSpace(token) != "" {
-		cmd.Env = append(cmd.Env, "GITHUB_PAT="+strings.TrimSpace(token))
-	}
+	envAdded := extrasEnv(token)
+	cmd.Env = append(append([]string(nil), os.Environ()...), envAdded...)
 
+	traceExec(ctx, "exec", cmd.Args, cmd.Dir, envAdded)
+	started := time.Now()
 	out, err := cmd.CombinedOutput()
+	traceExit(ctx, "exec", cmd, err, time.Since(started))
 	pkgs, parseErr := parseExtrasListJSON(out)
 	if parseErr == nil {
 		return pkgs, nil
@@ -169,15 +169,24 @@ func runArtisanCommand(ctx context.Context, coreDir string, token string, args [
 	fullArgs := append([]string{artisan}, args...)
 	cmd := exec.CommandContext(ctx, "php", fullArgs...)
 	cmd.Dir = coreDir
-	cmd.Env = append([]string(nil), os.Environ()...)
-	cmd.Env = append(cmd.Env, "CI=1")
-	if strings.TrimSpace(token) != "" {
-		cmd.Env = append(cmd.Env, "GITHUB_PAT="+strings.TrimSpace(token))
-	}
+	envAdded := extrasEnv(token)
+	cmd.Env = append(append([]string(nil), os.Environ()...), envAdded...)
+	traceExec(ctx, "exec", cmd.Args, cmd.Dir, envAdded)
+	started := time.Now()
 	out, err := cmd.CombinedOutput()
+	traceExit(ctx, "exec", cmd, err, time.Since(started))
 	return string(out), err
 }
 
+// extrasEnv returns the variables added to os.Environ for artisan runs.
+func extrasEnv(token string) []string {
+	env := []string{"CI=1"}
+	if strings.TrimSpace(token) != "" {
+		env = append(env, "GITHUB_PAT="+strings.TrimSpace(token))
+	}
+	return env
+}
+
 func absDir(path string) string {
 	if strings.TrimSpace(path) == "" {
 		return path
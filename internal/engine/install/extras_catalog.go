@@ -439,11 +439,14 @@ func loadLegacyStoreCatalog(ctx context.Context) ([]domain.ExtrasPackage, error)
 	req.Header.Set("User-Agent", "EvolutionCMS-Installer/Go")
 
 	client := &http.Client{Timeout: 30 * time.Second}
+	started := time.Now()
 	resp, err := client.Do(req)
 	if err != nil {
+		domain.Tracef(ctx, "http", "POST %s failed after %s: %v", legacyStoreCatalogURL, domain.FormatDuration(time.Since(started)), err)
 		return nil, err
 	}
 	defer resp.Body.Close()
+	domain.Tracef(ctx, "http", "POST %s -> %d after %s", legacyStoreCatalogURL, resp.StatusCode, domain.FormatDuration(time.Since(started)))
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		return nil, fmt.Errorf("unexpected status %s", resp.Status)
 	}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/evolution-cms/installer/internal/domain"
 )
@@ -16,7 +17,9 @@ const (
 	extrasInstallValue = "install"
 )
 
-func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, workDir string, requiredExtras []domain.ExtrasSelection) {
+// maybeRunExtras runs Step 7 and returns the per-item results; nothing is
+// returned when extras were skipped.
+func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, workDir string, requiredExtras []domain.ExtrasSelection) (results []domain.ExtrasItemResult) {
 	if actions == nil {
 		return
 	}
@@ -35,6 +38,7 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Severity: domain.SeverityInfo,
 			Payload: domain.StepStartPayload{
 				Label: "Step 7: Install Extras",
+				Key:   "step.extras",
 				Index: 7,
 				Total: 7,
 			},
@@ -67,6 +71,8 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Severity: domain.SeverityWarn,
 			Payload: domain.LogPayload{
 				Message: "Extras install skipped: " + err.Error(),
+				Key:     "log.extras_skipped_error",
+				Params:  map[string]string{"error": err.Error()},
 			},
 		})
 		stepOK = false
@@ -97,6 +103,8 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Severity: domain.SeverityInfo,
 			Payload: domain.LogPayload{
 				Message: "Preset requires extras: " + strings.Join(labels, ", "),
+				Key:     "log.extras_preset_requires",
+				Params:  map[string]string{"extras": strings.Join(labels, ", ")},
 			},
 		})
 	}
@@ -107,6 +115,7 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 		Severity: domain.SeverityInfo,
 		Payload: domain.LogPayload{
 			Message: "Fetching extras catalogs...",
+			Key:     "log.extras_fetching",
 		},
 	})
 
@@ -136,9 +145,10 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 		})
 	}
 	if err != nil || len(pkgs) == 0 {
-		msg := "Extras catalogs unavailable."
+		msg, key, params := "Extras catalogs unavailable.", "log.extras_catalogs_unavailable", map[string]string(nil)
 		if err != nil {
-			msg = "Extras catalogs unavailable: " + err.Error()
+			msg, key = "Extras catalogs unavailable: "+err.Error(), "log.extras_catalogs_error"
+			params = map[string]string{"error": err.Error()}
 		}
 		_ = emit(domain.Event{
 			Type:     domain.EventWarning,
@@ -147,6 +157,8 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Severity: domain.SeverityWarn,
 			Payload: domain.LogPayload{
 				Message: msg,
+				Key:     key,
+				Params:  params,
 			},
 		})
 		stepOK = false
@@ -198,6 +210,7 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 					Severity: domain.SeverityInfo,
 					Payload: domain.LogPayload{
 						Message: "Extras installation skipped.",
+						Key:     "log.extras_skipped",
 					},
 				})
 				emitExtrasSkippedSummary(emit)
@@ -223,6 +236,7 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 				Severity: domain.SeverityInfo,
 				Payload: domain.LogPayload{
 					Message: "Extras installation skipped.",
+					Key:     "log.extras_skipped",
 				},
 			})
 			emitExtrasSkippedSummary(emit)
@@ -241,6 +255,7 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Severity: domain.SeverityWarn,
 			Payload: domain.LogPayload{
 				Message: "No valid extras selected; skipping.",
+				Key:     "log.extras_none_valid",
 			},
 		})
 		_ = emit(domain.Event{
@@ -268,6 +283,8 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Severity: domain.SeverityInfo,
 			Payload: domain.LogPayload{
 				Message: "Extras preselected: " + strings.Join(selectedLabels, ", "),
+				Key:     "log.extras_preselected",
+				Params:  map[string]string{"extras": strings.Join(selectedLabels, ", ")},
 			},
 		})
 	}
@@ -278,10 +295,12 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 		Severity: domain.SeverityInfo,
 		Payload: domain.LogPayload{
 			Message: "Installing extras: " + strings.Join(selectedLabels, ", "),
+			Key:     "log.extras_installing",
+			Params:  map[string]string{"extras": strings.Join(selectedLabels, ", ")},
 		},
 	})
 
-	results := make([]domain.ExtrasItemResult, 0, len(selections)+2)
+	results = make([]domain.ExtrasItemResult, 0, len(selections)+2)
 	pkgByID := map[string]domain.ExtrasPackage{}
 	for _, pkg := range pkgs {
 		if id := strings.TrimSpace(pkg.ID); id != "" {
@@ -328,10 +347,12 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Payload:  state,
 		})
 
+		itemStarted := time.Now()
 		out, err := runExtrasSelection(ctx, coreDir, token, pkgByID, sel)
-		emitExtrasOutputLogs(emit, extrasStepID, label, out)
+		emitTiming(emit, extrasStepID, "extras", label, time.Since(itemStarted))
 		message := lastNonEmptyLine(out)
 		detectedErr := detectExtrasFailure(out)
+		emitExtrasOutputLogs(emit, extrasStepID, label, out, err != nil || detectedErr != "")
 		if err != nil || detectedErr != "" {
 			state.Results[i].Status = domain.ExtrasStatusError
 			if detectedErr != "" {
@@ -383,8 +404,10 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Payload:  state,
 		})
 
+		artisanStarted := time.Now()
 		out, err := runArtisanCommand(ctx, coreDir, token, []string{"migrate", "--force"})
-		emitExtrasOutputLogs(emit, extrasStepID, "artisan migrate", out)
+		emitTiming(emit, extrasStepID, "extras", "artisan migrate", time.Since(artisanStarted))
+		emitExtrasOutputLogs(emit, extrasStepID, "artisan migrate", out, err != nil)
 		migIdx := len(state.Results) - 1
 		msg := lastNonEmptyLine(out)
 		if err != nil {
@@ -423,8 +446,10 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			Payload:  state,
 		})
 
+		artisanStarted = time.Now()
 		out, err = runArtisanCommand(ctx, coreDir, token, []string{"cache:clear-full"})
-		emitExtrasOutputLogs(emit, extrasStepID, "artisan cache:clear-full", out)
+		emitTiming(emit, extrasStepID, "extras", "artisan cache:clear-full", time.Since(artisanStarted))
+		emitExtrasOutputLogs(emit, extrasStepID, "artisan cache:clear-full", out, err != nil)
 		cacheIdx := len(state.Results) - 1
 		msg = lastNonEmptyLine(out)
 		if err != nil {
@@ -467,6 +492,7 @@ func (e *Engine) maybeRunExtras(ctx context.Context, emit func(domain.Event) boo
 			break
 		}
 	}
+	return state.Results
 }
 
 func waitExtrasDecision(ctx context.Context, actions <-chan domain.Action) (string, []domain.ExtrasSelection, bool) {
@@ -792,10 +818,16 @@ func messageOrError(msg string, err error) string {
 	return fmt.Sprintf("%v", err)
 }
 
-func emitExtrasOutputLogs(emit func(domain.Event) bool, stepID string, label string, out string) {
+// emitExtrasOutputLogs relays the output of an extras command, as warnings
+// when the command failed.
+func emitExtrasOutputLogs(emit func(domain.Event) bool, stepID string, label string, out string, failed bool) {
 	if emit == nil {
 		return
 	}
+	sev := domain.SeverityInfo
+	if failed {
+		sev = domain.SeverityWarn
+	}
 	out = strings.ReplaceAll(out, "\r\n", "\n")
 	lines := strings.Split(out, "\n")
 	for _, line := range lines {
@@ -811,7 +843,7 @@ func emitExtrasOutputLogs(emit func(domain.Event) bool, stepID string, label str
 			Type:     domain.EventLog,
 			StepID:   stepID,
 			Source:   "extras",
-			Severity: domain.SeverityInfo,
+			Severity: sev,
 			Payload: domain.LogPayload{
 				Message: msg,
 			},
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"time"
 )
 
 //go:embed extras_helper.php
@@ -38,6 +39,9 @@ func runExtrasHelper(ctx context.Context, coreDir string, mode string, payload a
 	cmd.Dir = projectPath
 	cmd.Env = append([]string(nil), os.Environ()...)
 	cmd.Env = append(cmd.Env, "CI=1")
+	traceExec(ctx, "exec", cmd.Args, cmd.Dir, []string{"CI=1"})
+	started := time.Now()
 	out, err := cmd.CombinedOutput()
+	traceExit(ctx, "exec", cmd, err, time.Since(started))
 	return string(out), err
 }
@@ -0,0 +1,433 @@
+package install
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestAskPreflightNavigatesBackAndRetestsChangedDatabase(t *testing.T) {
+	t.Parallel()
+
+	tests := 0
+	e := &Engine{
+		opt: Options{DBType: "mysql"},
+		testDB: func(_ context.Context, _ string, cfg dbConfig) (dbTestResult, error) {
+			tests++
+			return dbTestResult{OK: cfg.Name == "evo", Error: "Unknown database"}, nil
+		},
+	}
+
+	input := func(id, text string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerInput, QuestionID: id, Text: text}
+	}
+	selectOpt := func(id, option string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerSelect, QuestionID: id, OptionID: option}
+	}
+	back := func(id string) domain.Action {
+		return domain.Action{Type: domain.ActionBack, QuestionID: id}
+	}
+	script := []domain.Action{
+		input("db_host", "localhost"),
+		input("db_name", "wrong_db"),
+		input("db_user", "root"),
+		back("db_password"),
+		input("db_user", "root"),
+		input("db_password", "pw"),
+		selectOpt("db_retry", "retry"),
+		input("db_host", "localhost"),
+		input("db_name", "evo"),
+		input("db_user", "root"),
+		input("db_password", "pw"),
+		back("admin_username"),
+		input("db_password", "pw"),
+		input("admin_username", "admin"),
+		input("admin_email", "not-an-email"),
+		input("admin_email", "admin@example.com"),
+		input("admin_password", "secret1"),
+		input("admin_directory", "manager"),
+		selectOpt("language", "en"),
+	}
+	actions := make(chan domain.Action, len(script))
+	for _, a := range script {
+		actions <- a
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var questions []domain.QuestionState
+	emit := func(ev domain.Event) bool {
+		if p, ok := ev.Payload.(domain.QuestionPayload); ok {
+			questions = append(questions, p.Question)
+		}
+		return true
+	}
+
+	got, ok := e.askPreflight(ctx, emit, actions, "database", t.TempDir(), domain.SystemStatus{})
+	if !ok {
+		t.Fatalf("askPreflight failed; questions asked: %d", len(questions))
+	}
+	if got.DBName != "evo" || got.DBPassword != "pw" || got.AdminEmail != "admin@example.com" || got.Language != "en" {
+		t.Fatalf("answers = %+v", got)
+	}
+	if tests != 2 {
+		t.Fatalf("connection tests = %d, want 2", tests)
+	}
+
+	revisited := questions[4]
+	if revisited.ID != "db_user" || revisited.Value != "root" || !revisited.CanGoBack {
+		t.Fatalf("revisited question = %+v", revisited)
+	}
+	if questions[0].CanGoBack {
+		t.Fatal("first question must not allow going back")
+	}
+	retried := questions[7]
+	if retried.ID != "db_host" || retried.Value != "localhost" {
+		t.Fatalf("question after retry = %+v", retried)
+	}
+}
+
+func TestAskPreflightRejectsInvalidAnswersInline(t *testing.T) {
+	t.Parallel()
+
+	e := &Engine{
+		opt: Options{
+			DBType:         "mysql",
+			DBHost:         "localhost",
+			DBName:         "evo;drop",
+			DBUser:         "root",
+			DBPassword:     "pw",
+			AdminUsername:  "admin",
+			AdminEmail:     "admin@example.com",
+			AdminPassword:  "secret1",
+			AdminDirectory: "core",
+			Language:       "en",
+		},
+		testDB: func(context.Context, string, dbConfig) (dbTestResult, error) {
+			return dbTestResult{OK: true}, nil
+		},
+	}
+
+	input := func(id, text string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerInput, QuestionID: id, Text: text}
+	}
+	script := []domain.Action{
+		input("db_name", "evo"),
+		input("admin_directory", "my admin"),
+		input("admin_directory", "backend"),
+	}
+	actions := make(chan domain.Action, len(script))
+	for _, a := range script {
+		actions <- a
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var questions []domain.QuestionState
+	var warnings []string
+	emit := func(ev domain.Event) bool {
+		switch p := ev.Payload.(type) {
+		case domain.QuestionPayload:
+			questions = append(questions, p.Question)
+		case domain.LogPayload:
+			if ev.Type == domain.EventWarning {
+				warnings = append(warnings, p.Message)
+			}
+		}
+		return true
+	}
+
+	got, ok := e.askPreflight(ctx, emit, actions, "database", t.TempDir(), domain.SystemStatus{})
+	if !ok {
+		t.Fatalf("askPreflight failed; questions asked: %d", len(questions))
+	}
+	if got.DBName != "evo" || got.AdminDirectory != "backend" {
+		t.Fatalf("answers = %+v", got)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("warnings = %q, want invalid --db-name and --admin-directory", warnings)
+	}
+	if len(questions) != 3 {
+		t.Fatalf("questions asked = %d, want 3", len(questions))
+	}
+	if q := questions[0]; q.ID != "db_name" || q.Value != "evo;drop" || q.Error == "" {
+		t.Fatalf("flag question = %+v", q)
+	}
+	if q := questions[1]; q.ID != "admin_directory" || q.Value != "core" || q.Error == "" {
+		t.Fatalf("flag question = %+v", q)
+	}
+	if q := questions[2]; q.Value != "my admin" || !strings.Contains(q.Error, "letters, digits") {
+		t.Fatalf("re-asked question = %+v", q)
+	}
+}
+
+func TestAskPreflightAcceptsConnectionURL(t *testing.T) {
+	t.Parallel()
+
+	var tested dbConfig
+	e := &Engine{
+		opt: Options{
+			AdminUsername:  "admin",
+			AdminEmail:     "admin@example.com",
+			AdminPassword:  "secret1",
+			AdminDirectory: "manager",
+			Language:       "en",
+		},
+		testDB: func(_ context.Context, _ string, cfg dbConfig) (dbTestResult, error) {
+			tested = cfg
+			return dbTestResult{OK: true}, nil
+		},
+	}
+
+	actions := make(chan domain.Action, 3)
+	actions <- domain.Action{Type: domain.ActionAnswerSelect, QuestionID: "db_driver", OptionID: "url"}
+	actions <- domain.Action{Type: domain.ActionAnswerInput, QuestionID: "db_url", Text: "mysql://evo:p%40ss@db"}
+	actions <- domain.Action{Type: domain.ActionAnswerInput, QuestionID: "db_url", Text: "mariadb://evo:p%40ss@db:3307/site?charset=utf8mb4"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var questions []domain.QuestionState
+	emit := func(ev domain.Event) bool {
+		if p, ok := ev.Payload.(domain.QuestionPayload); ok {
+			questions = append(questions, p.Question)
+		}
+		return true
+	}
+
+	got, ok := e.askPreflight(ctx, emit, actions, "database", t.TempDir(), domain.SystemStatus{})
+	if !ok {
+		t.Fatalf("askPreflight failed; questions asked: %d", len(questions))
+	}
+	if got.DBType != "mysql" || got.DBHost != "db" || got.DBPort != 3307 || got.DBName != "site" || got.DBPassword != "p@ss" {
+		t.Fatalf("answers = %+v", got)
+	}
+	if tested.Options["charset"] != "utf8mb4" {
+		t.Fatalf("tested options = %v, want charset=utf8mb4", tested.Options)
+	}
+	if len(questions) < 3 || questions[2].ID != "db_url" || questions[2].Error == "" {
+		t.Fatalf("URL without a database name was not re-asked: %+v", questions)
+	}
+}
+
+func TestAskPreflightCreatesMissingDatabase(t *testing.T) {
+	t.Parallel()
+
+	opt := Options{
+		DBType:         "mysql",
+		DBHost:         "localhost",
+		DBName:         "evo",
+		DBUser:         "root",
+		DBPassword:     "pw",
+		AdminUsername:  "admin",
+		AdminEmail:     "admin@example.com",
+		AdminPassword:  "secret1",
+		AdminDirectory: "manager",
+		Language:       "en",
+	}
+	run := func(opt Options, canCreate bool, script ...domain.Action) (preflightAnswers, []string, string, bool) {
+		created := false
+		collation := ""
+		e := &Engine{
+			opt: opt,
+			testDB: func(context.Context, string, dbConfig) (dbTestResult, error) {
+				if created {
+					return dbTestResult{OK: true}, nil
+				}
+				return dbTestResult{Error: "Unknown database 'evo'", MissingDB: true, CanCreate: canCreate, GrantError: "the user has no CREATE privilege for this database", ServerVersion: "8.0.36"}, nil
+			},
+			createDB: func(_ context.Context, _ string, _ dbConfig, c string) error {
+				created, collation = true, c
+				return nil
+			},
+		}
+		actions := make(chan domain.Action, len(script))
+		for _, a := range script {
+			actions <- a
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var asked []string
+		emit := func(ev domain.Event) bool {
+			if p, ok := ev.Payload.(domain.QuestionPayload); ok {
+				asked = append(asked, p.Question.ID)
+			}
+			return true
+		}
+		got, ok := e.askPreflight(ctx, emit, actions, "database", t.TempDir(), domain.SystemStatus{})
+		return got, asked, collation, ok
+	}
+	selectOpt := func(id, option string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerSelect, QuestionID: id, OptionID: option}
+	}
+
+	got, asked, collation, ok := run(opt, true, selectOpt("db_create", "create"), selectOpt("db_collation", "utf8mb4_unicode_ci"))
+	if !ok || collation != "utf8mb4_unicode_ci" || got.DBCollation != "utf8mb4_unicode_ci" {
+		t.Fatalf("ok = %v, created with %q, answers = %+v; questions %v", ok, collation, got, asked)
+	}
+
+	flagged := opt
+	flagged.DBCreate = true
+	_, asked, collation, ok = run(flagged, true)
+	if !ok || len(asked) != 0 || collation != "utf8mb4_0900_ai_ci" {
+		t.Fatalf("--db-create: ok = %v, questions %v, collation %q", ok, asked, collation)
+	}
+
+	_, asked, collation, ok = run(flagged, false, selectOpt("db_retry", "exit"))
+	if ok || collation != "" || len(asked) != 1 || asked[0] != "db_retry" {
+		t.Fatalf("without privileges: ok = %v, questions %v, collation %q", ok, asked, collation)
+	}
+}
+
+func TestAskPreflightHandlesExistingTables(t *testing.T) {
+	t.Parallel()
+
+	tables := []string{"evo_site_content", "evo_system_settings", "evo2_site_content", "wp_posts"}
+	if got := evolutionTablePrefixes(tables); strings.Join(got, ",") != "evo2_,evo_" {
+		t.Fatalf("evolutionTablePrefixes() = %q, want [evo2_ evo_]", got)
+	}
+
+	opt := Options{
+		DBType:         "sqlite",
+		DBName:         "database.sqlite",
+		AdminUsername:  "admin",
+		AdminEmail:     "admin@example.com",
+		AdminPassword:  "secret1",
+		AdminDirectory: "manager",
+		Language:       "en",
+	}
+	run := func(opt Options, script ...domain.Action) (preflightAnswers, []domain.QuestionState, bool) {
+		e := &Engine{
+			opt: opt,
+			testDB: func(context.Context, string, dbConfig) (dbTestResult, error) {
+				return dbTestResult{OK: true, Tables: tables}, nil
+			},
+		}
+		actions := make(chan domain.Action, len(script))
+		for _, a := range script {
+			actions <- a
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		var asked []domain.QuestionState
+		emit := func(ev domain.Event) bool {
+			if p, ok := ev.Payload.(domain.QuestionPayload); ok {
+				asked = append(asked, p.Question)
+			}
+			return true
+		}
+		got, ok := e.askPreflight(ctx, emit, actions, "database", t.TempDir(), domain.SystemStatus{})
+		return got, asked, ok
+	}
+	selectOpt := func(id, option string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerSelect, QuestionID: id, OptionID: option}
+	}
+	input := func(id, text string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerInput, QuestionID: id, Text: text}
+	}
+
+	got, asked, ok := run(opt, selectOpt("db_existing", "prefix"), input("db_table_prefix", "evo2_"), input("db_table_prefix", ""))
+	if !ok || got.DBTablePrefix != "evo3_" || got.DBExisting != "" {
+		t.Fatalf("ok = %v, prefix = %q, existing = %q", ok, got.DBTablePrefix, got.DBExisting)
+	}
+	if len(asked) != 3 || asked[2].ErrorKey != "validate.table_prefix_taken" || asked[1].Default != "evo3_" {
+		t.Fatalf("questions = %+v", asked)
+	}
+	if n := len(asked[0].Options); n != 4 {
+		t.Fatalf("db_existing offers %d options, want 4 including keep", n)
+	}
+
+	flagged := opt
+	flagged.DBExisting = "drop"
+	got, asked, ok = run(flagged)
+	if !ok || len(asked) != 0 || got.DBExisting != "drop" || strings.Join(got.DBDropTables, ",") != "evo_site_content,evo_system_settings" {
+		t.Fatalf("--db-existing=drop: ok = %v, questions %d, answers = %+v", ok, len(asked), got)
+	}
+
+	// keep only applies to an Evolution installation; wp_ is not one.
+	flagged.DBExisting, flagged.DBTablePrefix = "keep", "wp_"
+	_, asked, ok = run(flagged, selectOpt("db_existing", "abort"))
+	if ok || len(asked) != 1 || len(asked[0].Options) != 3 {
+		t.Fatalf("--db-existing=keep on wp_: ok = %v, questions %+v", ok, asked)
+	}
+
+	free := opt
+	free.DBTablePrefix = "site_"
+	if got, asked, ok = run(free); !ok || len(asked) != 0 || got.DBTablePrefix != "site_" {
+		t.Fatalf("free prefix: ok = %v, questions %d, prefix %q", ok, len(asked), got.DBTablePrefix)
+	}
+}
+
+func TestDropExistingTablesBacksUpFirst(t *testing.T) {
+	t.Parallel()
+
+	var reqs []dbTablesRequest
+	e := &Engine{dbTables: func(_ context.Context, _ string, r dbTablesRequest) error {
+		reqs = append(reqs, r)
+		return nil
+	}}
+	workDir := filepath.Join(t.TempDir(), "site")
+	plan := installPlan{
+		WorkDir: workDir,
+		Answers: preflightAnswers{
+			DBType:        "sqlite",
+			DBName:        "database.sqlite",
+			DBTablePrefix: "evo_",
+			DBExisting:    "drop",
+			DBDropTables:  []string{"evo_site_content"},
+		},
+	}
+	if !e.dropExistingTables(context.Background(), func(domain.Event) bool { return true }, reviewStepID, &plan) {
+		t.Fatalf("dropExistingTables failed")
+	}
+	if len(reqs) != 2 || reqs[0].Drop || !reqs[1].Drop || reqs[1].File != "" || len(reqs[1].Tables) != 1 {
+		t.Fatalf("requests = %+v, want a dump then a drop", reqs)
+	}
+	dump := reqs[0]
+	if len(dump.Tables) != 1 || filepath.Dir(dump.File) != workDir+"-backups" {
+		t.Fatalf("dump request = %+v", dump)
+	}
+	if len(plan.Backups) != 1 || plan.Backups[0] != dump.File || !strings.HasPrefix(filepath.Base(dump.File), "database-evo_tables-") {
+		t.Fatalf("backups = %q, file %q", plan.Backups, dump.File)
+	}
+
+	// SQL Server tables cannot be backed up with their structure, so they
+	// are not dropped.
+	reqs = nil
+	plan = installPlan{
+		WorkDir: workDir,
+		Answers: preflightAnswers{
+			DBType:        "sqlsrv",
+			DBName:        "evo",
+			DBTablePrefix: "evo_",
+			DBExisting:    "drop",
+			DBDropTables:  []string{"evo_site_content"},
+		},
+	}
+	if e.dropExistingTables(context.Background(), func(domain.Event) bool { return true }, reviewStepID, &plan) || len(reqs) != 0 {
+		t.Fatalf("sqlsrv drop went ahead: requests = %+v", reqs)
+	}
+}
+
+func TestCheckURLDriverRejectsUnavailableDriver(t *testing.T) {
+	t.Parallel()
+
+	opts := []domain.QuestionOption{
+		{ID: "mysql", Enabled: true},
+		{ID: "pgsql", Enabled: false, Reason: "Missing PDO driver: pdo_pgsql"},
+	}
+	if err := checkURLDriver(opts, "mysql://evo:pw@db/site"); err != nil {
+		t.Fatalf("checkURLDriver(mysql) = %v, want nil", err)
+	}
+	err := checkURLDriver(opts, "postgres://evo:pw@db/site")
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) || verr.Key != "validate.db_url_driver" || !strings.Contains(err.Error(), "pdo_pgsql") {
+		t.Fatalf("checkURLDriver(pgsql) = %v, want a validate.db_url_driver error", err)
+	}
+}
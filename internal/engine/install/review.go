@@ -0,0 +1,342 @@
+package install
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+const (
+	reviewStepID       = "review"
+	reviewQuestionID   = "review_install"
+	reviewEditID       = "review_edit"
+	reviewPresetField  = "project_preset"
+	reviewMaskedSecret = "••••••••"
+)
+
+// installPlan is everything the review screen lists before files are written.
+type installPlan struct {
+	WorkDir string
+	Version string
+	Answers preflightAnswers
+	Preset  string
+	Extras  []domain.ExtrasSelection
+	// Transport is shown with the database settings; it is not editable.
+	Transport dbTransport
+	// Import is the SQL dump loaded instead of the seeders.
+	Import string
+	// BackupDir is where the backup before a --force reinstall goes; empty
+	// when no backup is taken. Backups are the files written before the
+	// install; DBBackups are the database dumps among them, which a failed
+	// --db-import points to.
+	BackupDir string
+	Backups   []string
+	DBBackups []string
+}
+
+// reviewVersion describes what will be downloaded: the requested branch or the
+// latest stable release.
+func reviewVersion(branch string, release domain.ReleaseInfo) string {
+	if branch = strings.TrimSpace(branch); branch != "" {
+		return "branch " + branch
+	}
+	tag := release.Tag
+	if tag == "" && release.HighestVersion != "" {
+		tag = "v" + release.HighestVersion
+	}
+	if tag == "" {
+		return "latest stable release"
+	}
+	return "latest stable release (" + tag + ")"
+}
+
+// details renders the plan as review rows with passwords masked.
+func (p installPlan) details() []domain.QuestionDetail {
+	a := p.Answers
+	row := func(field, label, value string) domain.QuestionDetail {
+		return domain.QuestionDetail{Label: label, Value: value, Key: "field." + field}
+	}
+	masked := func(field, label, v string) domain.QuestionDetail {
+		if strings.TrimSpace(v) == "" {
+			d := row(field, label, "(empty)")
+			d.ValueKey = "value.empty"
+			return d
+		}
+		return row(field, label, reviewMaskedSecret)
+	}
+
+	rows := []domain.QuestionDetail{
+		row("target_dir", "Target directory", absDir(p.WorkDir)),
+		row("version", "Evolution CMS", p.Version),
+		row("db_driver", "Database driver", dbDriverLabel(a.DBType)),
+	}
+	if a.DBType == "sqlite" {
+		rows = append(rows, row("db_sqlite_path", "Database file", a.DBName))
+	} else {
+		host := a.DBHost
+		if a.DBPort > 0 {
+			host = fmt.Sprintf("%s:%d", a.DBHost, a.DBPort)
+		}
+		if p.Transport.Socket != "" {
+			host = p.Transport.Socket
+		}
+		rows = append(rows,
+			row("db_host", "Database host", host),
+			row("db_name", "Database name", a.DBName),
+			row("db_user", "Database user", a.DBUser),
+			masked("db_password", "Database password", a.DBPassword),
+		)
+		if t := p.Transport; t.SSLMode != "" || t.tls() {
+			tls := t.SSLMode
+			if tls == "" {
+				tls = "prefer"
+			}
+			if t.SSLCA != "" {
+				tls += ", CA " + t.SSLCA
+			}
+			if t.SSLCert != "" {
+				tls += ", client certificate " + t.SSLCert
+			}
+			rows = append(rows, row("db_tls", "Database TLS", tls))
+		}
+	}
+	rows = append(rows, row("db_table_prefix", "Table prefix", a.DBTablePrefix))
+	switch a.DBExisting {
+	case "drop":
+		d := row("db_existing", "Existing tables", "back up and drop")
+		d.ValueKey = "value.db_existing_drop"
+		rows = append(rows, d)
+	case "keep":
+		d := row("db_existing", "Existing tables", "keep and update")
+		d.ValueKey = "value.db_existing_keep"
+		rows = append(rows, d)
+	}
+	if p.Import != "" {
+		rows = append(rows, row("db_import", "Import dump", p.Import))
+	}
+
+	if p.BackupDir != "" {
+		rows = append(rows, row("backup", "Backup", p.BackupDir))
+	}
+
+	presetRow := row(reviewPresetField, "Project preset", p.Preset)
+	if p.Preset == "" || p.Preset == "evolution" {
+		presetRow.Value, presetRow.ValueKey = "Evolution core only", "value.core_only"
+	}
+	extras := make([]string, 0, len(p.Extras))
+	for _, sel := range p.Extras {
+		name := strings.TrimSpace(sel.Name)
+		if name == "" {
+			name = sel.ID
+		}
+		if v := strings.TrimSpace(sel.Version); v != "" {
+			name += "@" + v
+		}
+		extras = append(extras, name)
+	}
+	extrasRow := row("extras", "Extras", strings.Join(extras, ", "))
+	if extrasRow.Value == "" {
+		extrasRow.Value, extrasRow.ValueKey = "none (chosen after install)", "value.extras_later"
+	}
+
+	return append(rows,
+		row("admin_username", "Admin username", a.AdminUsername),
+		row("admin_email", "Admin email", a.AdminEmail),
+		masked("admin_password", "Admin password", a.AdminPassword),
+		row("admin_directory", "Admin directory", a.AdminDirectory),
+		row("language", "Language", languageLabel(a.Language)),
+		presetRow,
+		extrasRow,
+	)
+}
+
+// runReviewStep is the unnumbered step between the preset and the download:
+// the review screen, then the backup and table drop it may have asked for.
+func (e *Engine) runReviewStep(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, sysStatus domain.SystemStatus, plan *installPlan) bool {
+	_ = emit(domain.Event{
+		Type:     domain.EventStepStart,
+		StepID:   reviewStepID,
+		Source:   "install",
+		Severity: domain.SeverityInfo,
+		Payload: domain.StepStartPayload{
+			Label: "Review installation settings",
+			Key:   "step.review",
+		},
+	})
+	ok := e.reviewInstall(ctx, emit, actions, sysStatus, plan) &&
+		e.backupBeforeForce(ctx, emit, reviewStepID, plan) &&
+		e.dropExistingTables(ctx, emit, reviewStepID, plan)
+	sev := domain.SeverityInfo
+	if !ok {
+		sev = domain.SeverityWarn
+	}
+	_ = emit(domain.Event{
+		Type:     domain.EventStepDone,
+		StepID:   reviewStepID,
+		Source:   "install",
+		Severity: sev,
+		Payload:  domain.StepDonePayload{OK: ok},
+	})
+	return ok
+}
+
+// reviewInstall shows the collected settings and waits for Confirm, Cancel or
+// an edit of a single field. With --yes the settings are only logged.
+func (e *Engine) reviewInstall(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, sysStatus domain.SystemStatus, plan *installPlan) bool {
+	logLine := func(msg string, key string) {
+		_ = emit(domain.Event{
+			Type:     domain.EventLog,
+			StepID:   reviewStepID,
+			Source:   "install",
+			Severity: domain.SeverityInfo,
+			Payload: domain.LogPayload{
+				Message: msg,
+				Key:     key,
+			},
+		})
+	}
+
+	if e.opt.Yes {
+		logLine("Installation settings (confirmed with --yes):", "log.review_yes")
+		for _, d := range plan.details() {
+			logLine("  "+d.Label+": "+d.Value, "")
+		}
+		return true
+	}
+
+	driverOpts := dbDriverQuestionOptions(sysStatus)
+	questions := preflightQuestions(driverOpts, enabledOptionIndexes(driverOpts), e.opt.DBPort)
+	tested := plan.Answers.dbFingerprint()
+
+	for {
+		choice, ok := askSelect(ctx, emit, actions, reviewStepID, domain.QuestionState{
+			Active:  true,
+			ID:      reviewQuestionID,
+			Kind:    domain.QuestionSelect,
+			Prompt:  "Review the installation settings before any files are written:",
+			Details: plan.details(),
+			Options: []domain.QuestionOption{
+				{ID: "confirm", Label: "Confirm and install", Enabled: true, Key: "option.review.confirm"},
+				{ID: "edit", Label: "Edit a field", Enabled: true, Key: "option.review.edit"},
+				{ID: "cancel", Label: "Cancel installation", Enabled: true, Key: "option.review.cancel"},
+			},
+		})
+		if !ok {
+			return false
+		}
+
+		switch choice {
+		case "confirm":
+			logLine("Installation settings confirmed.", "log.review_confirmed")
+			return true
+		case "edit":
+			field, back, ok := askQuestion(ctx, emit, actions, reviewStepID, reviewEditQuestion(questions, &plan.Answers))
+			if !ok {
+				return false
+			}
+			if back {
+				continue
+			}
+			if field == reviewPresetField {
+				preset, ok := askProjectPreset(ctx, emit, actions, reviewStepID, plan.Preset)
+				if !ok {
+					return false
+				}
+				plan.Preset = preset
+				continue
+			}
+			if !e.editPreflightField(ctx, emit, actions, plan.WorkDir, questions, field, &plan.Answers, &tested) {
+				return false
+			}
+		default:
+			_ = emit(domain.Event{
+				Type:     domain.EventError,
+				StepID:   reviewStepID,
+				Source:   "install",
+				Severity: domain.SeverityError,
+				Payload: domain.LogPayload{
+					Message: "Installation cancelled by user.",
+					Key:     "log.cancelled_by_user",
+				},
+			})
+			return false
+		}
+	}
+}
+
+// reviewEditQuestion lists the fields that apply to the current answers.
+func reviewEditQuestion(questions []preflightQuestion, a *preflightAnswers) domain.QuestionState {
+	opts := []domain.QuestionOption{}
+	for _, q := range questions {
+		if q.label == "" || (q.skip != nil && q.skip(a)) {
+			continue
+		}
+		opts = append(opts, domain.QuestionOption{ID: q.id, Label: q.label, Enabled: true, Key: "field." + q.id})
+	}
+	opts = append(opts, domain.QuestionOption{ID: reviewPresetField, Label: "Project preset", Enabled: true, Key: "field." + reviewPresetField})
+	return domain.QuestionState{
+		Active:    true,
+		ID:        reviewEditID,
+		Kind:      domain.QuestionSelect,
+		Prompt:    "Which setting do you want to change?",
+		Options:   opts,
+		CanGoBack: true,
+	}
+}
+
+// editPreflightField re-asks one Step 2 question from the review screen.
+// Changing the driver walks through the other database questions, and changed
+// connection settings are tested again. Going back discards the edit.
+func (e *Engine) editPreflightField(ctx context.Context, emit func(domain.Event) bool, actions <-chan domain.Action, workDir string, questions []preflightQuestion, field string, a *preflightAnswers, tested *string) bool {
+	orig := *a
+	dbIDs := []string{}
+	byID := map[string]preflightQuestion{}
+	for _, q := range questions {
+		byID[q.id] = q
+		if q.db && q.id != preflightCheckDB {
+			dbIDs = append(dbIDs, q.id)
+		}
+	}
+
+	pending := []string{field}
+	if field == "db_driver" {
+		pending = dbIDs
+	}
+	for len(pending) > 0 {
+		q, found := byID[pending[0]]
+		if !found || (q.skip != nil && q.skip(a)) {
+			pending = pending[1:]
+			continue
+		}
+
+		back, ok := askPreflightQuestion(ctx, emit, actions, reviewStepID, q, a, true, nil)
+		if !ok {
+			return false
+		}
+		if back {
+			*a = orig
+			return true
+		}
+		pending = pending[1:]
+		if len(pending) > 0 || !q.db {
+			continue
+		}
+
+		a.normalize()
+		if a.dbFingerprint() == *tested {
+			continue
+		}
+		okConn, retry := e.checkPreflightDatabase(ctx, emit, actions, reviewStepID, workDir, a)
+		switch {
+		case okConn:
+			*tested = a.dbFingerprint()
+		case retry:
+			pending = dbIDs
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package install
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestReviewInstallEditsFieldAndMasksSecrets(t *testing.T) {
+	t.Parallel()
+
+	tests := 0
+	e := &Engine{
+		testDB: func(_ context.Context, _ string, cfg dbConfig) (dbTestResult, error) {
+			tests++
+			return dbTestResult{OK: true}, nil
+		},
+	}
+
+	selectOpt := func(id, option string) domain.Action {
+		return domain.Action{Type: domain.ActionAnswerSelect, QuestionID: id, OptionID: option}
+	}
+	script := []domain.Action{
+		selectOpt(reviewQuestionID, "edit"),
+		selectOpt(reviewEditID, "db_name"),
+		{Type: domain.ActionAnswerInput, QuestionID: "db_name", Text: "evo_prod"},
+		selectOpt(reviewQuestionID, "edit"),
+		{Type: domain.ActionBack, QuestionID: reviewEditID},
+		selectOpt(reviewQuestionID, "edit"),
+		selectOpt(reviewEditID, "admin_email"),
+		{Type: domain.ActionBack, QuestionID: "admin_email"},
+		selectOpt(reviewQuestionID, "confirm"),
+	}
+	actions := make(chan domain.Action, len(script))
+	for _, a := range script {
+		actions <- a
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var reviews []domain.QuestionState
+	emit := func(ev domain.Event) bool {
+		if p, ok := ev.Payload.(domain.QuestionPayload); ok && p.Question.ID == reviewQuestionID {
+			reviews = append(reviews, p.Question)
+		}
+		return true
+	}
+
+	plan := installPlan{
+		WorkDir: t.TempDir(),
+		Version: reviewVersion("", domain.ReleaseInfo{HighestVersion: "3.5.2"}),
+		Answers: preflightAnswers{
+			DBType:         "mysql",
+			DBHost:         "localhost",
+			DBPort:         3306,
+			DBName:         "evo",
+			DBUser:         "root",
+			DBPassword:     "db-secret",
+			AdminUsername:  "admin",
+			AdminEmail:     "admin@example.com",
+			AdminPassword:  "admin-secret",
+			AdminDirectory: "manager",
+			Language:       "en",
+		},
+		Preset: "evolution",
+	}
+	if !e.reviewInstall(ctx, emit, actions, domain.SystemStatus{}, &plan) {
+		t.Fatalf("reviewInstall failed; reviews shown: %d", len(reviews))
+	}
+	if plan.Answers.DBName != "evo_prod" || plan.Answers.AdminEmail != "admin@example.com" {
+		t.Fatalf("answers = %+v", plan.Answers)
+	}
+	if tests != 1 {
+		t.Fatalf("connection tests = %d, want 1", tests)
+	}
+	if len(reviews) != 4 {
+		t.Fatalf("review screens = %d, want 4", len(reviews))
+	}
+
+	values := map[string]string{}
+	for _, d := range reviews[len(reviews)-1].Details {
+		values[d.Label] = d.Value
+	}
+	want := map[string]string{
+		"Evolution CMS":     "latest stable release (v3.5.2)",
+		"Database host":     "localhost:3306",
+		"Database name":     "evo_prod",
+		"Database password": reviewMaskedSecret,
+		"Admin password":    reviewMaskedSecret,
+		"Project preset":    "Evolution core only",
+	}
+	for label, v := range want {
+		if values[label] != v {
+			t.Fatalf("%s = %q, want %q", label, values[label], v)
+		}
+	}
+}
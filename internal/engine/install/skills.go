@@ -9,7 +9,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -22,14 +24,28 @@ const (
 	skillsStateSchema     = "evo.skills.install-state.v1"
 	skillsManifestVersion = "evo.skills.manifest.v1"
 	skillsWorkflowVersion = "evo.skills.workflow.v1"
+	skillsDefaultTarget   = "default"
+	skillsDefaultRoot     = "core/custom/skills"
+	skillsLockfileName    = ".evo-skills.lock.json"
 )
 
 type skillsManifest struct {
-	SchemaVersion  string                `json:"schema_version"`
-	InstallRoot    string                `json:"install_root"`
-	Lockfile       string                `json:"lockfile"`
-	DefaultInstall []string              `json:"default_install"`
-	Skills         []skillsManifestEntry `json:"skills"`
+	SchemaVersion  string                 `json:"schema_version"`
+	InstallRoot    string                 `json:"install_root"`
+	Lockfile       string                 `json:"lockfile"`
+	DefaultInstall []string               `json:"default_install"`
+	Targets        []skillsManifestTarget `json:"targets,omitempty"`
+	Skills         []skillsManifestEntry  `json:"skills"`
+}
+
+// skillsManifestTarget describes a named install layout. Rename maps a file
+// path inside the skill directory to a new path ({name} is the skill name);
+// Index, when set, is a generated markdown list of installed skills.
+type skillsManifestTarget struct {
+	Name        string            `json:"name"`
+	InstallRoot string            `json:"install_root"`
+	Rename      map[string]string `json:"rename,omitempty"`
+	Index       string            `json:"index,omitempty"`
 }
 
 type skillsManifestEntry struct {
@@ -55,8 +71,29 @@ type skillsInstallPlan struct {
 	Mode            string
 	DryRun          bool
 	Selected        []string
+	Targets         []skillsTargetPlan
 	InstalledSkills []skillsInstalledItem
 	Operations      []skillsInstallOperation
+	// KeptTargets are lockfile targets from earlier installs that this run
+	// does not touch; they stay in the lockfile.
+	KeptTargets []skillsInstallStateTarget
+}
+
+type skillsTargetPlan struct {
+	Name   string
+	Root   string
+	Rename map[string]string
+	Index  string
+	Skills []skillsTargetItem
+}
+
+// skillsTargetItem is one skill installed into a target. SkillHash is the
+// hash of SkillFile as written, so verify can detect local edits.
+type skillsTargetItem struct {
+	Name       string `json:"name"`
+	TargetPath string `json:"target_path"`
+	SkillFile  string `json:"skill_file"`
+	SkillHash  string `json:"skill_hash,omitempty"`
 }
 
 type skillsInstalledItem struct {
@@ -120,22 +157,32 @@ type skillsWorkflowStageEvidence struct {
 }
 
 type skillsInstallOperation struct {
-	Kind      string `json:"kind"`
-	Source    string `json:"source,omitempty"`
-	Target    string `json:"target,omitempty"`
-	Ownership string `json:"ownership,omitempty"`
-	Status    string `json:"status"`
+	Kind       string `json:"kind"`
+	TargetName string `json:"target_name,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Target     string `json:"target,omitempty"`
+	Ownership  string `json:"ownership,omitempty"`
+	Status     string `json:"status"`
 }
 
 type skillsInstallState struct {
-	SchemaVersion   string                   `json:"schema_version"`
-	InstalledAt     string                   `json:"installed_at"`
-	ProjectRoot     string                   `json:"project_root"`
-	SkillsRoot      string                   `json:"skills_root"`
-	Mode            string                   `json:"mode"`
-	Source          skillsInstallStateSource `json:"source"`
-	InstalledSkills []skillsInstalledItem    `json:"installed_skills"`
-	Operations      []skillsInstallOperation `json:"operations,omitempty"`
+	SchemaVersion   string                     `json:"schema_version"`
+	InstalledAt     string                     `json:"installed_at"`
+	ProjectRoot     string                     `json:"project_root"`
+	SkillsRoot      string                     `json:"skills_root"`
+	Mode            string                     `json:"mode"`
+	Source          skillsInstallStateSource   `json:"source"`
+	InstalledSkills []skillsInstalledItem      `json:"installed_skills"`
+	Targets         []skillsInstallStateTarget `json:"targets,omitempty"`
+	Operations      []skillsInstallOperation   `json:"operations,omitempty"`
+}
+
+type skillsInstallStateTarget struct {
+	Name       string                   `json:"name"`
+	Root       string                   `json:"root"`
+	Index      string                   `json:"index,omitempty"`
+	Skills     []skillsTargetItem       `json:"skills"`
+	Operations []skillsInstallOperation `json:"operations"`
 }
 
 type skillsInstallStateSource struct {
@@ -158,6 +205,7 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 		Severity: domain.SeverityInfo,
 		Payload: domain.StepStartPayload{
 			Label: "Install EVO Skills",
+			Key:   "step.skills",
 			Index: 8,
 			Total: 8,
 		},
@@ -171,6 +219,7 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 			Severity: domain.SeverityError,
 			Payload: domain.LogPayload{
 				Message: "EVO skills install cancelled.",
+				Key:     "log.skills_cancelled",
 				Fields:  map[string]string{"error": err.Error()},
 			},
 		})
@@ -193,6 +242,7 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 			Severity: domain.SeverityError,
 			Payload: domain.LogPayload{
 				Message: "EVO skills install failed.",
+				Key:     "log.skills_failed",
 				Fields:  map[string]string{"error": err.Error()},
 			},
 		})
@@ -214,6 +264,7 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 			Severity: domain.SeverityInfo,
 			Payload: domain.LogPayload{
 				Message: "EVO skills install skipped (--skills=none).",
+				Key:     "log.skills_skipped",
 			},
 		})
 		_ = emit(domain.Event{
@@ -232,7 +283,13 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 		Source:   "skills",
 		Severity: domain.SeverityInfo,
 		Payload: domain.LogPayload{
-			Message: fmt.Sprintf("Planned EVO skills install: %s (%s mode).", strings.Join(plan.Selected, ", "), plan.Mode),
+			Message: fmt.Sprintf("Planned EVO skills install: %s (%s mode, targets: %s).", strings.Join(plan.Selected, ", "), plan.Mode, strings.Join(plan.targetNames(), ", ")),
+			Key:     "log.skills_planned",
+			Params: map[string]string{
+				"skills":  strings.Join(plan.Selected, ", "),
+				"mode":    plan.Mode,
+				"targets": strings.Join(plan.targetNames(), ", "),
+			},
 		},
 	})
 	for _, item := range plan.InstalledSkills {
@@ -251,6 +308,12 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 					item.Workflow.WorkflowID,
 					strings.Join(item.Workflow.ResolvedOrder, " -> "),
 				),
+				Key: "log.skills_workflow",
+				Params: map[string]string{
+					"skill":    item.Name,
+					"workflow": item.Workflow.WorkflowID,
+					"order":    strings.Join(item.Workflow.ResolvedOrder, " -> "),
+				},
 				Fields: map[string]string{
 					"autorun":                   "false",
 					"no_write_actions_executed": "true",
@@ -268,6 +331,7 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 				Severity: domain.SeverityError,
 				Payload: domain.LogPayload{
 					Message: "EVO skills install failed.",
+					Key:     "log.skills_failed",
 					Fields:  map[string]string{"error": err.Error()},
 				},
 			})
@@ -282,9 +346,9 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 		}
 	}
 
-	msg := "EVO skills dry-run completed; no files written."
+	msg, key := "EVO skills dry-run completed; no files written.", "log.skills_dry_run"
 	if !plan.DryRun {
-		msg = "EVO skills installed and lockfile written."
+		msg, key = "EVO skills installed and lockfile written.", "log.skills_installed"
 	}
 	_ = emit(domain.Event{
 		Type:     domain.EventLog,
@@ -293,6 +357,7 @@ func (e *Engine) maybeRunSkillsInstall(ctx context.Context, emit func(domain.Eve
 		Severity: domain.SeverityInfo,
 		Payload: domain.LogPayload{
 			Message: msg,
+			Key:     key,
 		},
 	})
 	_ = emit(domain.Event{
@@ -328,10 +393,10 @@ func planSkillsInstall(opt Options, workDir string) (skillsInstallPlan, error) {
 		return skillsInstallPlan{}, err
 	}
 	if strings.TrimSpace(manifest.InstallRoot) == "" {
-		manifest.InstallRoot = "core/custom/skills"
+		manifest.InstallRoot = skillsDefaultRoot
 	}
 	if strings.TrimSpace(manifest.Lockfile) == "" {
-		manifest.Lockfile = filepath.Join(manifest.InstallRoot, ".evo-skills.lock.json")
+		manifest.Lockfile = filepath.Join(manifest.InstallRoot, skillsLockfileName)
 	}
 
 	selected, err := resolveSkillsSelection(opt.Skills, manifest)
@@ -347,8 +412,16 @@ func planSkillsInstall(opt Options, workDir string) (skillsInstallPlan, error) {
 			DryRun:       opt.SkillsDryRun,
 		}, nil
 	}
+	targets, err := resolveSkillsTargets(opt.SkillsTargets, manifest)
+	if err != nil {
+		return skillsInstallPlan{}, err
+	}
+	for _, target := range targets {
+		if mode == "link" && len(target.Rename) > 0 {
+			return skillsInstallPlan{}, fmt.Errorf("skills target %q renames files and cannot be used with --skills-link", target.Name)
+		}
+	}
 
-	installRoot := filepath.Join(projectRoot, filepath.FromSlash(manifest.InstallRoot))
 	lockfilePath := filepath.Join(projectRoot, filepath.FromSlash(manifest.Lockfile))
 	previousState, _ := readSkillsInstallState(lockfilePath)
 
@@ -362,14 +435,35 @@ func planSkillsInstall(opt Options, workDir string) (skillsInstallPlan, error) {
 		SourceRoot:   sourceRoot,
 		SourceRef:    strings.TrimSpace(opt.SkillsRef),
 		ManifestPath: manifestPath,
-		InstallRoot:  installRoot,
+		InstallRoot:  filepath.Join(projectRoot, filepath.FromSlash(targets[0].InstallRoot)),
 		LockfilePath: lockfilePath,
 		Mode:         mode,
 		DryRun:       opt.SkillsDryRun,
 		Selected:     selected,
-		Operations: []skillsInstallOperation{
-			{Kind: "mkdir", Target: filepath.ToSlash(manifest.InstallRoot), Ownership: "managed", Status: "planned"},
-		},
+	}
+	for _, previous := range stateTargets(previousState) {
+		kept := true
+		for _, target := range targets {
+			kept = kept && target.Name != previous.Name
+		}
+		if kept {
+			plan.KeptTargets = append(plan.KeptTargets, previous)
+		}
+	}
+	for _, target := range targets {
+		plan.Targets = append(plan.Targets, skillsTargetPlan{
+			Name:   target.Name,
+			Root:   filepath.ToSlash(target.InstallRoot),
+			Rename: target.Rename,
+			Index:  filepath.ToSlash(target.Index),
+		})
+		plan.Operations = append(plan.Operations, skillsInstallOperation{
+			Kind:       "mkdir",
+			TargetName: target.Name,
+			Target:     filepath.ToSlash(target.InstallRoot),
+			Ownership:  "managed",
+			Status:     "planned",
+		})
 	}
 
 	for _, name := range selected {
@@ -382,10 +476,6 @@ func planSkillsInstall(opt Options, workDir string) (skillsInstallPlan, error) {
 		}
 		sourceDir := filepath.Join(sourceRoot, filepath.FromSlash(item.SourcePath))
 		sourceFile := filepath.Join(sourceRoot, filepath.FromSlash(item.SkillFile))
-		if strings.TrimSpace(item.InstallTarget) == "" {
-			item.InstallTarget = filepath.ToSlash(filepath.Join(manifest.InstallRoot, item.Name))
-		}
-		targetDir := filepath.Join(projectRoot, filepath.FromSlash(item.InstallTarget))
 
 		if st, err := os.Stat(sourceDir); err != nil || !st.IsDir() {
 			return skillsInstallPlan{}, fmt.Errorf("skill %q source directory is not readable: %s", name, sourceDir)
@@ -418,55 +508,54 @@ func planSkillsInstall(opt Options, workDir string) (skillsInstallPlan, error) {
 			})
 		}
 
-		ownership := "managed"
-		if _, err := os.Lstat(targetDir); err == nil {
-			if mode == "link" && symlinkPointsTo(targetDir, sourceDir) {
-				plan.Operations = append(plan.Operations, skillsInstallOperation{
-					Kind:      "skip",
-					Source:    filepath.ToSlash(sourceDir),
-					Target:    filepath.ToSlash(item.InstallTarget),
-					Ownership: "managed",
-					Status:    "planned",
-				})
-				plan.InstalledSkills = append(plan.InstalledSkills, skillsInstalledItem{
-					Name:        name,
-					SourcePath:  filepath.ToSlash(item.SourcePath),
-					TargetPath:  filepath.ToSlash(item.InstallTarget),
-					ContentHash: hash,
-					FileHashes:  fileHashes,
-					Workflow:    workflowEvidence,
-					Mode:        mode,
-					Status:      "installed",
-				})
+		for i, target := range targets {
+			installTarget := filepath.ToSlash(filepath.Join(target.InstallRoot, item.Name))
+			if target.Name == skillsDefaultTarget && strings.TrimSpace(item.InstallTarget) != "" {
+				installTarget = filepath.ToSlash(item.InstallTarget)
+			}
+			ops, targetItem, err := planSkillTarget(opt, plan, previousState, target, item, sourceDir, installTarget)
+			if err != nil {
+				return skillsInstallPlan{}, err
+			}
+			targetItem.SkillHash = hash
+			plan.Operations = append(plan.Operations, ops...)
+			plan.Targets[i].Skills = append(plan.Targets[i].Skills, targetItem)
+			if i > 0 {
 				continue
 			}
-			managed := isManagedSkillTarget(previousState, name, item.InstallTarget)
-			if !managed {
+			plan.InstalledSkills = append(plan.InstalledSkills, skillsInstalledItem{
+				Name:        name,
+				SourcePath:  filepath.ToSlash(item.SourcePath),
+				TargetPath:  installTarget,
+				ContentHash: hash,
+				FileHashes:  fileHashes,
+				Workflow:    workflowEvidence,
+				Mode:        mode,
+				Status:      "installed",
+			})
+		}
+	}
+	for _, target := range plan.Targets {
+		if target.Index == "" {
+			continue
+		}
+		ownership := "managed"
+		if _, err := os.Lstat(filepath.Join(projectRoot, filepath.FromSlash(target.Index))); err == nil {
+			if recordedSkillsOwnership(previousState, target.Index) == "unmanaged" {
+				ownership = "unmanaged"
+			} else if !isManagedSkillsPath(previousState, target.Index) {
 				ownership = "unmanaged"
 				if !opt.Force {
-					return skillsInstallPlan{}, fmt.Errorf("target already exists for skill %q (%s); use --force to replace unmanaged files", name, targetDir)
+					return skillsInstallPlan{}, fmt.Errorf("index file already exists for skills target %q (%s); use --force to replace unmanaged files", target.Name, target.Index)
 				}
 			}
-		} else if !errors.Is(err, os.ErrNotExist) {
-			return skillsInstallPlan{}, fmt.Errorf("unable to inspect target for skill %q: %w", name, err)
 		}
-
 		plan.Operations = append(plan.Operations, skillsInstallOperation{
-			Kind:      mode,
-			Source:    filepath.ToSlash(sourceDir),
-			Target:    filepath.ToSlash(item.InstallTarget),
-			Ownership: ownership,
-			Status:    "planned",
-		})
-		plan.InstalledSkills = append(plan.InstalledSkills, skillsInstalledItem{
-			Name:        name,
-			SourcePath:  filepath.ToSlash(item.SourcePath),
-			TargetPath:  filepath.ToSlash(item.InstallTarget),
-			ContentHash: hash,
-			FileHashes:  fileHashes,
-			Workflow:    workflowEvidence,
-			Mode:        mode,
-			Status:      "installed",
+			Kind:       "write-index",
+			TargetName: target.Name,
+			Target:     target.Index,
+			Ownership:  ownership,
+			Status:     "planned",
 		})
 	}
 	plan.Operations = append(plan.Operations, skillsInstallOperation{
@@ -478,15 +567,164 @@ func planSkillsInstall(opt Options, workDir string) (skillsInstallPlan, error) {
 	return plan, nil
 }
 
+func (p skillsInstallPlan) targetNames() []string {
+	out := make([]string, 0, len(p.Targets))
+	for _, target := range p.Targets {
+		out = append(out, target.Name)
+	}
+	return out
+}
+
+func planSkillTarget(opt Options, plan skillsInstallPlan, previousState skillsInstallState, target skillsManifestTarget, item skillsManifestEntry, sourceDir string, installTarget string) ([]skillsInstallOperation, skillsTargetItem, error) {
+	name := item.Name
+	targetDir := filepath.Join(plan.ProjectRoot, filepath.FromSlash(installTarget))
+	skillFile := path.Join(installTarget, "SKILL.md")
+	if rel, err := filepath.Rel(filepath.FromSlash(item.SourcePath), filepath.FromSlash(item.SkillFile)); err == nil && !strings.HasPrefix(rel, "..") {
+		skillFile = path.Join(installTarget, filepath.ToSlash(rel))
+	}
+	targetItem := skillsTargetItem{Name: name, TargetPath: installTarget, SkillFile: skillFile}
+
+	if _, err := os.Lstat(targetDir); err == nil {
+		if plan.Mode == "link" && symlinkPointsTo(targetDir, sourceDir) {
+			return []skillsInstallOperation{{
+				Kind:       "skip",
+				TargetName: target.Name,
+				Source:     filepath.ToSlash(sourceDir),
+				Target:     installTarget,
+				Ownership:  "managed",
+				Status:     "planned",
+			}}, targetItem, nil
+		}
+	}
+	ownership, err := skillsTargetOwnership(opt, previousState, target.Name, name, installTarget, targetDir)
+	if err != nil {
+		return nil, skillsTargetItem{}, err
+	}
+	ops := []skillsInstallOperation{{
+		Kind:       plan.Mode,
+		TargetName: target.Name,
+		Source:     filepath.ToSlash(sourceDir),
+		Target:     installTarget,
+		Ownership:  ownership,
+		Status:     "planned",
+	}}
+
+	froms := make([]string, 0, len(target.Rename))
+	for from := range target.Rename {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+	root := filepath.Join(plan.ProjectRoot, filepath.FromSlash(target.InstallRoot))
+	for _, from := range froms {
+		cleanFrom := filepath.Clean(filepath.FromSlash(strings.TrimSpace(from)))
+		if !isSafeRelPath(cleanFrom) {
+			return nil, skillsTargetItem{}, fmt.Errorf("skills target %q declares unsafe rename source %q", target.Name, from)
+		}
+		if st, err := os.Stat(filepath.Join(sourceDir, cleanFrom)); err != nil || st.IsDir() {
+			continue
+		}
+		to := strings.ReplaceAll(strings.TrimSpace(target.Rename[from]), "{name}", name)
+		dest := filepath.Clean(filepath.Join(targetDir, filepath.FromSlash(to)))
+		rel, err := filepath.Rel(root, dest)
+		if to == "" || err != nil || !isSafeRelPath(rel) {
+			return nil, skillsTargetItem{}, fmt.Errorf("skills target %q declares unsafe rename destination %q", target.Name, target.Rename[from])
+		}
+		fromRel := path.Join(installTarget, filepath.ToSlash(cleanFrom))
+		toRel := filepath.ToSlash(relOrSelf(plan.ProjectRoot, dest))
+		if outside, _ := filepath.Rel(targetDir, dest); !isSafeRelPath(outside) {
+			renameOwnership, err := skillsTargetOwnership(opt, previousState, "", name, toRel, dest)
+			if err != nil {
+				return nil, skillsTargetItem{}, err
+			}
+			if renameOwnership == "unmanaged" {
+				ops[0].Ownership = renameOwnership
+			}
+		}
+		ops = append(ops, skillsInstallOperation{
+			Kind:       "rename",
+			TargetName: target.Name,
+			Source:     fromRel,
+			Target:     toRel,
+			Ownership:  "managed",
+			Status:     "planned",
+		})
+		if fromRel == targetItem.SkillFile {
+			targetItem.SkillFile = toRel
+		}
+	}
+	return ops, targetItem, nil
+}
+
+func skillsTargetOwnership(opt Options, previousState skillsInstallState, targetName string, name string, installTarget string, targetPath string) (string, error) {
+	if _, err := os.Lstat(targetPath); err == nil {
+		if isManagedSkillsPath(previousState, installTarget) || (targetName == skillsDefaultTarget && isManagedSkillTarget(previousState, name, installTarget)) {
+			// A path --force replaced once stays the user's, so remove keeps it.
+			if recordedSkillsOwnership(previousState, installTarget) == "unmanaged" {
+				return "unmanaged", nil
+			}
+			return "managed", nil
+		}
+		if !opt.Force {
+			return "", fmt.Errorf("target already exists for skill %q (%s); use --force to replace unmanaged files", name, targetPath)
+		}
+		return "unmanaged", nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("unable to inspect target for skill %q: %w", name, err)
+	}
+	return "managed", nil
+}
+
+// resolveSkillsTargets maps --skills-target names onto manifest targets. The
+// manifest's top-level install_root is always available as "default".
+func resolveSkillsTargets(raw []string, manifest skillsManifest) ([]skillsManifestTarget, error) {
+	available := map[string]skillsManifestTarget{
+		skillsDefaultTarget: {Name: skillsDefaultTarget, InstallRoot: manifest.InstallRoot},
+	}
+	names := []string{skillsDefaultTarget}
+	for _, target := range manifest.Targets {
+		target.Name = strings.TrimSpace(target.Name)
+		if target.Name == "" {
+			return nil, errors.New("skills manifest declares a target without a name")
+		}
+		if strings.TrimSpace(target.InstallRoot) == "" {
+			return nil, fmt.Errorf("skills target %q has no install_root", target.Name)
+		}
+		for _, p := range []string{target.InstallRoot, target.Index} {
+			if p != "" && !isSafeRelPath(filepath.Clean(filepath.FromSlash(p))) {
+				return nil, fmt.Errorf("skills target %q declares unsafe path %q", target.Name, p)
+			}
+		}
+		if _, ok := available[target.Name]; !ok {
+			names = append(names, target.Name)
+		}
+		available[target.Name] = target
+	}
+
+	requested := uniqueSkillNames(raw)
+	if len(requested) == 0 {
+		requested = []string{skillsDefaultTarget}
+	}
+	out := make([]skillsManifestTarget, 0, len(requested))
+	for _, name := range requested {
+		target, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown skills target %q (available: %s)", name, strings.Join(names, ", "))
+		}
+		out = append(out, target)
+	}
+	return out, nil
+}
+
 func applySkillsInstallPlan(plan skillsInstallPlan) error {
 	if len(plan.Selected) == 0 {
 		return nil
 	}
-	if err := os.MkdirAll(plan.InstallRoot, 0o755); err != nil {
-		return err
-	}
 	for _, op := range plan.Operations {
 		switch op.Kind {
+		case "mkdir":
+			if err := os.MkdirAll(filepath.Join(plan.ProjectRoot, filepath.FromSlash(op.Target)), 0o755); err != nil {
+				return err
+			}
 		case "copy":
 			source := filepath.FromSlash(op.Source)
 			target := filepath.Join(plan.ProjectRoot, filepath.FromSlash(op.Target))
@@ -505,8 +743,31 @@ func applySkillsInstallPlan(plan skillsInstallPlan) error {
 			if err := os.Symlink(source, target); err != nil {
 				return err
 			}
+		case "rename":
+			source := filepath.Join(plan.ProjectRoot, filepath.FromSlash(op.Source))
+			target := filepath.Join(plan.ProjectRoot, filepath.FromSlash(op.Target))
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Rename(source, target); err != nil {
+				return err
+			}
+		case "write-index":
+			for _, target := range plan.Targets {
+				if target.Name != op.TargetName {
+					continue
+				}
+				indexPath := filepath.Join(plan.ProjectRoot, filepath.FromSlash(op.Target))
+				if err := os.MkdirAll(filepath.Dir(indexPath), 0o755); err != nil {
+					return err
+				}
+				if err := os.WriteFile(indexPath, renderSkillsIndex(target), 0o644); err != nil {
+					return err
+				}
+			}
 		}
 	}
+	operations := appliedSkillsOperations(plan.Operations)
 	state := skillsInstallState{
 		SchemaVersion: skillsStateSchema,
 		InstalledAt:   time.Now().UTC().Format(time.RFC3339),
@@ -520,16 +781,51 @@ func applySkillsInstallPlan(plan skillsInstallPlan) error {
 			Manifest: plan.ManifestPath,
 		},
 		InstalledSkills: plan.InstalledSkills,
-		Operations:      appliedSkillsOperations(plan.Operations),
+		Operations:      operations,
+	}
+	for _, target := range plan.Targets {
+		stateTarget := skillsInstallStateTarget{
+			Name:       target.Name,
+			Root:       target.Root,
+			Index:      target.Index,
+			Skills:     target.Skills,
+			Operations: []skillsInstallOperation{},
+		}
+		for _, op := range operations {
+			if op.TargetName == target.Name {
+				stateTarget.Operations = append(stateTarget.Operations, op)
+			}
+		}
+		state.Targets = append(state.Targets, stateTarget)
 	}
+	state.Targets = append(state.Targets, plan.KeptTargets...)
+	return writeSkillsInstallState(plan.LockfilePath, state)
+}
+
+func writeSkillsInstallState(path string, state skillsInstallState) error {
 	raw, err := json.MarshalIndent(state, "", "  ")
 	if err != nil {
 		return err
 	}
-	if err := os.MkdirAll(filepath.Dir(plan.LockfilePath), 0o755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return err
 	}
-	return os.WriteFile(plan.LockfilePath, append(raw, '\n'), 0o644)
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+func renderSkillsIndex(target skillsTargetPlan) []byte {
+	indexDir := path.Dir(target.Index)
+	var b strings.Builder
+	b.WriteString("# EVO Skills\n\n")
+	b.WriteString("Generated by the Evolution CMS installer; do not edit by hand.\n\n")
+	for _, item := range target.Skills {
+		link := item.SkillFile
+		if rel, err := filepath.Rel(filepath.FromSlash(indexDir), filepath.FromSlash(item.SkillFile)); err == nil {
+			link = filepath.ToSlash(rel)
+		}
+		fmt.Fprintf(&b, "- [%s](%s)\n", item.Name, link)
+	}
+	return []byte(b.String())
 }
 
 func readSkillsManifest(path string) (skillsManifest, error) {
@@ -783,6 +1079,36 @@ func isManagedSkillTarget(state skillsInstallState, name string, target string)
 	return false
 }
 
+func isManagedSkillsPath(state skillsInstallState, target string) bool {
+	target = filepath.ToSlash(target)
+	for _, stateTarget := range state.Targets {
+		if stateTarget.Index == target {
+			return true
+		}
+		for _, op := range stateTarget.Operations {
+			if op.Target == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// recordedSkillsOwnership returns the ownership the lockfile records for
+// target, or "" when no target records it.
+func recordedSkillsOwnership(state skillsInstallState, target string) string {
+	for _, stateTarget := range state.Targets {
+		if ownership := skillsPathOwnership(stateTarget, target); ownership != "" {
+			return ownership
+		}
+	}
+	return ""
+}
+
+func isSafeRelPath(cleanPath string) bool {
+	return cleanPath != "." && cleanPath != ".." && !filepath.IsAbs(cleanPath) && !strings.HasPrefix(cleanPath, ".."+string(filepath.Separator))
+}
+
 func symlinkPointsTo(target string, source string) bool {
 	dest, err := os.Readlink(target)
 	if err != nil {
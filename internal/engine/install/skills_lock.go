@@ -0,0 +1,272 @@
+package install
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SkillsTargetReport is the verify result for one lockfile target. Problems
+// is empty when every recorded file is present and unchanged.
+type SkillsTargetReport struct {
+	Target   string
+	Skills   int
+	Problems []string
+}
+
+// SkillsLockfilePath locates the skills lockfile of a project. The manifest
+// in source may move it; without a source the default location is used.
+func SkillsLockfilePath(projectDir string, source string) (string, error) {
+	lockfile := path.Join(skillsDefaultRoot, skillsLockfileName)
+	if source = strings.TrimSpace(source); source != "" {
+		manifest, err := readSkillsManifest(filepath.Join(absDir(source), skillsManifestPath))
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case strings.TrimSpace(manifest.Lockfile) != "":
+			lockfile = manifest.Lockfile
+		case strings.TrimSpace(manifest.InstallRoot) != "":
+			lockfile = path.Join(manifest.InstallRoot, skillsLockfileName)
+		}
+	}
+	return filepath.Join(absDir(projectDir), filepath.FromSlash(lockfile)), nil
+}
+
+// VerifySkills checks the installed files of the named lockfile targets (all
+// targets when names is empty) against what the lockfile recorded.
+func VerifySkills(projectDir string, lockfile string, names []string) ([]SkillsTargetReport, error) {
+	root := absDir(projectDir)
+	_, targets, err := selectSkillsTargets(lockfile, names)
+	if err != nil {
+		return nil, err
+	}
+	reports := make([]SkillsTargetReport, 0, len(targets))
+	for _, target := range targets {
+		report := SkillsTargetReport{Target: target.Name, Skills: len(target.Skills)}
+		problem := func(format string, args ...any) {
+			report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+		}
+		for _, item := range target.Skills {
+			if _, err := os.Lstat(skillsProjectPath(root, item.TargetPath)); err != nil {
+				problem("%s: missing %s", item.Name, item.TargetPath)
+				continue
+			}
+			hash, err := sha256File(skillsProjectPath(root, item.SkillFile))
+			switch {
+			case err != nil:
+				problem("%s: missing %s", item.Name, item.SkillFile)
+			case item.SkillHash != "" && hash != item.SkillHash:
+				problem("%s: %s was modified", item.Name, item.SkillFile)
+			}
+		}
+		for _, op := range target.Operations {
+			if op.Kind != "rename" {
+				continue
+			}
+			if _, err := os.Lstat(skillsProjectPath(root, op.Target)); err != nil {
+				problem("missing %s", op.Target)
+			}
+		}
+		if target.Index != "" {
+			raw, err := os.ReadFile(skillsProjectPath(root, target.Index))
+			switch {
+			case err != nil:
+				problem("missing index %s", target.Index)
+			case !bytes.Equal(raw, renderSkillsIndex(skillsTargetPlan{Index: target.Index, Skills: target.Skills})):
+				problem("index %s was modified", target.Index)
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// RemoveSkills deletes what the named lockfile targets installed (skill
+// directories, renamed files and the index) and drops them from the
+// lockfile. Paths another remaining target still records are kept, and so
+// are paths that existed before the installer replaced them with --force.
+// The lockfile itself goes once no target is left. It returns the removed
+// paths relative to the project.
+func RemoveSkills(projectDir string, lockfile string, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, errors.New("name the skills targets to remove")
+	}
+	root := absDir(projectDir)
+	state, targets, err := selectSkillsTargets(lockfile, names)
+	if err != nil {
+		return nil, err
+	}
+	var left []skillsInstallStateTarget
+	for _, target := range stateTargets(state) {
+		if !slices.Contains(names, target.Name) {
+			left = append(left, target)
+		}
+	}
+	stillUsed := map[string]int{}
+	for _, target := range left {
+		for _, rel := range skillsTargetPaths(target) {
+			stillUsed[rel]++
+		}
+	}
+
+	var removed []string
+	remove := func(target skillsInstallStateTarget, rel string) error {
+		clean := filepath.Clean(filepath.FromSlash(rel))
+		if !isSafeRelPath(clean) {
+			return fmt.Errorf("refusing to remove unsafe lockfile path %q", rel)
+		}
+		if stillUsed[filepath.ToSlash(clean)] > 0 || skillsPathOwnership(target, rel) == "unmanaged" {
+			return nil
+		}
+		if _, err := os.Lstat(filepath.Join(root, clean)); err != nil {
+			return nil
+		}
+		if err := os.RemoveAll(filepath.Join(root, clean)); err != nil {
+			return err
+		}
+		removed = append(removed, filepath.ToSlash(clean))
+		return nil
+	}
+	for _, target := range targets {
+		for _, op := range target.Operations {
+			if op.Kind == "rename" {
+				if err := remove(target, op.Target); err != nil {
+					return removed, err
+				}
+			}
+		}
+		for _, item := range target.Skills {
+			if err := remove(target, item.TargetPath); err != nil {
+				return removed, err
+			}
+		}
+		if target.Index != "" {
+			if err := remove(target, target.Index); err != nil {
+				return removed, err
+			}
+		}
+	}
+
+	if len(left) == 0 {
+		if err := os.Remove(lockfile); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		return removed, nil
+	}
+	state.Targets = left
+	// Installed skills follow the remaining targets, one entry per skill.
+	var installed []skillsInstalledItem
+	for _, target := range left {
+		for _, skill := range target.Skills {
+			if slices.ContainsFunc(installed, func(it skillsInstalledItem) bool { return it.Name == skill.Name }) {
+				continue
+			}
+			i := slices.IndexFunc(state.InstalledSkills, func(it skillsInstalledItem) bool { return it.Name == skill.Name })
+			if i < 0 {
+				continue
+			}
+			item := state.InstalledSkills[i]
+			item.TargetPath = skill.TargetPath
+			installed = append(installed, item)
+		}
+	}
+	state.InstalledSkills = installed
+	ops := state.Operations[:0]
+	for _, op := range state.Operations {
+		if op.TargetName == "" || !slices.Contains(names, op.TargetName) {
+			ops = append(ops, op)
+		}
+	}
+	state.Operations = ops
+	return removed, writeSkillsInstallState(lockfile, state)
+}
+
+// skillsTargetPaths lists the project paths a lockfile target records.
+func skillsTargetPaths(target skillsInstallStateTarget) []string {
+	var paths []string
+	add := func(rel string) {
+		if rel != "" {
+			paths = append(paths, path.Clean(filepath.ToSlash(rel)))
+		}
+	}
+	for _, item := range target.Skills {
+		add(item.TargetPath)
+	}
+	for _, op := range target.Operations {
+		if op.Kind == "rename" {
+			add(op.Target)
+		}
+	}
+	add(target.Index)
+	return paths
+}
+
+// skillsPathOwnership returns the ownership recorded for rel by target's
+// operations: "unmanaged" when the path existed before --force replaced it,
+// "managed" or "" (lockfiles without operations) otherwise.
+func skillsPathOwnership(target skillsInstallStateTarget, rel string) string {
+	rel = path.Clean(filepath.ToSlash(rel))
+	for _, op := range target.Operations {
+		if op.Target != "" && path.Clean(op.Target) == rel {
+			return op.Ownership
+		}
+	}
+	return ""
+}
+
+// selectSkillsTargets reads the lockfile and returns the named targets, or
+// all of them when names is empty.
+func selectSkillsTargets(lockfile string, names []string) (skillsInstallState, []skillsInstallStateTarget, error) {
+	state, err := readSkillsInstallState(lockfile)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil, fmt.Errorf("no skills lockfile at %s", lockfile)
+	}
+	if err != nil {
+		return state, nil, fmt.Errorf("unable to read skills lockfile: %w", err)
+	}
+	all := stateTargets(state)
+	if len(names) == 0 {
+		return state, all, nil
+	}
+	known := make([]string, 0, len(all))
+	for _, target := range all {
+		known = append(known, target.Name)
+	}
+	out := make([]skillsInstallStateTarget, 0, len(names))
+	for _, name := range uniqueSkillNames(names) {
+		i := slices.Index(known, name)
+		if i < 0 {
+			return state, nil, fmt.Errorf("skills target %q is not installed (installed: %s)", name, strings.Join(known, ", "))
+		}
+		out = append(out, all[i])
+	}
+	return state, out, nil
+}
+
+// stateTargets returns the lockfile targets. Lockfiles written before named
+// targets existed only list installed skills; they become the default target.
+func stateTargets(state skillsInstallState) []skillsInstallStateTarget {
+	if len(state.Targets) > 0 || len(state.InstalledSkills) == 0 {
+		return state.Targets
+	}
+	target := skillsInstallStateTarget{Name: skillsDefaultTarget, Root: state.SkillsRoot}
+	for _, item := range state.InstalledSkills {
+		target.Skills = append(target.Skills, skillsTargetItem{
+			Name:       item.Name,
+			TargetPath: item.TargetPath,
+			SkillFile:  path.Join(item.TargetPath, "SKILL.md"),
+			SkillHash:  item.ContentHash,
+		})
+	}
+	return []skillsInstallStateTarget{target}
+}
+
+func skillsProjectPath(root string, rel string) string {
+	return filepath.Join(root, filepath.FromSlash(rel))
+}
@@ -269,6 +269,275 @@ func TestPlanSkillsInstallNoneIsNoop(t *testing.T) {
 	}
 }
 
+func TestApplySkillsInstallNamedTargetsRecordsPerTargetOperations(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := makeSkillsSource(t)
+	rewriteSkillsManifest(t, sourceRoot, func(manifest *skillsManifest) {
+		manifest.Targets = []skillsManifestTarget{
+			{
+				Name:        "cursor",
+				InstallRoot: ".cursor/rules",
+				Rename:      map[string]string{"SKILL.md": "../{name}.mdc"},
+				Index:       ".cursor/rules/INDEX.md",
+			},
+		}
+	})
+	projectRoot := t.TempDir()
+
+	plan, err := planSkillsInstall(Options{
+		Skills:        []string{"evo-skill-creator"},
+		SkillsTargets: []string{"default", "cursor"},
+		SkillsSource:  sourceRoot,
+	}, projectRoot)
+	if err != nil {
+		t.Fatalf("planSkillsInstall returned error: %v", err)
+	}
+	if err := applySkillsInstallPlan(plan); err != nil {
+		t.Fatalf("applySkillsInstallPlan returned error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(projectRoot, "core", "custom", "skills", "evo-skill-creator", "SKILL.md")); err != nil {
+		t.Fatalf("default target SKILL.md missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".cursor", "rules", "evo-skill-creator.mdc")); err != nil {
+		t.Fatalf("renamed cursor rule missing: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".cursor", "rules", "evo-skill-creator", "SKILL.md")); !os.IsNotExist(err) {
+		t.Fatalf("expected SKILL.md to be renamed away, stat err=%v", err)
+	}
+	index, err := os.ReadFile(filepath.Join(projectRoot, ".cursor", "rules", "INDEX.md"))
+	if err != nil {
+		t.Fatalf("index missing: %v", err)
+	}
+	if !strings.Contains(string(index), "[evo-skill-creator](evo-skill-creator.mdc)") {
+		t.Fatalf("index = %q", index)
+	}
+
+	state, err := readSkillsInstallState(filepath.Join(projectRoot, "core", "custom", "skills", ".evo-skills.lock.json"))
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	if len(state.Targets) != 2 || state.Targets[0].Name != "default" || state.Targets[1].Name != "cursor" {
+		t.Fatalf("lockfile targets = %#v", state.Targets)
+	}
+	cursor := state.Targets[1]
+	if !hasSkillsOperation(cursor.Operations, "rename") || !hasSkillsOperation(cursor.Operations, "write-index") {
+		t.Fatalf("cursor operations = %#v", cursor.Operations)
+	}
+	if hasSkillsOperation(state.Targets[0].Operations, "rename") {
+		t.Fatalf("default target should not record cursor renames: %#v", state.Targets[0].Operations)
+	}
+	if len(cursor.Skills) != 1 || cursor.Skills[0].SkillFile != ".cursor/rules/evo-skill-creator.mdc" {
+		t.Fatalf("cursor skills = %#v", cursor.Skills)
+	}
+}
+
+func TestVerifyAndRemoveSkillsPerTarget(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := makeSkillsSource(t)
+	rewriteSkillsManifest(t, sourceRoot, func(manifest *skillsManifest) {
+		manifest.Targets = []skillsManifestTarget{
+			{Name: "cursor", InstallRoot: ".cursor/rules", Rename: map[string]string{"SKILL.md": "../{name}.mdc"}, Index: ".cursor/rules/INDEX.md"},
+		}
+	})
+	projectRoot := t.TempDir()
+	install := func(target string) {
+		t.Helper()
+		plan, err := planSkillsInstall(Options{Skills: []string{"evo-skill-creator"}, SkillsTargets: []string{target}, SkillsSource: sourceRoot}, projectRoot)
+		if err != nil {
+			t.Fatalf("planSkillsInstall(%s) returned error: %v", target, err)
+		}
+		if err := applySkillsInstallPlan(plan); err != nil {
+			t.Fatalf("applySkillsInstallPlan(%s) returned error: %v", target, err)
+		}
+	}
+	// Separate runs: the second install keeps the first target in the lockfile.
+	install("default")
+	install("cursor")
+
+	lockfile, err := SkillsLockfilePath(projectRoot, sourceRoot)
+	if err != nil {
+		t.Fatalf("SkillsLockfilePath returned error: %v", err)
+	}
+	reports, err := VerifySkills(projectRoot, lockfile, nil)
+	if err != nil {
+		t.Fatalf("VerifySkills returned error: %v", err)
+	}
+	if len(reports) != 2 || len(reports[0].Problems) != 0 || len(reports[1].Problems) != 0 {
+		t.Fatalf("reports = %#v", reports)
+	}
+
+	rule := filepath.Join(projectRoot, ".cursor", "rules", "evo-skill-creator.mdc")
+	if err := os.WriteFile(rule, []byte("edited"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reports, err = VerifySkills(projectRoot, lockfile, []string{"cursor"})
+	if err != nil || len(reports) != 1 || len(reports[0].Problems) != 1 || !strings.Contains(reports[0].Problems[0], "modified") {
+		t.Fatalf("VerifySkills(cursor) = %#v, %v", reports, err)
+	}
+
+	removed, err := RemoveSkills(projectRoot, lockfile, []string{"cursor"})
+	if err != nil {
+		t.Fatalf("RemoveSkills returned error: %v", err)
+	}
+	if len(removed) != 3 {
+		t.Fatalf("removed = %q", removed)
+	}
+	if _, err := os.Stat(rule); !os.IsNotExist(err) {
+		t.Fatalf("cursor rule still present, stat err=%v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, "core", "custom", "skills", "evo-skill-creator", "SKILL.md")); err != nil {
+		t.Fatalf("default target removed too: %v", err)
+	}
+	if _, err := VerifySkills(projectRoot, lockfile, []string{"cursor"}); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Fatalf("expected cursor to be gone from the lockfile, got %v", err)
+	}
+
+	if _, err := RemoveSkills(projectRoot, lockfile, []string{"default"}); err != nil {
+		t.Fatalf("RemoveSkills(default) returned error: %v", err)
+	}
+	if _, err := os.Stat(lockfile); !os.IsNotExist(err) {
+		t.Fatalf("lockfile kept after removing every target, stat err=%v", err)
+	}
+}
+
+func TestRemoveSkillsKeepsSharedAndUnmanagedPaths(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := makeSkillsSource(t)
+	rewriteSkillsManifest(t, sourceRoot, func(manifest *skillsManifest) {
+		// mirror installs into the same directory as the default target.
+		manifest.Targets = []skillsManifestTarget{{Name: "mirror", InstallRoot: "core/custom/skills"}}
+	})
+	install := func(projectRoot string, target string, force bool) {
+		t.Helper()
+		plan, err := planSkillsInstall(Options{Skills: []string{"evo-skill-creator"}, SkillsTargets: []string{target}, SkillsSource: sourceRoot, Force: force}, projectRoot)
+		if err != nil {
+			t.Fatalf("planSkillsInstall(%s) returned error: %v", target, err)
+		}
+		if err := applySkillsInstallPlan(plan); err != nil {
+			t.Fatalf("applySkillsInstallPlan(%s) returned error: %v", target, err)
+		}
+	}
+
+	projectRoot := t.TempDir()
+	install(projectRoot, "default", false)
+	install(projectRoot, "mirror", false)
+	lockfile, err := SkillsLockfilePath(projectRoot, sourceRoot)
+	if err != nil {
+		t.Fatalf("SkillsLockfilePath returned error: %v", err)
+	}
+	skillDir := filepath.Join(projectRoot, "core", "custom", "skills", "evo-skill-creator")
+	removed, err := RemoveSkills(projectRoot, lockfile, []string{"mirror"})
+	if err != nil || len(removed) != 0 {
+		t.Fatalf("RemoveSkills(mirror) = %q, %v; want nothing removed", removed, err)
+	}
+	if _, err := os.Stat(skillDir); err != nil {
+		t.Fatalf("shared skill directory removed: %v", err)
+	}
+	state, err := readSkillsInstallState(lockfile)
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	if len(state.InstalledSkills) != 1 || state.InstalledSkills[0].TargetPath != "core/custom/skills/evo-skill-creator" {
+		t.Fatalf("installed skills = %#v", state.InstalledSkills)
+	}
+	if removed, err = RemoveSkills(projectRoot, lockfile, []string{"default"}); err != nil || len(removed) != 1 {
+		t.Fatalf("RemoveSkills(default) = %q, %v", removed, err)
+	}
+	if _, err := os.Stat(skillDir); !os.IsNotExist(err) {
+		t.Fatalf("skill directory kept after its last target, stat err=%v", err)
+	}
+
+	// A directory that existed before --force replaced it is not removed.
+	projectRoot = t.TempDir()
+	skillDir = filepath.Join(projectRoot, "core", "custom", "skills", "evo-skill-creator")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	install(projectRoot, "default", true)
+	install(projectRoot, "default", false)
+	lockfile, _ = SkillsLockfilePath(projectRoot, sourceRoot)
+	if removed, err = RemoveSkills(projectRoot, lockfile, []string{"default"}); err != nil || len(removed) != 0 {
+		t.Fatalf("RemoveSkills(unmanaged) = %q, %v; want nothing removed", removed, err)
+	}
+	if _, err := os.Stat(skillDir); err != nil {
+		t.Fatalf("unmanaged directory removed: %v", err)
+	}
+}
+
+func TestRemoveSkillsRecomputesInstalledSkills(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := makeSkillsSource(t)
+	rewriteSkillsManifest(t, sourceRoot, func(manifest *skillsManifest) {
+		manifest.Targets = []skillsManifestTarget{{Name: "agents", InstallRoot: ".agents/skills"}}
+	})
+	projectRoot := t.TempDir()
+	plan, err := planSkillsInstall(Options{Skills: []string{"evo-skill-creator"}, SkillsTargets: []string{"default", "agents"}, SkillsSource: sourceRoot}, projectRoot)
+	if err != nil {
+		t.Fatalf("planSkillsInstall returned error: %v", err)
+	}
+	if err := applySkillsInstallPlan(plan); err != nil {
+		t.Fatalf("applySkillsInstallPlan returned error: %v", err)
+	}
+	lockfile, err := SkillsLockfilePath(projectRoot, sourceRoot)
+	if err != nil {
+		t.Fatalf("SkillsLockfilePath returned error: %v", err)
+	}
+
+	removed, err := RemoveSkills(projectRoot, lockfile, []string{"default"})
+	if err != nil || len(removed) != 1 || removed[0] != "core/custom/skills/evo-skill-creator" {
+		t.Fatalf("RemoveSkills(default) = %q, %v", removed, err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".agents", "skills", "evo-skill-creator", "SKILL.md")); err != nil {
+		t.Fatalf("agents target removed too: %v", err)
+	}
+	state, err := readSkillsInstallState(lockfile)
+	if err != nil {
+		t.Fatalf("read lockfile: %v", err)
+	}
+	if len(state.InstalledSkills) != 1 || state.InstalledSkills[0].TargetPath != ".agents/skills/evo-skill-creator" {
+		t.Fatalf("installed skills = %#v, want the agents target path", state.InstalledSkills)
+	}
+}
+
+func TestPlanSkillsInstallUnknownTargetFails(t *testing.T) {
+	t.Parallel()
+
+	_, err := planSkillsInstall(Options{
+		Skills:        []string{"evo-skill-creator"},
+		SkillsTargets: []string{"missing"},
+		SkillsSource:  makeSkillsSource(t),
+	}, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "unknown skills target") {
+		t.Fatalf("expected unknown target error, got %v", err)
+	}
+}
+
+func TestPlanSkillsInstallRenameTargetRejectsLinkMode(t *testing.T) {
+	t.Parallel()
+
+	sourceRoot := makeSkillsSource(t)
+	rewriteSkillsManifest(t, sourceRoot, func(manifest *skillsManifest) {
+		manifest.Targets = []skillsManifestTarget{
+			{Name: "agents", InstallRoot: ".agents", Rename: map[string]string{"SKILL.md": "AGENTS.md"}},
+		}
+	})
+
+	_, err := planSkillsInstall(Options{
+		Skills:        []string{"evo-skill-creator"},
+		SkillsTargets: []string{"agents"},
+		SkillsSource:  sourceRoot,
+		SkillsLink:    true,
+	}, t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "cannot be used with --skills-link") {
+		t.Fatalf("expected link-mode rename error, got %v", err)
+	}
+}
+
 func makeSkillsSource(t *testing.T) string {
 	t.Helper()
 
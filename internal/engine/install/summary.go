@@ -0,0 +1,115 @@
+package install
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+var installedVersionRe = regexp.MustCompile(`'version'\s*=>\s*'([^']+)'`)
+
+// installSummary describes the finished installation for the final screen.
+// Passwords never appear in the rows; the admin password is only carried for
+// the clipboard copy.
+func installSummary(plan installPlan, extras []domain.ExtrasItemResult) domain.InstallSummary {
+	a := plan.Answers
+	row := func(field, label, value string) domain.QuestionDetail {
+		return domain.QuestionDetail{Label: label, Value: value, Key: "field." + field}
+	}
+
+	projectPath := absDir(plan.WorkDir)
+	managerPath := "/" + sanitizeAdminDir(a.AdminDirectory) + "/"
+	version := plan.Version
+	if v := installedVersion(projectPath); v != "" {
+		version = v
+	}
+
+	database := dbDriverLabel(a.DBType) + " · " + a.DBName
+	if a.DBType != "sqlite" {
+		host := a.DBHost
+		if a.DBPort > 0 {
+			host = fmt.Sprintf("%s:%d", a.DBHost, a.DBPort)
+		}
+		database = fmt.Sprintf("%s · %s@%s/%s", dbDriverLabel(a.DBType), a.DBUser, host, a.DBName)
+	}
+
+	presetRow := row(reviewPresetField, "Project preset", plan.Preset)
+	if plan.Preset == "" || plan.Preset == "evolution" {
+		presetRow.Value, presetRow.ValueKey = "Evolution core only", "value.core_only"
+	}
+
+	details := []domain.QuestionDetail{
+		row("target_dir", "Target directory", projectPath),
+		row("manager_url", "Manager URL", managerPath),
+		row("admin_username", "Admin username", a.AdminUsername),
+		row("admin_email", "Admin email", a.AdminEmail),
+		row("database", "Database", database),
+		row("version", "Evolution CMS", version),
+		row("language", "Language", languageLabel(a.Language)),
+		presetRow,
+	}
+	for _, b := range plan.Backups {
+		details = append(details, row("backup", "Backup", b))
+	}
+
+	artisan := filepath.Join(projectPath, "core", "artisan")
+	return domain.InstallSummary{
+		ProjectPath:   projectPath,
+		ManagerPath:   managerPath,
+		AdminUsername: a.AdminUsername,
+		AdminPassword: a.AdminPassword,
+		Details:       details,
+		Extras:        extras,
+		NextSteps: []domain.LogPayload{
+			{
+				Message: "Point the web server document root at " + projectPath + ".",
+				Key:     "summary.next.docroot",
+				Params:  map[string]string{"dir": projectPath},
+			},
+			{
+				Message: "Let the web server user write to core/storage and assets/.",
+				Key:     "summary.next.permissions",
+			},
+			{
+				Message: "If you use scheduled tasks, run `php " + artisan + " schedule:run` every minute from cron.",
+				Key:     "summary.next.cron",
+				Params:  map[string]string{"artisan": artisan},
+			},
+			{
+				Message: "For a quick local test, run `php -S localhost:8000` in the project directory and open http://localhost:8000" + managerPath + ".",
+				Key:     "summary.next.local",
+				Params:  map[string]string{"manager": managerPath},
+			},
+		},
+	}
+}
+
+// installedVersion reads the installed Evolution CMS version from
+// core/factory/version.php; it returns "" when the file is missing.
+func installedVersion(projectPath string) string {
+	raw, err := os.ReadFile(filepath.Join(projectPath, "core", "factory", "version.php"))
+	if err != nil {
+		return ""
+	}
+	m := installedVersionRe.FindSubmatch(raw)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(string(m[1]))
+}
+
+// emitInstallSummary sends the final summary once the run has finished.
+func emitInstallSummary(emit func(domain.Event) bool, plan installPlan, extras []domain.ExtrasItemResult) {
+	_ = emit(domain.Event{
+		Type:     domain.EventSummary,
+		Source:   "install",
+		Severity: domain.SeverityInfo,
+		Payload: domain.SummaryPayload{
+			Summary: installSummary(plan, extras),
+		},
+	})
+}
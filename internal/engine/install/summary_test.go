@@ -0,0 +1,71 @@
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestInstallSummaryListsSettingsWithoutPasswords(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	versionFile := filepath.Join(dir, "core", "factory", "version.php")
+	if err := os.MkdirAll(filepath.Dir(versionFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(versionFile, []byte("<?php return [\n    'version' => '3.5.2',\n];\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := installSummary(installPlan{
+		WorkDir: dir,
+		Version: "latest stable release (v3.5.2)",
+		Answers: preflightAnswers{
+			DBType:         "mysql",
+			DBHost:         "localhost",
+			DBPort:         3306,
+			DBName:         "evo",
+			DBUser:         "root",
+			DBPassword:     "db-secret",
+			AdminUsername:  "admin",
+			AdminEmail:     "admin@example.com",
+			AdminPassword:  "admin-secret",
+			AdminDirectory: "panel",
+			Language:       "uk",
+		},
+		Preset: "evolution",
+	}, []domain.ExtrasItemResult{{Name: "TinyMCE5", Status: domain.ExtrasStatusSuccess}})
+
+	if s.ManagerPath != "/panel/" || s.AdminPassword != "admin-secret" {
+		t.Fatalf("summary = %+v", s)
+	}
+	rows := map[string]string{}
+	for _, d := range s.Details {
+		if strings.Contains(d.Value, "secret") {
+			t.Fatalf("detail %q leaks a password: %q", d.Label, d.Value)
+		}
+		rows[d.Key] = d.Value
+	}
+	want := map[string]string{
+		"field.manager_url":    "/panel/",
+		"field.database":       "MySQL/MariaDB · root@localhost:3306/evo",
+		"field.version":        "3.5.2",
+		"field.project_preset": "Evolution core only",
+		"field.language":       "Ukrainian",
+	}
+	for key, value := range want {
+		if rows[key] != value {
+			t.Fatalf("%s = %q, want %q", key, rows[key], value)
+		}
+	}
+	if len(s.Extras) != 1 || len(s.NextSteps) == 0 {
+		t.Fatalf("extras = %d, next steps = %d", len(s.Extras), len(s.NextSteps))
+	}
+	if got, want := s.Credentials(), "Manager: /panel/\nUsername: admin\nPassword: admin-secret"; got != want {
+		t.Fatalf("Credentials() = %q, want %q", got, want)
+	}
+}
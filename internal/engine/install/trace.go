@@ -0,0 +1,131 @@
+package install
+
+import (
+	"context"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// sensitiveArgKeys mark --flag=value arguments and KEY=value environment
+// entries whose value is masked in trace output.
+var sensitiveArgKeys = []string{"password", "pat", "token", "secret"}
+
+// traceExec reports a subprocess command line and the environment variables
+// added on top of os.Environ.
+func traceExec(ctx context.Context, source string, argv []string, dir string, envAdded []string) {
+	masked := make([]string, 0, len(argv))
+	for _, arg := range argv {
+		masked = append(masked, maskSensitiveAssignment(arg))
+	}
+	msg := "exec: " + strings.Join(masked, " ")
+	if strings.TrimSpace(dir) != "" {
+		msg += " (dir: " + dir + ")"
+	}
+	domain.Tracef(ctx, source, "%s", msg)
+	if len(envAdded) > 0 {
+		env := make([]string, 0, len(envAdded))
+		for _, kv := range envAdded {
+			env = append(env, maskSensitiveAssignment(kv))
+		}
+		domain.Tracef(ctx, source, "env: %s", strings.Join(env, " "))
+	}
+}
+
+// traceExit reports how a subprocess finished.
+func traceExit(ctx context.Context, source string, cmd *exec.Cmd, err error, took time.Duration) {
+	code := 0
+	if cmd.ProcessState != nil {
+		code = cmd.ProcessState.ExitCode()
+	} else if err != nil {
+		code = -1
+	}
+	name := "process"
+	if len(cmd.Args) > 0 {
+		name = filepath.Base(cmd.Args[0])
+	}
+	if err != nil && cmd.ProcessState == nil {
+		domain.Tracef(ctx, source, "exit: %s failed to run after %s: %v", name, domain.FormatDuration(took), err)
+		return
+	}
+	domain.Tracef(ctx, source, "exit: %s code=%d after %s", name, code, domain.FormatDuration(took))
+}
+
+func maskSensitiveAssignment(arg string) string {
+	key, value, ok := strings.Cut(arg, "=")
+	if !ok {
+		return arg
+	}
+	if key == "--db-options" {
+		return key + "=" + maskDatabaseOptions(value)
+	}
+	if isSensitiveKey(strings.TrimLeft(key, "-")) {
+		return key + "=<redacted>"
+	}
+	return arg
+}
+
+// isSensitiveKey reports whether a flag, variable or option name holds a
+// secret.
+func isSensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, k := range sensitiveArgKeys {
+		if strings.Contains(lower, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskDatabaseOptions masks the secret values of an encoded --db-options
+// query, e.g. sslpassword from a --db-url.
+func maskDatabaseOptions(query string) string {
+	parts := strings.Split(query, "&")
+	for i, part := range parts {
+		key, _, ok := strings.Cut(part, "=")
+		if name, err := url.QueryUnescape(key); ok && err == nil && isSensitiveKey(name) {
+			parts[i] = key + "=<redacted>"
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// redactDatabaseOptions returns options with the secret values masked.
+func redactDatabaseOptions(options map[string]string) map[string]string {
+	if len(options) == 0 {
+		return options
+	}
+	out := make(map[string]string, len(options))
+	for k, v := range options {
+		if isSensitiveKey(k) {
+			v = "<redacted>"
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// plainSeverityPrefix starts every plain output line of the PHP installer
+// when it runs with EVO_LOG_SEVERITY=1, e.g. "[evo:warn] ...".
+const plainSeverityPrefix = "[evo:"
+
+// splitPlainSeverity removes the severity tag the PHP installer put in front of
+// a line. Untagged lines (other tools, PHP notices) are warnings on stderr and
+// info on stdout.
+func splitPlainSeverity(line string, stderr bool) (domain.Severity, string) {
+	if rest, ok := strings.CutPrefix(line, plainSeverityPrefix); ok {
+		if level, msg, ok := strings.Cut(rest, "]"); ok {
+			if sev, err := domain.ParseSeverity(level); err == nil {
+				return sev, strings.TrimSpace(msg)
+			}
+		}
+	}
+	if stderr {
+		return domain.SeverityWarn, line
+	}
+	return domain.SeverityInfo, line
+}
@@ -27,7 +27,7 @@ func New() *Engine {
 	}
 }
 
-func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, _ <-chan domain.Action) {
+func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, actions <-chan domain.Action) {
 	go func() {
 		defer close(ch)
 
@@ -69,13 +69,13 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, _ <-chan domai
 			Severity: domain.SeverityInfo,
 			Payload: domain.StepsPayload{
 				Steps: []domain.StepState{
-					{ID: "php", Label: "Step 1: Validate PHP version", Status: domain.StepPending},
-					{ID: "database", Label: "Step 2: Check database connection", Status: domain.StepPending},
-					{ID: "project_preset", Label: "Step 3: Choose project preset", Status: domain.StepPending},
-					{ID: "download", Label: "Step 4: Download Evolution CMS", Status: domain.StepPending},
-					{ID: "install", Label: "Step 5: Install Evolution CMS", Status: domain.StepPending},
-					{ID: "finalize", Label: "Step 6: Finalize installation", Status: domain.StepPending},
-					{ID: "extras", Label: "Step 7: Install Extras", Status: domain.StepPending},
+					{ID: "php", Label: "Step 1: Validate PHP version", Status: domain.StepPending, Key: "step.php"},
+					{ID: "database", Label: "Step 2: Check database connection", Status: domain.StepPending, Key: "step.database"},
+					{ID: "project_preset", Label: "Step 3: Choose project preset", Status: domain.StepPending, Key: "step.project_preset"},
+					{ID: "download", Label: "Step 4: Download Evolution CMS", Status: domain.StepPending, Key: "step.download"},
+					{ID: "install", Label: "Step 5: Install Evolution CMS", Status: domain.StepPending, Key: "step.install"},
+					{ID: "finalize", Label: "Step 6: Finalize installation", Status: domain.StepPending, Key: "step.finalize"},
+					{ID: "extras", Label: "Step 7: Install Extras", Status: domain.StepPending, Key: "step.extras"},
 				},
 			},
 		})
@@ -258,7 +258,7 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, _ <-chan domai
 			})
 		}
 
-		// A sample question to drive UI selection (engine does not consume the answer yet).
+		// A sample question to drive UI selection; the answer itself is ignored.
 		_ = emit(domain.Event{
 			Type:     domain.EventLog,
 			Source:   "mock",
@@ -279,6 +279,10 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, _ <-chan domai
 			},
 		})
 
+		if !waitAnswer(ctx, actions, "db_driver") {
+			return
+		}
+
 		steps := []struct {
 			id    string
 			label string
@@ -315,6 +319,7 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, _ <-chan domai
 					Label: s.label,
 					Index: i + 1,
 					Total: len(steps),
+					Key:   "step." + s.id,
 				},
 			})
 
@@ -372,9 +377,54 @@ func (e *Engine) Run(ctx context.Context, ch chan<- domain.Event, _ <-chan domai
 				Payload:  domain.StepDonePayload{OK: true},
 			})
 		}
+
+		_ = emit(domain.Event{
+			Type:     domain.EventSummary,
+			Source:   "mock",
+			Severity: domain.SeverityInfo,
+			Payload: domain.SummaryPayload{
+				Summary: domain.InstallSummary{
+					ProjectPath:   "/var/www/evo",
+					ManagerPath:   "/manager/",
+					AdminUsername: "admin",
+					AdminPassword: "mock-password",
+					Details: []domain.QuestionDetail{
+						{Label: "Target directory", Value: "/var/www/evo", Key: "field.target_dir"},
+						{Label: "Manager URL", Value: "/manager/", Key: "field.manager_url"},
+						{Label: "Admin username", Value: "admin", Key: "field.admin_username"},
+						{Label: "Database", Value: "SQLite · database.sqlite", Key: "field.database"},
+						{Label: "Evolution CMS", Value: "3.3.0", Key: "field.version"},
+					},
+					Extras: []domain.ExtrasItemResult{
+						{Name: "TinyMCE5", Status: domain.ExtrasStatusSuccess},
+					},
+					NextSteps: []domain.LogPayload{
+						{Message: "Point the web server document root at /var/www/evo.", Key: "summary.next.docroot", Params: map[string]string{"dir": "/var/www/evo"}},
+					},
+				},
+			},
+		})
 	}()
 }
 
+// waitAnswer blocks until questionID is answered so demos pause on the
+// question like a real run. Without an action channel it returns at once.
+func waitAnswer(ctx context.Context, actions <-chan domain.Action, questionID string) bool {
+	if actions == nil {
+		return true
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case a, ok := <-actions:
+			if !ok || a.QuestionID == questionID {
+				return true
+			}
+		}
+	}
+}
+
 func envInt(key string, def int) int {
 	raw := strings.TrimSpace(os.Getenv(key))
 	if raw == "" {
@@ -0,0 +1,210 @@
+// Package i18n holds the installer UI message catalog. Engine events carry a
+// message key plus parameters next to their English text, and the TUI and CLI
+// render them in the selected UI language. The English text stays the
+// fallback, so untranslated keys and log files keep working.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// English is the source language of the catalog.
+const English = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps a language code to its key -> template table. Templates use
+// {name} placeholders filled from the message parameters.
+var catalogs = loadCatalogs()
+
+var current atomic.Value
+
+func init() {
+	current.Store(English)
+}
+
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	out := map[string]map[string]string{}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		table := map[string]string{}
+		if err := json.Unmarshal(data, &table); err != nil {
+			panic(fmt.Sprintf("i18n: parse %s: %v", entry.Name(), err))
+		}
+		out[strings.TrimSuffix(entry.Name(), ".json")] = table
+	}
+	return out
+}
+
+// Supported lists the UI languages with a catalog, English first.
+func Supported() []string {
+	out := []string{English}
+	for lang := range catalogs {
+		if lang != English {
+			out = append(out, lang)
+		}
+	}
+	sort.Strings(out[1:])
+	return out
+}
+
+// Parse validates a --ui-lang value. Locale names such as uk_UA.UTF-8 and
+// de-AT are reduced to their language; "" means English.
+func Parse(name string) (string, error) {
+	lang := baseLanguage(name)
+	if lang == "" {
+		return English, nil
+	}
+	if _, ok := catalogs[lang]; ok {
+		return lang, nil
+	}
+	return "", fmt.Errorf("unsupported UI language %q (use %s)", strings.TrimSpace(name), strings.Join(Supported(), ", "))
+}
+
+// Detect picks the UI language from LC_ALL, LC_MESSAGES and LANG in that
+// order, like gettext. Unsupported locales fall back to English.
+func Detect(getenv func(string) string) string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := strings.TrimSpace(getenv(name))
+		if value == "" {
+			continue
+		}
+		if lang, err := Parse(value); err == nil {
+			return lang
+		}
+		return English
+	}
+	return English
+}
+
+func baseLanguage(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "c" || name == "posix" {
+		return English
+	}
+	return name
+}
+
+// SetLanguage selects the UI language for all later lookups.
+func SetLanguage(lang string) {
+	if _, ok := catalogs[lang]; !ok {
+		lang = English
+	}
+	current.Store(lang)
+}
+
+// Language returns the selected UI language.
+func Language() string {
+	return current.Load().(string)
+}
+
+// T renders a catalog key in the selected language, falling back to English
+// and then to the key itself.
+func T(key string, params map[string]string) string {
+	return Text(key, params, "")
+}
+
+// Text renders key in the selected language. In English, or without a
+// translation, it returns fallback (the English text sent with the event), or
+// the English catalog entry when fallback is empty.
+func Text(key string, params map[string]string, fallback string) string {
+	if key != "" && (Language() != English || fallback == "") {
+		if tmpl, ok := catalogs[Language()][key]; ok {
+			return format(tmpl, params)
+		}
+	}
+	if fallback != "" || key == "" {
+		return fallback
+	}
+	if tmpl, ok := catalogs[English][key]; ok {
+		return format(tmpl, params)
+	}
+	return key
+}
+
+func format(tmpl string, params map[string]string) string {
+	if len(params) == 0 {
+		return tmpl
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
+
+// Error renders a validation error in the selected language; other errors
+// are returned as is.
+func Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	var verr *domain.ValidationError
+	if errors.As(err, &verr) {
+		return Text(verr.Key, verr.Params, verr.Error())
+	}
+	return err.Error()
+}
+
+// Log renders a log payload message.
+func Log(p domain.LogPayload) string {
+	return Text(p.Key, p.Params, p.Message)
+}
+
+// Step renders a step label.
+func Step(s domain.StepState) string {
+	return Text(s.Key, nil, s.Label)
+}
+
+// Question returns q with its prompt, option labels, detail labels and error
+// rendered in the selected language. IDs are untouched, so answers still
+// match what the engine expects.
+func Question(q domain.QuestionState) domain.QuestionState {
+	q.Prompt = Text(q.Key, q.Params, q.Prompt)
+	q.Error = Text(q.ErrorKey, q.ErrorParams, q.Error)
+	if len(q.Options) > 0 {
+		opts := make([]domain.QuestionOption, len(q.Options))
+		for i, o := range q.Options {
+			o.Label = Text(o.Key, o.Params, o.Label)
+			o.Reason = Text(o.ReasonKey, o.ReasonParams, o.Reason)
+			opts[i] = o
+		}
+		q.Options = opts
+	}
+	q.Details = Details(q.Details)
+	return q
+}
+
+// Details returns label/value rows rendered in the selected language.
+func Details(details []domain.QuestionDetail) []domain.QuestionDetail {
+	if len(details) == 0 {
+		return details
+	}
+	out := make([]domain.QuestionDetail, len(details))
+	for i, d := range details {
+		d.Label = Text(d.Key, nil, d.Label)
+		d.Value = Text(d.ValueKey, nil, d.Value)
+		out[i] = d
+	}
+	return out
+}
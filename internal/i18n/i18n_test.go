@@ -0,0 +1,140 @@
+package i18n
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+var placeholderRe = regexp.MustCompile(`\{[a-z_]+\}`)
+
+func placeholders(s string) string {
+	found := placeholderRe.FindAllString(s, -1)
+	sort.Strings(found)
+	return strings.Join(found, ",")
+}
+
+func TestCatalogsMatchEnglish(t *testing.T) {
+	t.Parallel()
+
+	en := catalogs[English]
+	if len(en) == 0 {
+		t.Fatal("English catalog is empty")
+	}
+	for _, lang := range []string{"uk", "ru", "de"} {
+		table, ok := catalogs[lang]
+		if !ok {
+			t.Fatalf("missing %s catalog", lang)
+		}
+		for key, tmpl := range en {
+			got, ok := table[key]
+			if !ok {
+				t.Fatalf("%s: missing key %q", lang, key)
+			}
+			if placeholders(got) != placeholders(tmpl) {
+				t.Fatalf("%s: %q placeholders = %q, want %q", lang, key, placeholders(got), placeholders(tmpl))
+			}
+		}
+		for key := range table {
+			if _, ok := en[key]; !ok {
+				t.Fatalf("%s: key %q is not in the English catalog", lang, key)
+			}
+		}
+	}
+}
+
+func TestParseAndDetect(t *testing.T) {
+	t.Parallel()
+
+	for in, want := range map[string]string{"": "en", "uk": "uk", "uk_UA.UTF-8": "uk", "de-AT": "de", "RU": "ru", "C": "en"} {
+		got, err := Parse(in)
+		if err != nil || got != want {
+			t.Fatalf("Parse(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := Parse("fr"); err == nil {
+		t.Fatal("Parse(fr) returned no error")
+	}
+
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	cases := []struct {
+		vars map[string]string
+		want string
+	}{
+		{map[string]string{"LANG": "de_DE.UTF-8"}, "de"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_MESSAGES": "ru_RU.UTF-8"}, "ru"},
+		{map[string]string{"LANG": "de_DE.UTF-8", "LC_ALL": "uk_UA.UTF-8"}, "uk"},
+		{map[string]string{"LANG": "fr_FR.UTF-8"}, "en"},
+		{map[string]string{}, "en"},
+	}
+	for _, tc := range cases {
+		if got := Detect(env(tc.vars)); got != tc.want {
+			t.Fatalf("Detect(%v) = %q, want %q", tc.vars, got, tc.want)
+		}
+	}
+}
+
+// The tests below switch the global language and must not run in parallel.
+
+func TestTextFallsBackToEventText(t *testing.T) {
+	defer SetLanguage(English)
+
+	if got := Text("log.db_connection_failed", map[string]string{"error": "x"}, "Database connection failed: x"); got != "Database connection failed: x" {
+		t.Fatalf("en Text = %q", got)
+	}
+	if got := T("ui.panel.log", nil); got != "Log" {
+		t.Fatalf("en T = %q, want Log", got)
+	}
+
+	SetLanguage("uk")
+	if got := Text("log.db_connection_failed", map[string]string{"error": "x"}, "Database connection failed: x"); got != "Не вдалося під’єднатися до бази даних: x" {
+		t.Fatalf("uk Text = %q", got)
+	}
+	if got := Text("no.such.key", nil, "English text"); got != "English text" {
+		t.Fatalf("missing key Text = %q, want fallback", got)
+	}
+	if got := Text("", nil, "Plain"); got != "Plain" {
+		t.Fatalf("keyless Text = %q, want fallback", got)
+	}
+}
+
+func TestQuestionLocalizesLabelsButKeepsIDs(t *testing.T) {
+	defer SetLanguage(English)
+	SetLanguage("de")
+
+	q := Question(domain.QuestionState{
+		ID:     "db_retry",
+		Prompt: "Database connection failed: refused — try again or exit installation?",
+		Key:    "question.db_retry",
+		Params: map[string]string{"error": "refused"},
+		Options: []domain.QuestionOption{
+			{ID: "exit", Label: "Exit installation", Key: "option.db_retry.exit"},
+			{ID: "custom", Label: "Custom label"},
+		},
+		Details: []domain.QuestionDetail{
+			{Label: "Database password", Value: "(empty)", Key: "field.db_password", ValueKey: "value.empty"},
+		},
+	})
+	if q.Prompt != "Datenbankverbindung fehlgeschlagen: refused — erneut versuchen oder Installation beenden?" {
+		t.Fatalf("Prompt = %q", q.Prompt)
+	}
+	if q.Options[0].ID != "exit" || q.Options[0].Label != "Installation beenden" {
+		t.Fatalf("Options[0] = %+v", q.Options[0])
+	}
+	if q.Options[1].Label != "Custom label" {
+		t.Fatalf("Options[1].Label = %q, want the untranslated label", q.Options[1].Label)
+	}
+	if q.Details[0].Label != "Datenbank-Passwort" || q.Details[0].Value != "(leer)" {
+		t.Fatalf("Details[0] = %+v", q.Details[0])
+	}
+
+	err := domain.ValidateAnswer("abc", domain.ValidatePassword)
+	if got := Error(err); got != "Das Passwort muss mindestens 6 Zeichen lang sein." {
+		t.Fatalf("Error = %q", got)
+	}
+}
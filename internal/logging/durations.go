@@ -0,0 +1,141 @@
+package logging
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+const (
+	stepDurationsFile = "step-durations.json"
+	// maxDurationSamples is how many recent runs are kept per step and variant.
+	maxDurationSamples = 5
+)
+
+// StepDurations keeps the durations of recently finished steps, keyed by step
+// ID and variant (the database driver), to estimate how long a run takes.
+type StepDurations struct {
+	// Steps maps "step" or "step/variant" to recent durations, oldest first.
+	Steps map[string][]time.Duration `json:"steps"`
+}
+
+// StepDurationsPath returns the user-level duration history location.
+// EVO_STEP_DURATIONS overrides the default under the user cache directory.
+func StepDurationsPath() (string, error) {
+	if p := strings.TrimSpace(os.Getenv("EVO_STEP_DURATIONS")); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "evo-installer", stepDurationsFile), nil
+}
+
+// LoadStepDurations reads the duration history; a missing file yields an
+// empty history.
+func LoadStepDurations(path string) (*StepDurations, error) {
+	d := &StepDurations{Steps: map[string][]time.Duration{}}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return d, nil
+		}
+		return d, err
+	}
+	if err := json.Unmarshal(raw, d); err != nil {
+		return &StepDurations{Steps: map[string][]time.Duration{}}, fmt.Errorf("invalid step durations %s: %w", path, err)
+	}
+	if d.Steps == nil {
+		d.Steps = map[string][]time.Duration{}
+	}
+	return d, nil
+}
+
+// Estimate returns the median recorded duration of a step for the variant.
+// Without samples for the variant it falls back to all samples of the step.
+func (d *StepDurations) Estimate(stepID string, variant string) (time.Duration, bool) {
+	if d == nil || stepID == "" {
+		return 0, false
+	}
+	if samples := d.Steps[durationKey(stepID, variant)]; len(samples) > 0 {
+		return median(samples), true
+	}
+	var all []time.Duration
+	for key, samples := range d.Steps {
+		if key == stepID || strings.HasPrefix(key, stepID+"/") {
+			all = append(all, samples...)
+		}
+	}
+	if len(all) == 0 {
+		return 0, false
+	}
+	return median(all), true
+}
+
+// Add records the steps of a run that finished successfully.
+func (d *StepDurations) Add(t *domain.Timings) {
+	if d == nil || t == nil {
+		return
+	}
+	if d.Steps == nil {
+		d.Steps = map[string][]time.Duration{}
+	}
+	for _, s := range t.Steps {
+		took := s.Duration()
+		if !s.Done || !s.OK || took <= 0 {
+			continue
+		}
+		key := durationKey(s.ID, s.Variant)
+		samples := append(d.Steps[key], took)
+		if len(samples) > maxDurationSamples {
+			samples = samples[len(samples)-maxDurationSamples:]
+		}
+		d.Steps[key] = samples
+	}
+}
+
+// Save writes the duration history, creating its directory.
+func (d *StepDurations) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	raw, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+// recordStepDurations adds this run's step durations to the history file.
+// Estimates are best effort, so errors are ignored.
+func (l *EventLogger) recordStepDurations() {
+	path := strings.TrimSpace(l.cfg.DurationsPath)
+	if path == "" {
+		return
+	}
+	// An unreadable or corrupt history is replaced.
+	d, _ := LoadStepDurations(path)
+	d.Add(&l.timings)
+	_ = d.Save(path)
+}
+
+func durationKey(stepID string, variant string) string {
+	if variant == "" {
+		return stepID
+	}
+	return stepID + "/" + variant
+}
+
+func median(samples []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted[len(sorted)/2]
+}
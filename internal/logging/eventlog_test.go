@@ -1,7 +1,13 @@
 package logging
 
 import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/evolution-cms/installer/internal/domain"
 )
@@ -27,3 +33,368 @@ func TestFinalizeWritesLogWhenStepDoneFails(t *testing.T) {
 		t.Fatalf("expected log to be written for failed step")
 	}
 }
+
+func TestFinalizeWritesNDJSONEntriesAndSummary(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := NewEventLogger(Config{InstallDir: dir, Always: true, DBType: "mysql", Formats: []string{FormatMarkdown, FormatNDJSON}})
+	logger.Record(domain.Event{
+		Type:     domain.EventError,
+		StepID:   "database",
+		Source:   "install",
+		Severity: domain.SeverityError,
+		Payload: domain.LogPayload{
+			Message: "Database connection failed.",
+			Fields:  map[string]string{"error": "access denied", "db_password": "secret", "op": "replace_last"},
+		},
+	})
+
+	res, err := logger.Finalize()
+	if err != nil {
+		t.Fatalf("Finalize error: %v", err)
+	}
+	if len(res.Paths) != 2 || filepath.Base(res.Paths[1]) != "log.ndjson" {
+		t.Fatalf("paths = %#v", res.Paths)
+	}
+
+	f, err := os.Open(filepath.Join(dir, "log.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("invalid ndjson line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) != 2 {
+		t.Fatalf("lines = %#v", lines)
+	}
+	entry := lines[0]
+	if entry["type"] != "entry" || entry["level"] != "error" || entry["step_id"] != "database" {
+		t.Fatalf("entry = %#v", entry)
+	}
+	fields, _ := entry["fields"].(map[string]any)
+	if fields["db_password"] != "<redacted>" || fields["op"] != nil {
+		t.Fatalf("fields = %#v", fields)
+	}
+	summary := lines[1]
+	if summary["type"] != "summary" || summary["result"] != "failed" || summary["failure_reason"] != "access denied" {
+		t.Fatalf("summary = %#v", summary)
+	}
+	if opts, _ := summary["options"].(string); !strings.Contains(opts, "db-type=mysql") {
+		t.Fatalf("summary options = %#v", summary["options"])
+	}
+}
+
+func TestParseFormatsRejectsUnknown(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseFormats([]string{"md,ndjson", "json", "md"})
+	if err != nil {
+		t.Fatalf("ParseFormats error: %v", err)
+	}
+	if strings.Join(got, ",") != "md,ndjson,json" {
+		t.Fatalf("formats = %#v", got)
+	}
+	if _, err := ParseFormats([]string{"yaml"}); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestFinalizeRecordsRunHistoryAndPrunes(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	indexPath := filepath.Join(t.TempDir(), "logs-index.json")
+	for i := 0; i < 2; i++ {
+		logger := NewEventLogger(Config{InstallDir: dir, HistoryIndex: indexPath})
+		logger.started = logger.started.Add(time.Duration(i) * time.Minute)
+		logger.Record(domain.Event{
+			Type:    domain.EventLog,
+			StepID:  "php",
+			Payload: domain.LogPayload{Message: "PHP version OK."},
+		})
+		res, err := logger.Finalize()
+		if err != nil || res.HistoryErr != nil {
+			t.Fatalf("Finalize error: %v / %v", err, res.HistoryErr)
+		}
+		if res.Written {
+			t.Fatal("log.md should only be written on failure or --log")
+		}
+		if !strings.HasSuffix(res.HistoryPath, "-completed.md") {
+			t.Fatalf("history path = %q", res.HistoryPath)
+		}
+	}
+
+	entries, err := LoadHistory(indexPath)
+	if err != nil {
+		t.Fatalf("LoadHistory error: %v", err)
+	}
+	if len(entries) != 2 || !entries[0].Started.After(entries[1].Started) {
+		t.Fatalf("entries = %#v", entries)
+	}
+
+	removed, err := PruneHistory(indexPath, 1)
+	if err != nil {
+		t.Fatalf("PruneHistory error: %v", err)
+	}
+	if len(removed) != 1 || removed[0].Path != entries[1].Path {
+		t.Fatalf("removed = %#v", removed)
+	}
+	if _, err := os.Stat(entries[1].Path); !os.IsNotExist(err) {
+		t.Fatalf("expected pruned log to be deleted, stat err=%v", err)
+	}
+}
+
+func TestHistoryPathsDoNotCollide(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	started := time.Now()
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		logger := NewEventLogger(Config{InstallDir: dir})
+		logger.started = started
+		logger.Record(domain.Event{
+			Type:    domain.EventLog,
+			StepID:  "php",
+			Payload: domain.LogPayload{Message: "PHP version OK."},
+		})
+		res, err := logger.Finalize()
+		if err != nil || res.HistoryErr != nil {
+			t.Fatalf("Finalize error: %v / %v", err, res.HistoryErr)
+		}
+		if seen[res.HistoryPath] {
+			t.Fatalf("history path %q written twice", res.HistoryPath)
+		}
+		seen[res.HistoryPath] = true
+	}
+	entries, err := os.ReadDir(HistoryDir(dir))
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("history dir has %d file(s), want 3 (err=%v)", len(entries), err)
+	}
+}
+
+func TestPlannedPathsFollowSummaryProjectDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := NewEventLogger(Config{})
+	logger.Record(domain.Event{
+		Type:    domain.EventLog,
+		StepID:  "php",
+		Payload: domain.LogPayload{Message: "PHP version OK."},
+	})
+	logger.Record(domain.Event{
+		Type:    domain.EventSummary,
+		Payload: domain.SummaryPayload{Summary: domain.InstallSummary{ProjectPath: dir}},
+	})
+
+	planned := logger.PlannedPaths()
+	if len(planned) != 1 || !strings.HasPrefix(planned[0], HistoryDir(dir)) {
+		t.Fatalf("PlannedPaths() = %#v, want one history path under %s", planned, dir)
+	}
+	res, err := logger.Finalize()
+	if err != nil || res.HistoryErr != nil {
+		t.Fatalf("Finalize error: %v / %v", err, res.HistoryErr)
+	}
+	if res.HistoryPath != planned[0] {
+		t.Fatalf("HistoryPath = %q, want %q", res.HistoryPath, planned[0])
+	}
+}
+
+func TestFinalizeRecordsStepDurations(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "cache", "step-durations.json")
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, took := range []time.Duration{40 * time.Second, 60 * time.Second, 50 * time.Second} {
+		logger := NewEventLogger(Config{InstallDir: t.TempDir(), DurationsPath: path})
+		logger.Record(domain.Event{Type: domain.EventStepStart, StepID: "install", TS: start, Payload: domain.StepStartPayload{Label: "Install", Variant: "mysql"}})
+		logger.Record(domain.Event{Type: domain.EventStepDone, StepID: "install", TS: start.Add(took), Payload: domain.StepDonePayload{OK: true}})
+		logger.Record(domain.Event{Type: domain.EventStepStart, StepID: "finalize", TS: start.Add(took)})
+		logger.Record(domain.Event{Type: domain.EventStepDone, StepID: "finalize", TS: start.Add(took + time.Second), Payload: domain.StepDonePayload{OK: false}})
+		if _, err := logger.Finalize(); err != nil {
+			t.Fatalf("Finalize error: %v", err)
+		}
+	}
+
+	d, err := LoadStepDurations(path)
+	if err != nil {
+		t.Fatalf("LoadStepDurations error: %v", err)
+	}
+	if got, ok := d.Estimate("install", "mysql"); !ok || got != 50*time.Second {
+		t.Fatalf("Estimate(install, mysql) = %s, %v, want 50s", got, ok)
+	}
+	if got, ok := d.Estimate("install", "pgsql"); !ok || got != 50*time.Second {
+		t.Fatalf("Estimate(install, pgsql) = %s, %v, want the fallback 50s", got, ok)
+	}
+	if _, ok := d.Estimate("finalize", "mysql"); ok {
+		t.Fatal("failed steps must not be recorded")
+	}
+
+	for i := 0; i < 10; i++ {
+		d.Add(&domain.Timings{Steps: []domain.StepTiming{{ID: "install", Variant: "mysql", Started: start, Ended: start.Add(time.Second), Done: true, OK: true}}})
+	}
+	if got := len(d.Steps["install/mysql"]); got != maxDurationSamples {
+		t.Fatalf("samples = %d, want %d", got, maxDurationSamples)
+	}
+}
+
+func TestRegisteredSecretsAreRedactedFromLogs(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecrets("pa$$-Wx9q7-secret")
+	RegisterSecretsFromEnv([]string{"CI_DEPLOY_TOKEN=tok-4f1d2e", "HOME=/home/evo"})
+
+	dir := t.TempDir()
+	logger := NewEventLogger(Config{InstallDir: dir, Always: true, Formats: []string{FormatMarkdown, FormatNDJSON}})
+	logger.Record(domain.Event{
+		Type:   domain.EventError,
+		StepID: "database",
+		Payload: domain.LogPayload{
+			Message: "SQLSTATE[HY000] [1045] Access denied (using pa$$-Wx9q7-secret)",
+			Fields:  map[string]string{"error": "dsn=mysql://root:pa%24%24-Wx9q7-secret@db tok-4f1d2e"},
+		},
+	})
+	if _, err := logger.Finalize(); err != nil {
+		t.Fatalf("Finalize error: %v", err)
+	}
+	for _, name := range []string{"log.md", "log.ndjson"} {
+		raw, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, secret := range []string{"Wx9q7", "tok-4f1d2e"} {
+			if strings.Contains(string(raw), secret) {
+				t.Fatalf("%s leaks %q:\n%s", name, secret, raw)
+			}
+		}
+	}
+}
+
+func TestURLPasswordsAreRedacted(t *testing.T) {
+	t.Parallel()
+
+	got := sanitizeMessage("connecting to pgsql://evo:Wx9q7-url@db:5432/site")
+	if strings.Contains(got, "Wx9q7") || !strings.Contains(got, "pgsql://evo:<redacted>@db") {
+		t.Fatalf("sanitizeMessage() = %q", got)
+	}
+}
+
+func TestLoadRedactPatterns(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "redact.txt")
+	if err := os.WriteFile(path, []byte("# comment\n\nacme-[0-9]{6}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadRedactPatterns(path, false); err != nil {
+		t.Fatalf("LoadRedactPatterns error: %v", err)
+	}
+	if got := Redact("license acme-123456 ok"); got != "license <redacted> ok" {
+		t.Fatalf("Redact = %q", got)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	if err := LoadRedactPatterns(missing, true); err != nil {
+		t.Fatalf("missing default file should be ignored, got %v", err)
+	}
+	if err := LoadRedactPatterns(missing, false); err == nil {
+		t.Fatal("expected error for a missing --redact-patterns file")
+	}
+}
+
+func TestFinalizeWritesTimingSection(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := NewEventLogger(Config{InstallDir: dir, Always: true, Formats: []string{FormatMarkdown, FormatJSON}})
+	start := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+	logger.Record(domain.Event{
+		Type:    domain.EventStepStart,
+		StepID:  "install",
+		TS:      start,
+		Payload: domain.StepStartPayload{Label: "Step 5: Install Evolution CMS"},
+	})
+	logger.Record(domain.Event{
+		Type:    domain.EventTiming,
+		StepID:  "install",
+		TS:      start.Add(3 * time.Minute),
+		Payload: domain.TimingPayload{Label: "Composer dependencies", Duration: 150 * time.Second},
+	})
+	logger.Record(domain.Event{
+		Type:    domain.EventStepDone,
+		StepID:  "install",
+		TS:      start.Add(4 * time.Minute),
+		Payload: domain.StepDonePayload{OK: true},
+	})
+
+	if _, err := logger.Finalize(); err != nil {
+		t.Fatalf("Finalize error: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "log.md"))
+	if err != nil {
+		t.Fatalf("read log.md: %v", err)
+	}
+	md := string(raw)
+	for _, want := range []string{"## Timing", "Step 5: Install Evolution CMS  4m00s  2m30s", "  Composer dependencies        2m30s", "Total"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("log.md missing %q:\n%s", want, md)
+		}
+	}
+
+	raw, err = os.ReadFile(filepath.Join(dir, "log.json"))
+	if err != nil {
+		t.Fatalf("read log.json: %v", err)
+	}
+	var doc jsonLog
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		t.Fatalf("decode log.json: %v", err)
+	}
+	if len(doc.Summary.Timing) != 1 {
+		t.Fatalf("timing = %#v", doc.Summary.Timing)
+	}
+	got := doc.Summary.Timing[0]
+	if got.DurationMS != 240000 || got.SubprocessMS != 150000 || len(got.Details) != 1 {
+		t.Fatalf("timing = %#v", got)
+	}
+}
+
+func TestLoggerFiltersBySeverity(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	logger := NewEventLogger(Config{InstallDir: dir, Always: true, LogLevel: domain.SeverityWarn})
+	for _, ev := range []domain.Event{
+		{Type: domain.EventLog, StepID: "install", Severity: domain.SeverityInfo, Payload: domain.LogPayload{Message: "Running database migrations..."}},
+		{Type: domain.EventLog, StepID: "install", Severity: domain.SeverityDebug, Payload: domain.LogPayload{Message: "- Installing psr/log (3.0.0)"}},
+		{Type: domain.EventLog, StepID: "install", Severity: domain.SeverityTrace, Payload: domain.LogPayload{Message: "exec: php install"}},
+		{Type: domain.EventLog, StepID: "install", Severity: domain.SeverityWarn, Payload: domain.LogPayload{Message: "Seeder failed softly"}},
+	} {
+		logger.Record(ev)
+	}
+	if _, err := logger.Finalize(); err != nil {
+		t.Fatalf("Finalize error: %v", err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, "log.md"))
+	if err != nil {
+		t.Fatalf("read log.md: %v", err)
+	}
+	md := string(raw)
+	if strings.Contains(md, "exec: php install") {
+		t.Fatalf("trace entry recorded without trace level:\n%s", md)
+	}
+	if !strings.Contains(md, "Full output (3 lines)") || !strings.Contains(md, "[DEBUG] - Installing psr/log") {
+		t.Fatalf("debug entry missing from full output:\n%s", md)
+	}
+	issues := md[strings.Index(md, "#### Issues"):strings.Index(md, "#### Highlights")]
+	if !strings.Contains(issues, "Seeder failed softly") || strings.Contains(issues, "migrations") {
+		t.Fatalf("issues = %q", issues)
+	}
+}
@@ -0,0 +1,240 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	OptionsSnapshotFile = "options.json"
+	historyDirName      = ".evo/logs"
+	historyIndexFile    = "logs-index.json"
+	DefaultHistoryKeep  = 20
+	maxIndexEntries     = 500
+)
+
+// HistoryDir returns the project-local run history directory.
+func HistoryDir(projectDir string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(historyDirName))
+}
+
+// HistoryEntry describes one stored run in the user-level log index.
+type HistoryEntry struct {
+	Path       string    `json:"path"`
+	ProjectDir string    `json:"project_dir"`
+	Started    time.Time `json:"started"`
+	Result     string    `json:"result"`
+	Version    string    `json:"version,omitempty"`
+	Mode       string    `json:"mode,omitempty"`
+}
+
+type historyIndex struct {
+	Entries []HistoryEntry `json:"entries"`
+}
+
+// HistoryIndexPath returns the user-level index location. EVO_LOG_INDEX
+// overrides the default under the user cache directory.
+func HistoryIndexPath() (string, error) {
+	if p := strings.TrimSpace(os.Getenv("EVO_LOG_INDEX")); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "evo-installer", historyIndexFile), nil
+}
+
+// HistoryKeep returns the per-project retention limit (EVO_LOG_KEEP).
+func HistoryKeep() int {
+	if v, err := strconv.Atoi(strings.TrimSpace(os.Getenv("EVO_LOG_KEEP"))); err == nil && v > 0 {
+		return v
+	}
+	return DefaultHistoryKeep
+}
+
+// LoadHistory returns indexed runs, newest first.
+func LoadHistory(indexPath string) ([]HistoryEntry, error) {
+	idx, err := readHistoryIndex(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	sortHistory(idx.Entries)
+	return idx.Entries, nil
+}
+
+// PruneHistory drops index entries whose files are gone and removes runs
+// beyond keep per project. It returns the removed entries.
+func PruneHistory(indexPath string, keep int) ([]HistoryEntry, error) {
+	idx, err := readHistoryIndex(indexPath)
+	if err != nil {
+		return nil, err
+	}
+	removed := applyRetention(&idx, keep)
+	return removed, writeHistoryIndex(indexPath, idx)
+}
+
+func (l *EventLogger) writeHistory(logDir string) (string, error) {
+	if len(l.buffer.entries) == 0 {
+		return "", nil
+	}
+	dir := HistoryDir(logDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	path := uniqueHistoryPath(l.historyPath(logDir))
+	if err := l.writeFile(path, FormatMarkdown); err != nil {
+		return "", err
+	}
+	pruneLocalHistory(dir, HistoryKeep())
+	if l.cfg.Options != nil {
+		if raw, err := json.MarshalIndent(l.cfg.Options, "", "  "); err == nil {
+			_ = os.WriteFile(filepath.Join(filepath.Dir(dir), OptionsSnapshotFile), append([]byte(Redact(string(raw))), '\n'), 0o644)
+		}
+	}
+
+	indexPath := strings.TrimSpace(l.cfg.HistoryIndex)
+	if indexPath == "" {
+		return path, nil
+	}
+	idx, err := readHistoryIndex(indexPath)
+	if err != nil {
+		return path, err
+	}
+	projectDir := logDir
+	if abs, err := filepath.Abs(logDir); err == nil {
+		projectDir = abs
+	}
+	idx.Entries = append(idx.Entries, HistoryEntry{
+		Path:       path,
+		ProjectDir: projectDir,
+		Started:    l.started,
+		Result:     l.result(),
+		Version:    strings.TrimSpace(l.cfg.Version),
+		Mode:       strings.TrimSpace(l.cfg.Mode),
+	})
+	applyRetention(&idx, HistoryKeep())
+	return path, writeHistoryIndex(indexPath, idx)
+}
+
+func (l *EventLogger) result() string {
+	if l.hadError {
+		return "failed"
+	}
+	return "completed"
+}
+
+// historyPath returns the absolute path of this run's history copy. The
+// millisecond timestamp keeps back-to-back runs apart.
+func (l *EventLogger) historyPath(logDir string) string {
+	path := filepath.Join(HistoryDir(logDir), fmt.Sprintf("%s-%s.md", l.started.Format("20060102-150405.000"), l.result()))
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// uniqueHistoryPath adds a -2, -3, ... suffix when another run already wrote
+// path, so concurrent runs never overwrite each other's log.
+func uniqueHistoryPath(path string) string {
+	base := strings.TrimSuffix(path, ".md")
+	for n := 2; ; n++ {
+		if _, err := os.Lstat(path); errors.Is(err, os.ErrNotExist) {
+			return path
+		}
+		path = fmt.Sprintf("%s-%d.md", base, n)
+	}
+}
+
+func applyRetention(idx *historyIndex, keep int) []HistoryEntry {
+	if keep <= 0 {
+		keep = DefaultHistoryKeep
+	}
+	sortHistory(idx.Entries)
+	perProject := map[string]int{}
+	kept := make([]HistoryEntry, 0, len(idx.Entries))
+	var removed []HistoryEntry
+	for _, entry := range idx.Entries {
+		if _, err := os.Stat(entry.Path); err != nil {
+			removed = append(removed, entry)
+			continue
+		}
+		perProject[entry.ProjectDir]++
+		if perProject[entry.ProjectDir] > keep || len(kept) >= maxIndexEntries {
+			_ = os.Remove(entry.Path)
+			removed = append(removed, entry)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	idx.Entries = kept
+	return removed
+}
+
+// pruneLocalHistory keeps the newest keep run logs in a project history dir.
+// File names start with a sortable timestamp.
+func pruneLocalHistory(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+	for i, name := range names {
+		if i >= keep {
+			_ = os.Remove(filepath.Join(dir, name))
+		}
+	}
+}
+
+func sortHistory(entries []HistoryEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Started.After(entries[j].Started)
+	})
+}
+
+func readHistoryIndex(path string) (historyIndex, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return historyIndex{}, nil
+		}
+		return historyIndex{}, err
+	}
+	var idx historyIndex
+	if err := json.Unmarshal(raw, &idx); err != nil {
+		return historyIndex{}, fmt.Errorf("invalid log index %s: %w", path, err)
+	}
+	return idx, nil
+}
+
+func writeHistoryIndex(path string, idx historyIndex) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(idx); err != nil {
+		return err
+	}
+	return w.Flush()
+}
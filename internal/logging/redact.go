@@ -0,0 +1,173 @@
+package logging
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+const redactedValue = "<redacted>"
+
+// minSecretLen keeps trivially short values (e.g. "1") from blanking out
+// unrelated text.
+const minSecretLen = 3
+
+// secrets holds the real secret values and extra patterns registered for the
+// current process. Every sink (log files, CLI output) passes through Redact.
+var secrets = &secretRegistry{}
+
+type secretRegistry struct {
+	mu       sync.RWMutex
+	values   []string
+	patterns []*regexp.Regexp
+}
+
+// RegisterSecrets records secret values so that every occurrence is redacted,
+// including their URL-encoded forms.
+func RegisterSecrets(values ...string) {
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if len([]rune(value)) < minSecretLen {
+			continue
+		}
+		secrets.add(value)
+		if escaped := url.QueryEscape(value); escaped != value {
+			secrets.add(escaped)
+		}
+		if escaped := url.PathEscape(value); escaped != value {
+			secrets.add(escaped)
+		}
+	}
+	// Longest first so a secret that contains another is replaced whole.
+	sort.SliceStable(secrets.values, func(i, j int) bool {
+		return len(secrets.values[i]) > len(secrets.values[j])
+	})
+}
+
+// RegisterSecretAnswer registers the answer to a secret question. A pasted
+// connection URL also carries the bare password, which is registered too.
+func RegisterSecretAnswer(answer string) {
+	RegisterSecrets(answer)
+	if u, err := domain.ParseDatabaseURL(answer); err == nil {
+		RegisterSecrets(u.Password)
+	}
+}
+
+// RegisterSecretsFromEnv registers the values of *_TOKEN variables from an
+// environment list in os.Environ form.
+func RegisterSecretsFromEnv(environ []string) {
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		if strings.HasSuffix(strings.ToUpper(key), "_TOKEN") {
+			RegisterSecrets(value)
+		}
+	}
+}
+
+// LoadRedactPatterns reads extra regular expressions, one per line; blank
+// lines and lines starting with # are ignored. A missing file is only
+// ignored when optional is set, i.e. for the implicit default location.
+func LoadRedactPatterns(path string, optional bool) error {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) && optional {
+			return nil
+		}
+		return fmt.Errorf("unable to read redact patterns: %w", err)
+	}
+	defer f.Close()
+
+	var patterns []*regexp.Regexp
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		re, err := regexp.Compile(text)
+		if err != nil {
+			return fmt.Errorf("%s:%d: invalid redact pattern: %w", path, line, err)
+		}
+		patterns = append(patterns, re)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	secrets.mu.Lock()
+	secrets.patterns = append(secrets.patterns, patterns...)
+	secrets.mu.Unlock()
+	return nil
+}
+
+// RedactPatternsPath returns the extra patterns file: EVO_REDACT_FILE or
+// redact.txt in the user config directory. optional reports the latter, which
+// does not have to exist.
+func RedactPatternsPath() (path string, optional bool) {
+	if p := strings.TrimSpace(os.Getenv("EVO_REDACT_FILE")); p != "" {
+		return p, false
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", true
+	}
+	return filepath.Join(dir, "evo-installer", "redact.txt"), true
+}
+
+// Redact replaces registered secret values and extra patterns in s.
+func Redact(s string) string {
+	if s == "" {
+		return s
+	}
+	secrets.mu.RLock()
+	defer secrets.mu.RUnlock()
+	for _, value := range secrets.values {
+		s = strings.ReplaceAll(s, value, redactedValue)
+	}
+	for _, re := range secrets.patterns {
+		s = re.ReplaceAllString(s, redactedValue)
+	}
+	return s
+}
+
+func (r *secretRegistry) add(value string) {
+	for _, existing := range r.values {
+		if existing == value {
+			return
+		}
+	}
+	r.values = append(r.values, value)
+}
+
+// RedactDocument applies Redact and the log sanitizing rules line by line,
+// keeping the line structure of multi-line text such as JSON or command output.
+func RedactDocument(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			lines[i] = line
+			continue
+		}
+		lines[i] = sanitizeMessage(line)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,207 @@
+package logging
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+const (
+	FormatMarkdown = "md"
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+)
+
+// ParseFormats normalizes --log-format values. Each value may itself be a
+// comma-separated list; an empty input selects Markdown only.
+func ParseFormats(values []string) ([]string, error) {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			format := strings.ToLower(strings.TrimSpace(part))
+			if format == "" {
+				continue
+			}
+			if format == "markdown" {
+				format = FormatMarkdown
+			}
+			switch format {
+			case FormatMarkdown, FormatJSON, FormatNDJSON:
+			default:
+				return nil, fmt.Errorf("invalid --log-format value: %q (use md, json or ndjson)", part)
+			}
+			if seen[format] {
+				continue
+			}
+			seen[format] = true
+			out = append(out, format)
+		}
+	}
+	if len(out) == 0 {
+		out = append(out, FormatMarkdown)
+	}
+	return out, nil
+}
+
+type jsonEntry struct {
+	Type    string            `json:"type,omitempty"`
+	TS      string            `json:"ts"`
+	Level   string            `json:"level"`
+	Source  string            `json:"source,omitempty"`
+	StepID  string            `json:"step_id,omitempty"`
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	// Key and Params let log viewers render Message in another UI language.
+	Key    string            `json:"key,omitempty"`
+	Params map[string]string `json:"params,omitempty"`
+}
+
+type jsonSummary struct {
+	Type          string       `json:"type,omitempty"`
+	Started       string       `json:"started"`
+	Ended         string       `json:"ended"`
+	DurationMS    int64        `json:"duration_ms"`
+	Result        string       `json:"result"`
+	FailureReason string       `json:"failure_reason,omitempty"`
+	FailedSteps   []string     `json:"failed_steps,omitempty"`
+	InstallDir    string       `json:"install_dir,omitempty"`
+	Version       string       `json:"version,omitempty"`
+	Mode          string       `json:"mode,omitempty"`
+	Options       string       `json:"options,omitempty"`
+	Timing        []jsonTiming `json:"timing,omitempty"`
+}
+
+type jsonTiming struct {
+	StepID       string             `json:"step_id"`
+	Label        string             `json:"label,omitempty"`
+	OK           bool               `json:"ok"`
+	DurationMS   int64              `json:"duration_ms"`
+	SubprocessMS int64              `json:"subprocess_ms,omitempty"`
+	Details      []jsonTimingDetail `json:"details,omitempty"`
+}
+
+type jsonTimingDetail struct {
+	Label      string `json:"label"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+type jsonLog struct {
+	Summary jsonSummary `json:"summary"`
+	Entries []jsonEntry `json:"entries"`
+}
+
+func writeJSON(w *bufio.Writer, cfg Config, started time.Time, ended time.Time, hadError bool, failedSteps map[string]bool, stepLabels map[string]string, entries []domain.LogEntry, timings *domain.Timings) error {
+	doc := jsonLog{
+		Summary: buildSummary(cfg, started, ended, hadError, failedSteps, stepLabels, entries, timings),
+		Entries: make([]jsonEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		doc.Entries = append(doc.Entries, buildJSONEntry(entry))
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(doc)
+}
+
+func writeNDJSON(w *bufio.Writer, cfg Config, started time.Time, ended time.Time, hadError bool, failedSteps map[string]bool, stepLabels map[string]string, entries []domain.LogEntry, timings *domain.Timings) error {
+	enc := json.NewEncoder(w)
+	for _, entry := range entries {
+		line := buildJSONEntry(entry)
+		line.Type = "entry"
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+	summary := buildSummary(cfg, started, ended, hadError, failedSteps, stepLabels, entries, timings)
+	summary.Type = "summary"
+	return enc.Encode(summary)
+}
+
+func buildJSONEntry(entry domain.LogEntry) jsonEntry {
+	level := string(entry.Level)
+	if level == "" {
+		level = string(domain.LogInfo)
+	}
+	return jsonEntry{
+		TS:      entry.TS.Format(time.RFC3339Nano),
+		Level:   level,
+		Source:  strings.TrimSpace(entry.Source),
+		StepID:  strings.TrimSpace(entry.StepID),
+		Message: sanitizeMessage(formatMessage(entry)),
+		Fields:  sanitizeFields(entry.Fields),
+		Key:     entry.Key,
+		Params:  sanitizeFields(entry.Params),
+	}
+}
+
+func buildSummary(cfg Config, started time.Time, ended time.Time, hadError bool, failedSteps map[string]bool, stepLabels map[string]string, entries []domain.LogEntry, timings *domain.Timings) jsonSummary {
+	stepGroups, _ := groupEntries(stepLabels, entries)
+	summary := jsonSummary{
+		Started:     started.Format(time.RFC3339),
+		Ended:       ended.Format(time.RFC3339),
+		DurationMS:  ended.Sub(started).Milliseconds(),
+		Result:      "completed",
+		FailedSteps: formatFailedSteps(stepGroups, failedSteps),
+		InstallDir:  strings.TrimSpace(cfg.InstallDir),
+		Version:     strings.TrimSpace(cfg.Version),
+		Mode:        strings.TrimSpace(cfg.Mode),
+		Options:     formatOptions(cfg),
+		Timing:      buildTiming(timings),
+	}
+	if hadError {
+		summary.Result = "failed"
+		summary.FailureReason = failureReason(entries)
+	}
+	return summary
+}
+
+func buildTiming(timings *domain.Timings) []jsonTiming {
+	if timings == nil {
+		return nil
+	}
+	var out []jsonTiming
+	for _, s := range timings.Steps {
+		if !s.Done {
+			continue
+		}
+		t := jsonTiming{
+			StepID:       s.ID,
+			Label:        s.Label,
+			OK:           s.OK,
+			DurationMS:   s.Duration().Milliseconds(),
+			SubprocessMS: s.SubprocessTime().Milliseconds(),
+		}
+		for _, d := range s.Details {
+			t.Details = append(t.Details, jsonTimingDetail{Label: sanitizeMessage(d.Label), DurationMS: d.Duration.Milliseconds()})
+		}
+		out = append(out, t)
+	}
+	return out
+}
+
+// sanitizeFields is the structured counterpart of formatFields: internal
+// rendering hints are dropped and sensitive values are redacted.
+func sanitizeFields(fields map[string]string) map[string]string {
+	if len(fields) == 0 {
+		return nil
+	}
+	out := map[string]string{}
+	for k, v := range fields {
+		if isInternalField(k) {
+			continue
+		}
+		if isSensitiveKey(k) {
+			v = "<redacted>"
+		}
+		out[k] = strings.TrimSpace(sanitizeMessage(v))
+	}
+	if len(out) == 0 {
+		return nil
+	}
+	return out
+}
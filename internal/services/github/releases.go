@@ -9,6 +9,8 @@ import (
 	"os"
 	"strconv"
 	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
 )
 
 type GitHubRelease struct {
@@ -45,8 +47,7 @@ func FetchLatestRelease(ctx context.Context, owner string, repo string) (GitHubR
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	client := &http.Client{Timeout: 12 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := doRequest(req)
 	if err != nil {
 		return GitHubRelease{}, err
 	}
@@ -103,8 +104,7 @@ func FetchReleasesPage(ctx context.Context, owner string, repo string, page int)
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	client := &http.Client{Timeout: 12 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -163,8 +163,7 @@ func FetchOrgRepositoriesPage(ctx context.Context, org string, page int) ([]GitH
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
-	client := &http.Client{Timeout: 12 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := doRequest(req)
 	if err != nil {
 		return nil, err
 	}
@@ -180,3 +179,18 @@ func FetchOrgRepositoriesPage(ctx context.Context, org string, page int) ([]GitH
 	}
 	return repos, nil
 }
+
+// doRequest sends an API request and reports it as a trace event through the
+// tracer attached to the request context.
+func doRequest(req *http.Request) (*http.Response, error) {
+	client := &http.Client{Timeout: 12 * time.Second}
+	started := time.Now()
+	resp, err := client.Do(req)
+	took := domain.FormatDuration(time.Since(started))
+	if err != nil {
+		domain.Tracef(req.Context(), "http", "%s %s failed after %s: %v", req.Method, req.URL.Redacted(), took, err)
+		return nil, err
+	}
+	domain.Tracef(req.Context(), "http", "%s %s -> %d after %s", req.Method, req.URL.Redacted(), resp.StatusCode, took)
+	return resp, nil
+}
@@ -326,28 +326,19 @@ func (m *Model) handleExtrasSelectKey(key string, lowerKey string) {
 }
 
 func (m *Model) handleExtrasSearchKey(key string, lowerKey string) {
-	switch lowerKey {
-	case "esc":
+	query, result := editSearchQuery(m.extras.searchQuery, key, lowerKey)
+	switch result {
+	case searchCancelled:
 		m.extras.searchActive = false
 		return
-	case "enter":
+	case searchSubmitted:
 		m.extras.searchActive = false
 		if len(m.visibleExtrasPackages()) > 0 {
 			m.extras.focus = extrasFocusList
 		}
 		return
-	case "ctrl+u":
-		m.extras.searchQuery = ""
-	case "backspace", "ctrl+h":
-		rs := []rune(m.extras.searchQuery)
-		if len(rs) > 0 {
-			m.extras.searchQuery = string(rs[:len(rs)-1])
-		}
-	default:
-		if len(key) == 1 && key != "/" {
-			m.extras.searchQuery += key
-		}
 	}
+	m.extras.searchQuery = query
 	m.extras.cursor = 0
 	if len(m.visibleExtrasPackages()) == 0 {
 		m.extras.focus = extrasFocusActions
@@ -399,6 +390,11 @@ func (m *Model) handleExtrasSummaryKey(lowerKey string) {
 	case "end":
 		m.extras.summaryCursor = len(m.extras.results) - 1
 	case "enter", "esc", "q":
+		if m.summary.active || !m.engineDone {
+			// The installation summary follows once the engine finishes.
+			m.extras = extrasUIState{}
+			return
+		}
 		m.quitRequested = true
 	}
 
@@ -640,10 +636,7 @@ func (m *Model) renderExtrasSelect(width int, height int) string {
 		"",
 	}
 
-	listHeight := innerH - len(lines) - 2
-	if listHeight < 1 {
-		listHeight = 1
-	}
+	_, listHeight, _ := extrasSelectRows(height)
 	lines = append(lines, m.renderExtrasList(contentW, listHeight)...)
 	lines = append(lines, "", m.renderExtrasSelectActions(contentW))
 
@@ -660,6 +653,16 @@ func (m *Model) renderExtrasSelect(width int, height int) string {
 	return base
 }
 
+// extrasSelectRows returns the panel body rows of the package list and of the
+// action buttons in an extras selection panel of the given height.
+func extrasSelectRows(height int) (listRow int, listHeight int, actionsRow int) {
+	// Title, filter line and a blank row above the list; a blank row and the
+	// actions below it.
+	listRow = 3
+	listHeight = max(1, height-2-listRow-2)
+	return listRow, listHeight, listRow + listHeight + 1
+}
+
 func (m *Model) renderExtrasVersionPicker(width int, height int) string {
 	if width <= 0 || height <= 0 {
 		return ""
@@ -669,7 +672,23 @@ func (m *Model) renderExtrasVersionPicker(width int, height int) string {
 		return ""
 	}
 
-	boxW := width - 6
+	boxW, boxH, listH := extrasVersionPickerSize(len(options), width, height)
+	contentW := panelContentWidth(boxW)
+	lines := []string{
+		truncatePlain("Select package version", contentW),
+		"",
+	}
+	lines = append(lines, m.renderExtrasVersionOptions(contentW, listH)...)
+	lines = append(lines, "", truncatePlain("Enter: select  Esc: cancel", contentW))
+
+	body := strings.Join(lines, "\n")
+	return panel("Package version", body, boxW, boxH)
+}
+
+// extrasVersionPickerSize returns the size of the version picker modal over an
+// extras panel of width x height and how many options it lists.
+func extrasVersionPickerSize(options int, width int, height int) (boxW int, boxH int, listH int) {
+	boxW = width - 6
 	if boxW > 72 {
 		boxW = 72
 	}
@@ -681,28 +700,18 @@ func (m *Model) renderExtrasVersionPicker(width int, height int) string {
 	if maxBodyH < 4 {
 		maxBodyH = 4
 	}
-	listH := len(options)
+	listH = options
 	if listH > maxBodyH-3 {
 		listH = max(1, maxBodyH-3)
 	}
-	boxH := listH + 4
+	boxH = listH + 4
 	if boxH > height-2 {
 		boxH = height - 2
 	}
 	if boxH < 7 {
 		boxH = 7
 	}
-
-	contentW := panelContentWidth(boxW)
-	lines := []string{
-		truncatePlain("Select package version", contentW),
-		"",
-	}
-	lines = append(lines, m.renderExtrasVersionOptions(contentW, listH)...)
-	lines = append(lines, "", truncatePlain("Enter: select  Esc: cancel", contentW))
-
-	body := strings.Join(lines, "\n")
-	return panel("Package version", body, boxW, boxH)
+	return boxW, boxH, listH
 }
 
 func (m *Model) renderExtrasProgress(width int, height int) string {
@@ -764,7 +773,13 @@ func (m *Model) renderExtrasSummary(width int, height int) string {
 	if innerH < 6 {
 		return minSizeView(width, height)
 	}
-	contentW := panelContentWidth(width)
+	body := strings.Join(m.extrasSummaryLines(panelContentWidth(width), innerH), "\n")
+	return panel("Extras summary", body, width, height)
+}
+
+// extrasSummaryLines returns the body of the extras summary panel; the last
+// line holds the Close button.
+func (m *Model) extrasSummaryLines(contentW int, innerH int) []string {
 	introLines := m.renderExtrasSummaryIntro(contentW)
 
 	lines := []string{
@@ -812,9 +827,7 @@ func (m *Model) renderExtrasSummary(width int, height int) string {
 		}
 	}
 
-	lines = append(lines, "", m.renderExtrasSummaryActions(contentW))
-	body := strings.Join(lines, "\n")
-	return panel("Extras summary", body, width, height)
+	return append(lines, "", m.renderExtrasSummaryActions(contentW))
 }
 
 func (m *Model) renderExtrasSummaryIntro(width int) []string {
@@ -995,36 +1008,15 @@ func (m *Model) renderExtrasList(width int, height int) []string {
 		return fitLines("(no extras found)", width, height)
 	}
 
-	visible := height
-	if visible > len(packages) {
-		visible = len(packages)
-	}
-	start := 0
-	if len(packages) > visible {
-		start = m.extras.cursor - visible/2
-		if start < 0 {
-			start = 0
-		}
-		maxStart := len(packages) - visible
-		if start > maxStart {
-			start = maxStart
-		}
-	}
-
 	out := make([]string, 0, height)
-	currentSection := ""
-	for i := 0; i < visible; i++ {
-		idx := start + i
+	for _, row := range m.extrasListRows(packages, height) {
+		idx := row.index
 		pkg := packages[idx]
 		key := extrasPackageKey(pkg)
-		section := strings.TrimSpace(pkg.Section)
-		if section != "" && section != currentSection && len(out) < height {
-			header := truncatePlain("  "+section, width)
+		if row.header {
+			header := truncatePlain("  "+strings.TrimSpace(pkg.Section), width)
 			out = append(out, mutedStyle.Copy().Bold(true).Render(header))
-			currentSection = section
-			if len(out) >= height {
-				break
-			}
+			continue
 		}
 
 		cursor := " "
@@ -1077,6 +1069,35 @@ func (m *Model) renderExtrasList(width int, height int) []string {
 	return out[:height]
 }
 
+// extrasListRow is a row of the extras list: a package, or the header of the
+// section the package opens.
+type extrasListRow struct {
+	index  int
+	header bool
+}
+
+// extrasListRows lays out the visible window of packages in height rows,
+// keeping the cursor in view.
+func (m *Model) extrasListRows(packages []domain.ExtrasPackage, height int) []extrasListRow {
+	visible := min(height, len(packages))
+	start := windowStart(m.extras.cursor, len(packages), visible)
+
+	rows := make([]extrasListRow, 0, height)
+	currentSection := ""
+	for idx := start; idx < start+visible && len(rows) < height; idx++ {
+		section := strings.TrimSpace(packages[idx].Section)
+		if section != "" && section != currentSection {
+			rows = append(rows, extrasListRow{index: idx, header: true})
+			currentSection = section
+			if len(rows) >= height {
+				break
+			}
+		}
+		rows = append(rows, extrasListRow{index: idx})
+	}
+	return rows
+}
+
 func (m *Model) extrasFilterLine() string {
 	source := "Bundled + Managed"
 	if m.extras.showLegacy {
@@ -1163,21 +1184,8 @@ func (m *Model) renderExtrasVersionOptions(width int, height int) []string {
 		return fitLines("(no versions found)", width, height)
 	}
 
-	visible := height
-	if visible > len(options) {
-		visible = len(options)
-	}
-	start := 0
-	if len(options) > visible {
-		start = m.extras.versionPickerCursor - visible/2
-		if start < 0 {
-			start = 0
-		}
-		maxStart := len(options) - visible
-		if start > maxStart {
-			start = maxStart
-		}
-	}
+	visible := min(height, len(options))
+	start := windowStart(m.extras.versionPickerCursor, len(options), visible)
 
 	out := make([]string, 0, height)
 	for i := 0; i < visible; i++ {
@@ -1348,9 +1356,9 @@ func extrasStatusMarker(status domain.ExtrasItemStatus) (string, lipgloss.Style)
 	}
 }
 
-func (m *Model) renderExtrasSelectActions(width int) string {
-	selected := m.extrasSelectedNames()
-	installLabel := fmt.Sprintf(" Install selected (%d) ", len(selected))
+// extrasSelectActionLabels returns the action buttons of the extras selection
+// in action order, as they are rendered two spaces apart.
+func (m *Model) extrasSelectActionLabels() []string {
 	skipLabel := " Skip extras "
 	if m.hasRequiredExtras() {
 		skipLabel = " Install required only "
@@ -1359,6 +1367,16 @@ func (m *Model) renderExtrasSelectActions(width int) string {
 	if m.extras.showLegacy {
 		legacyLabel = " Hide Legacy Store "
 	}
+	return []string{
+		fmt.Sprintf("[ Install selected (%d) ]", len(m.extrasSelectedNames())),
+		"[" + skipLabel + "]",
+		"[" + legacyLabel + "]",
+	}
+}
+
+func (m *Model) renderExtrasSelectActions(width int) string {
+	selected := m.extrasSelectedNames()
+	labels := m.extrasSelectActionLabels()
 
 	installStyle := mutedStyle
 	skipStyle := mutedStyle
@@ -1385,9 +1403,9 @@ func (m *Model) renderExtrasSelectActions(width int) string {
 		installStyle = mutedStyle
 	}
 
-	install := installStyle.Render("[" + installLabel + "]")
-	skip := skipStyle.Render("[" + skipLabel + "]")
-	legacy := legacyStyle.Render("[" + legacyLabel + "]")
+	install := installStyle.Render(labels[0])
+	skip := skipStyle.Render(labels[1])
+	legacy := legacyStyle.Render(labels[2])
 	line := install + "  " + skip + "  " + legacy
 	return padRight(truncateANSI(line, width), width)
 }
@@ -1401,9 +1419,10 @@ func (m *Model) hasRequiredExtras() bool {
 	return false
 }
 
+const extrasSummaryCloseLabel = "[ Close ]"
+
 func (m *Model) renderExtrasSummaryActions(width int) string {
-	closeLabel := " Close "
 	closeStyle := okStyle.Copy().Bold(true)
-	closeBtn := closeStyle.Render("[" + closeLabel + "]")
+	closeBtn := closeStyle.Render(extrasSummaryCloseLabel)
 	return padRight(truncateANSI(closeBtn, width), width)
 }
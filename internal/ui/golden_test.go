@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// Golden frame tests replay event scripts into a Model at a fixed terminal
+// size and compare the rendered frames with testdata/golden/<test>.golden.
+// Run `go test ./internal/ui -run Golden -update` to rewrite them.
+
+var updateGolden = flag.Bool("update", false, "rewrite golden files in testdata/golden")
+
+// goldenEpoch is the clock of replayed scripts; every script entry advances
+// it by a second.
+var goldenEpoch = time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+
+// Script entries besides domain.Event and key names.
+type (
+	// engineClosed closes the event stream.
+	engineClosed struct{}
+	// snapshot records the current frame under a name.
+	snapshot string
+	// press sends a key: "enter", "esc", "up", "down", "tab", "space",
+	// "ctrl+q", "ctrl+c" or literal runes.
+	press string
+)
+
+func replay(t *testing.T, width int, height int, script ...any) string {
+	t.Helper()
+
+	clock := goldenEpoch
+	m := NewModel(context.Background(), ModeInstall, nil, nil, Meta{Version: "1.2.3", Tagline: "The world’s fastest CMS!"}, nil, nil)
+	m.now = func() time.Time { return clock }
+	m.state.StartedAt = clock
+	m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+
+	var frames []string
+	for _, entry := range script {
+		clock = clock.Add(time.Second)
+		switch e := entry.(type) {
+		case domain.Event:
+			if e.TS.IsZero() {
+				e.TS = clock
+			}
+			m.Update(EventMsg{Event: e, OK: true})
+		case engineClosed:
+			m.Update(EventMsg{OK: false})
+		case press:
+			m.Update(keyMsg(string(e)))
+		case snapshot:
+			frames = append(frames, "── "+string(e)+" ──\n"+normalizeFrame(m.View()))
+		default:
+			t.Fatalf("unknown script entry %T", entry)
+		}
+	}
+	return strings.Join(frames, "\n")
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	types := map[string]tea.KeyType{
+		"enter": tea.KeyEnter, "esc": tea.KeyEsc, "up": tea.KeyUp, "down": tea.KeyDown,
+		"left": tea.KeyLeft, "right": tea.KeyRight, "tab": tea.KeyTab, "space": tea.KeySpace,
+		"ctrl+q": tea.KeyCtrlQ, "ctrl+c": tea.KeyCtrlC,
+	}
+	if kt, ok := types[k]; ok {
+		return tea.KeyMsg{Type: kt}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// normalizeFrame drops colors and trailing spaces so frames don't depend on
+// the terminal profile.
+func normalizeFrame(frame string) string {
+	lines := strings.Split(ansi.Strip(frame), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func assertGolden(t *testing.T, got string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", t.Name()+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Fatalf("frames differ from %s (run with -update to accept):\n%s", path, got)
+	}
+}
+
+// bootScript is the start of every run: the step plan, the release probe and
+// the system status.
+func bootScript() []any {
+	steps := []domain.StepState{
+		{ID: "php", Label: "Step 1: Validate PHP version", Key: "step.php"},
+		{ID: "database", Label: "Step 2: Check database connection", Key: "step.database"},
+		{ID: "project_preset", Label: "Step 3: Choose project preset", Key: "step.project_preset"},
+		{ID: "download", Label: "Step 4: Download Evolution CMS", Key: "step.download"},
+		{ID: "install", Label: "Step 5: Install Evolution CMS", Key: "step.install"},
+		{ID: "finalize", Label: "Step 6: Finalize installation", Key: "step.finalize"},
+		{ID: "extras", Label: "Step 7: Install Extras", Key: "step.extras"},
+	}
+	for i := range steps {
+		steps[i].Status = domain.StepPending
+	}
+	return []any{
+		domain.Event{Type: domain.EventSteps, Payload: domain.StepsPayload{Steps: steps}},
+		domain.Event{Type: domain.EventStepStart, StepID: "fetch_release_version", Payload: domain.StepStartPayload{Label: "Detect latest stable version"}},
+		domain.Event{Type: domain.EventStepDone, StepID: "fetch_release_version", Payload: domain.ReleaseInfo{HighestVersion: "3.3.0", Tag: "v3.3.0"}},
+		domain.Event{Type: domain.EventSystemStatus, Payload: domain.SystemStatus{
+			Overall: domain.StatusWarn,
+			Items: []domain.StatusItem{
+				{Key: "php", Label: "PHP 8.3.4", Level: domain.StatusOK},
+				{Key: "pdo_mysql", Label: "pdo_mysql", Level: domain.StatusOK},
+				{Key: "pdo_sqlsrv", Label: "pdo_sqlsrv", Level: domain.StatusWarn},
+			},
+		}},
+	}
+}
+
+func stepEvents(id string, index int, label string) []any {
+	return []any{
+		domain.Event{Type: domain.EventStepStart, StepID: id, Payload: domain.StepStartPayload{Label: label, Index: index, Total: 7, Key: "step." + id}},
+		domain.Event{Type: domain.EventLog, StepID: id, Payload: domain.LogPayload{Message: label + "…"}},
+		domain.Event{Type: domain.EventStepDone, StepID: id, Payload: domain.StepDonePayload{OK: true}},
+	}
+}
+
+func script(parts ...[]any) []any {
+	var out []any
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	return out
+}
+
+func driverQuestion() domain.Event {
+	return domain.Event{Type: domain.EventLog, StepID: "database", Payload: domain.QuestionPayload{Question: domain.QuestionState{
+		Active: true,
+		ID:     "db_driver",
+		Prompt: "Which database driver do you want to use?",
+		Key:    "question.db_driver",
+		Options: []domain.QuestionOption{
+			{ID: "sqlite", Label: "SQLite", Enabled: true},
+			{ID: "mysql", Label: "MySQL or MariaDB", Enabled: true},
+			{ID: "pgsql", Label: "PostgreSQL", Enabled: true},
+			{ID: "sqlsrv", Label: "SQL Server", Reason: "Missing PDO driver: pdo_sqlsrv"},
+		},
+	}}}
+}
+
+func TestGoldenQuestion(t *testing.T) {
+	frames := replay(t, 100, 30, script(
+		bootScript(),
+		stepEvents("php", 1, "Step 1: Validate PHP version"),
+		[]any{
+			domain.Event{Type: domain.EventStepStart, StepID: "database", Payload: domain.StepStartPayload{Label: "Step 2: Check database connection", Index: 2, Total: 7}},
+			driverQuestion(),
+			snapshot("question"),
+			press("down"),
+			press("down"),
+			snapshot("moved"),
+			press("enter"),
+			snapshot("answered"),
+		},
+	)...)
+	assertGolden(t, frames)
+}
+
+func TestGoldenNarrowLayouts(t *testing.T) {
+	run := script(
+		bootScript(),
+		stepEvents("php", 1, "Step 1: Validate PHP version"),
+		[]any{driverQuestion()},
+	)
+	// 80 columns drop the logo, 14 rows squeeze the Quest track, and 60
+	// columns are below the two-column minimum.
+	var frames string
+	for _, size := range [][2]int{{80, 24}, {80, 14}, {60, 24}} {
+		name := snapshot(fmt.Sprintf("%dx%d", size[0], size[1]))
+		frames += replay(t, size[0], size[1], append(run[:len(run):len(run)], name)...)
+	}
+	assertGolden(t, frames)
+}
+
+func TestGoldenExtras(t *testing.T) {
+	packages := []domain.ExtrasPackage{
+		{ID: "managed:sTask", Name: "sTask", Source: "managed", Version: "1.2.0", Description: "Task scheduler"},
+		{ID: "managed:sSeo", Name: "sSeo", Source: "managed", Version: "1.0.4", Description: "SEO tools"},
+		{ID: "bundled-inline:codemirror", Name: "CodeMirror", Source: "bundled-inline", Description: "Code editor"},
+	}
+	extras := func(state domain.ExtrasState) domain.Event {
+		state.Active = true
+		return domain.Event{Type: domain.EventExtras, StepID: "extras", Payload: state}
+	}
+	frames := replay(t, 100, 30, script(
+		bootScript(),
+		stepEvents("php", 1, "Step 1: Validate PHP version"),
+		stepEvents("database", 2, "Step 2: Check database connection"),
+		stepEvents("project_preset", 3, "Step 3: Choose project preset"),
+		stepEvents("download", 4, "Step 4: Download Evolution CMS"),
+		stepEvents("install", 5, "Step 5: Install Evolution CMS"),
+		stepEvents("finalize", 6, "Step 6: Finalize installation"),
+		[]any{
+			domain.Event{Type: domain.EventStepStart, StepID: "extras", Payload: domain.StepStartPayload{Label: "Step 7: Install Extras", Index: 7, Total: 7}},
+			extras(domain.ExtrasState{Stage: domain.ExtrasStageSelect, Packages: packages, Selections: []domain.ExtrasSelection{{ID: "managed:sTask", Name: "sTask"}}}),
+			snapshot("select"),
+			extras(domain.ExtrasState{Stage: domain.ExtrasStageProgress, Current: "sSeo", CurrentIndex: 2, Total: 2, Results: []domain.ExtrasItemResult{
+				{Name: "sTask", Status: domain.ExtrasStatusSuccess},
+				{Name: "sSeo", Status: domain.ExtrasStatusRunning},
+			}}),
+			snapshot("progress"),
+			extras(domain.ExtrasState{Stage: domain.ExtrasStageSummary, Total: 2, Results: []domain.ExtrasItemResult{
+				{Name: "sTask", Status: domain.ExtrasStatusSuccess},
+				{Name: "sSeo", Status: domain.ExtrasStatusError, Message: "composer require failed"},
+			}}),
+			snapshot("summary"),
+			press("enter"),
+			domain.Event{Type: domain.EventStepDone, StepID: "extras", Payload: domain.StepDonePayload{OK: true}},
+			domain.Event{Type: domain.EventSummary, Payload: domain.SummaryPayload{Summary: domain.InstallSummary{
+				ProjectPath:   "/var/www/evo",
+				ManagerPath:   "/manager/",
+				AdminUsername: "admin",
+				Details: []domain.QuestionDetail{
+					{Label: "Target directory", Value: "/var/www/evo", Key: "field.target_dir"},
+					{Label: "Manager URL", Value: "/manager/", Key: "field.manager_url"},
+				},
+				NextSteps: []domain.LogPayload{{Message: "Let the web server user write to core/storage and assets/.", Key: "summary.next.permissions"}},
+			}}},
+			engineClosed{},
+			snapshot("install summary"),
+		},
+	)...)
+	assertGolden(t, frames)
+}
+
+func TestGoldenQuitModal(t *testing.T) {
+	frames := replay(t, 100, 30, script(
+		bootScript(),
+		stepEvents("php", 1, "Step 1: Validate PHP version"),
+		stepEvents("database", 2, "Step 2: Check database connection"),
+		[]any{
+			domain.Event{Type: domain.EventStepStart, StepID: "download", Payload: domain.StepStartPayload{Label: "Step 4: Download Evolution CMS", Index: 4, Total: 7}},
+			domain.Event{Type: domain.EventProgress, StepID: "download", Payload: domain.ProgressPayload{Current: 40, Total: 100, Unit: "pct"}},
+			press("ctrl+q"),
+			snapshot("confirm"),
+			press("right"),
+			snapshot("continue selected"),
+			press("enter"),
+			snapshot("dismissed"),
+		},
+	)...)
+	assertGolden(t, frames)
+}
@@ -208,7 +208,10 @@ func (m *Model) questionBlockHeight(width int, maxHeight int) int {
 	case domain.QuestionInput:
 		need = 1 + 1 + 1 // separator + prompt + input
 	default:
-		need = 1 + 1 + len(m.state.Question.Options) // separator + prompt + options
+		need = 1 + 1 + len(m.state.Question.Details) + len(m.state.Question.Options) // separator + prompt + details + options
+	}
+	if m.state.Question.Error != "" {
+		need++
 	}
 	if need < 2 {
 		need = 2
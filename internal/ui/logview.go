@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
+)
+
+// logViewState holds the log pane search and filters. Filters only change
+// what is shown; entries are kept so clearing a filter restores them.
+type logViewState struct {
+	searchActive bool
+	searchQuery  string
+	// match is the index of the current match in logMatches().
+	match int
+
+	issuesOnly   bool
+	stepFilter   string
+	sourceFilter string
+}
+
+func (v logViewState) filtered() bool {
+	return v.issuesOnly || v.stepFilter != "" || v.sourceFilter != ""
+}
+
+// visibleLogEntries returns the entries that pass the active filters, one per
+// rendered log line.
+func (m *Model) visibleLogEntries() []domain.LogEntry {
+	if !m.logView.filtered() {
+		return m.state.Logs.Entries
+	}
+	out := make([]domain.LogEntry, 0, len(m.state.Logs.Entries))
+	for _, e := range m.state.Logs.Entries {
+		if m.logView.issuesOnly && e.Level != domain.LogWarning && e.Level != domain.LogError {
+			continue
+		}
+		if m.logView.stepFilter != "" && e.StepID != m.logView.stepFilter {
+			continue
+		}
+		if m.logView.sourceFilter != "" && e.Source != m.logView.sourceFilter {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// logMatches returns the line indexes of visible entries containing the
+// search query (case-insensitive).
+func (m *Model) logMatches() []int {
+	query := strings.ToLower(strings.TrimSpace(m.logView.searchQuery))
+	if query == "" {
+		return nil
+	}
+	var out []int
+	for i, e := range m.visibleLogEntries() {
+		if strings.Contains(strings.ToLower(e.Message), query) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// handleLogKey handles log pane keys while no question is active.
+func (m *Model) handleLogKey(key string, lowerKey string) bool {
+	if m.logView.searchActive {
+		m.handleLogSearchKey(key, lowerKey)
+		return true
+	}
+
+	switch key {
+	case "/":
+		m.logView.searchActive = true
+	case "n":
+		m.stepLogMatch(1)
+	case "N":
+		m.stepLogMatch(-1)
+	case "w":
+		m.logView.issuesOnly = !m.logView.issuesOnly
+		m.resetLogPosition()
+	case "s":
+		m.logView.stepFilter = nextLogFilter(m.logView.stepFilter, m.logFilterValues(func(e domain.LogEntry) string { return e.StepID }))
+		m.resetLogPosition()
+	case "o":
+		m.logView.sourceFilter = nextLogFilter(m.logView.sourceFilter, m.logFilterValues(func(e domain.LogEntry) string { return e.Source }))
+		m.resetLogPosition()
+	case "e":
+		m.jumpToFirstLogError()
+	case "esc":
+		if m.logView == (logViewState{}) {
+			return false
+		}
+		m.logView = logViewState{}
+		m.resetLogPosition()
+	default:
+		return false
+	}
+	return true
+}
+
+func (m *Model) handleLogSearchKey(key string, lowerKey string) {
+	query, result := editSearchQuery(m.logView.searchQuery, key, lowerKey)
+	switch result {
+	case searchCancelled:
+		m.logView.searchActive = false
+		return
+	case searchSubmitted:
+		m.logView.searchActive = false
+		m.scrollToLogMatch()
+		return
+	}
+	m.logView.searchQuery = query
+	m.logView.match = 0
+	m.scrollToLogMatch()
+}
+
+// stepLogMatch moves to the next (dir > 0) or previous match, wrapping around.
+func (m *Model) stepLogMatch(dir int) {
+	matches := m.logMatches()
+	if len(matches) == 0 {
+		return
+	}
+	m.logView.match = (m.logView.match + dir + len(matches)) % len(matches)
+	m.scrollToLogMatch()
+}
+
+func (m *Model) scrollToLogMatch() {
+	matches := m.logMatches()
+	if len(matches) == 0 {
+		m.logView.match = 0
+		return
+	}
+	m.logView.match = min(max(m.logView.match, 0), len(matches)-1)
+	m.scrollLogTo(matches[m.logView.match])
+}
+
+func (m *Model) jumpToFirstLogError() {
+	for i, e := range m.visibleLogEntries() {
+		if e.Level == domain.LogError {
+			m.scrollLogTo(i)
+			return
+		}
+	}
+}
+
+// scrollLogTo stops following and centers the given log line.
+func (m *Model) scrollLogTo(line int) {
+	m.followLogs = false
+	m.reflow()
+	m.logVP.SetYOffset(max(0, line-m.logVP.Height/2))
+}
+
+// resetLogPosition follows the tail again after the filters changed.
+func (m *Model) resetLogPosition() {
+	m.logView.match = 0
+	m.followLogs = true
+}
+
+// logFilterValues lists the distinct non-empty values in order of first
+// appearance.
+func (m *Model) logFilterValues(field func(domain.LogEntry) string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, e := range m.state.Logs.Entries {
+		v := field(e)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
+
+// nextLogFilter cycles "" (all) -> values[0] -> ... -> "" again.
+func nextLogFilter(current string, values []string) string {
+	if current == "" {
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+	for i, v := range values {
+		if v == current && i+1 < len(values) {
+			return values[i+1]
+		}
+	}
+	return ""
+}
+
+// logPanelTitle shows the active search and filters next to the pane title.
+func (m *Model) logPanelTitle() string {
+	parts := []string{}
+	if m.logView.searchActive || m.logView.searchQuery != "" {
+		search := "/" + m.logView.searchQuery
+		if m.logView.searchActive {
+			search += "▏"
+		} else if matches := m.logMatches(); len(matches) > 0 {
+			search += fmt.Sprintf(" %d/%d", m.logView.match+1, len(matches))
+		} else {
+			search += " no matches"
+		}
+		parts = append(parts, search)
+	}
+	if m.logView.issuesOnly {
+		parts = append(parts, "warnings+errors")
+	}
+	if m.logView.stepFilter != "" {
+		parts = append(parts, "step:"+m.logView.stepFilter)
+	}
+	if m.logView.sourceFilter != "" {
+		parts = append(parts, "source:"+m.logView.sourceFilter)
+	}
+	if len(parts) == 0 {
+		return i18n.T("ui.panel.log", nil)
+	}
+	return i18n.T("ui.panel.log", nil) + " — " + strings.Join(parts, " · ")
+}
+
+// highlightLogMatches marks every occurrence of query in message; the line
+// holding the current match uses a stronger style.
+func highlightLogMatches(message string, query string, current bool) string {
+	query = strings.TrimSpace(query)
+	lower := strings.ToLower(message)
+	if query == "" || len(lower) != len(message) {
+		return message
+	}
+	query = strings.ToLower(query)
+	style := logMatchStyle
+	if current {
+		style = logCurrentMatchStyle
+	}
+
+	var b strings.Builder
+	i := 0
+	for {
+		j := strings.Index(lower[i:], query)
+		if j < 0 {
+			break
+		}
+		b.WriteString(message[i : i+j])
+		b.WriteString(style.Render(message[i+j : i+j+len(query)]))
+		i += j + len(query)
+	}
+	b.WriteString(message[i:])
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestLogViewSearchAndFilters(t *testing.T) {
+	t.Parallel()
+
+	m := &Model{followLogs: true}
+	m.state.Logs.Entries = []domain.LogEntry{
+		{Level: domain.LogInfo, Source: "install", StepID: "php", Message: "PHP version 8.3 is supported."},
+		{Level: domain.LogDebug, Source: "php", StepID: "download", Message: "Installing symfony/console"},
+		{Level: domain.LogWarning, Source: "php", StepID: "download", Message: "Package foo/bar is abandoned"},
+		{Level: domain.LogError, Source: "extras", StepID: "extras", Message: "Composer require failed"},
+		{Level: domain.LogInfo, Source: "php", StepID: "install", Message: "composer dump-autoload"},
+	}
+
+	for _, k := range []string{"/", "C", "o", "m", "p", "o", "s", "e", "r", "enter"} {
+		m.handleLogKey(k, k)
+	}
+	if m.logView.searchActive || m.logView.searchQuery != "Composer" {
+		t.Fatalf("search state = %+v", m.logView)
+	}
+	if got := m.logMatches(); len(got) != 2 || got[0] != 3 || got[1] != 4 {
+		t.Fatalf("logMatches() = %v, want [3 4]", got)
+	}
+	if m.followLogs {
+		t.Fatal("search must stop following the log tail")
+	}
+	m.handleLogKey("n", "n")
+	m.handleLogKey("n", "n")
+	if m.logView.match != 0 {
+		t.Fatalf("match after wrapping = %d, want 0", m.logView.match)
+	}
+	m.handleLogKey("N", "n")
+	if m.logView.match != 1 {
+		t.Fatalf("match after previous = %d, want 1", m.logView.match)
+	}
+	if got, want := m.logPanelTitle(), "Log — /Composer 2/2"; got != want {
+		t.Fatalf("logPanelTitle() = %q, want %q", got, want)
+	}
+
+	m.handleLogKey("w", "w")
+	if got := len(m.visibleLogEntries()); got != 2 {
+		t.Fatalf("issues-only entries = %d, want 2", got)
+	}
+	m.handleLogKey("w", "w")
+
+	m.handleLogKey("o", "o")
+	m.handleLogKey("o", "o")
+	if m.logView.sourceFilter != "php" || len(m.visibleLogEntries()) != 3 {
+		t.Fatalf("source filter = %q with %d entries", m.logView.sourceFilter, len(m.visibleLogEntries()))
+	}
+	m.handleLogKey("s", "s")
+	m.handleLogKey("s", "s")
+	if m.logView.stepFilter != "download" || len(m.visibleLogEntries()) != 2 {
+		t.Fatalf("step filter = %q with %d entries", m.logView.stepFilter, len(m.visibleLogEntries()))
+	}
+
+	m.handleLogKey("esc", "esc")
+	if m.logView != (logViewState{}) || !m.followLogs {
+		t.Fatalf("esc must clear search and filters, got %+v", m.logView)
+	}
+	if m.handleLogKey("esc", "esc") {
+		t.Fatal("esc without search or filters must not be consumed")
+	}
+}
+
+func TestNextLogFilterCyclesThroughValues(t *testing.T) {
+	t.Parallel()
+
+	values := []string{"install", "php"}
+	got := []string{}
+	current := ""
+	for i := 0; i < 3; i++ {
+		current = nextLogFilter(current, values)
+		got = append(got, current)
+	}
+	if got[0] != "install" || got[1] != "php" || got[2] != "" {
+		t.Fatalf("cycle = %q", got)
+	}
+}
+
+func TestEditSearchQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		query, key string
+		want       string
+		result     searchKeyResult
+	}{
+		{"comp", "o", "compo", searchEdited},
+		{"comp", " ", "comp ", searchEdited},
+		{"comp", "/", "comp", searchEdited},
+		{"зап", "backspace", "за", searchEdited},
+		{"comp", "ctrl+u", "", searchEdited},
+		{"comp", "enter", "comp", searchSubmitted},
+		{"comp", "esc", "comp", searchCancelled},
+	}
+	for _, tt := range tests {
+		got, result := editSearchQuery(tt.query, tt.key, tt.key)
+		if got != tt.want || result != tt.result {
+			t.Fatalf("editSearchQuery(%q, %q) = %q, %d, want %q, %d", tt.query, tt.key, got, result, tt.want, tt.result)
+		}
+	}
+}
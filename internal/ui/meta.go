@@ -1,7 +1,23 @@
 package ui
 
+import (
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/logging"
+)
+
 type Meta struct {
 	Version string
 	Tagline string
 	Branch  string
+	// LogLevel is the minimum severity shown in the log panel (default info).
+	LogLevel domain.Severity
+	// Theme is a theme name (see ParseTheme); auto follows the terminal
+	// background.
+	Theme string
+	// StepDurations are durations of earlier runs, used for the ETA in the
+	// Quest track; nil hides estimates.
+	StepDurations *logging.StepDurations
+	// TermProgress reports progress in the terminal tab and title and
+	// notifies when the install finishes or needs input.
+	TermProgress bool
 }
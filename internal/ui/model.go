@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
 	"github.com/evolution-cms/installer/internal/logging"
 )
 
@@ -72,6 +74,23 @@ type Model struct {
 	logger *logging.EventLogger
 
 	extras extrasUIState
+
+	logView logViewState
+
+	timings domain.Timings
+	// lastOutput is when the engine last reported progress; the Quest track
+	// flags silent steps.
+	lastOutput time.Time
+
+	summary summaryUIState
+	// clipboard receives OSC 52 sequences (the terminal output).
+	clipboard io.Writer
+	// termStatus mirrors progress in the terminal tab and title; nil when
+	// disabled.
+	termStatus *TerminalStatus
+
+	// now replaces time.Now in tests that compare rendered frames.
+	now func() time.Time
 }
 
 func NewModel(ctx context.Context, mode Mode, events <-chan domain.Event, actions chan<- domain.Action, meta Meta, cancel func(), logger *logging.EventLogger) *Model {
@@ -99,7 +118,7 @@ func NewModel(ctx context.Context, mode Mode, events <-chan domain.Event, action
 		actions:             actions,
 		state:               state,
 		meta:                meta,
-		progress:            progress.New(progress.WithSolidFill(progressFillHex), progress.WithoutPercentage()),
+		progress:            progress.New(progress.WithSolidFill(activeTheme.progress), progress.WithoutPercentage()),
 		spin:                spin,
 		systemStatusLoading: true,
 		followLogs:          true,
@@ -145,6 +164,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, pulseTick()
 
+	case tea.MouseMsg:
+		return m.handleMouse(msg)
+
 	case tea.KeyMsg:
 		key := msg.String()
 		lowerKey := strings.ToLower(key)
@@ -225,6 +247,18 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 
+		if m.summaryVisible() {
+			m.handleSummaryKey(lowerKey)
+			m.reflow()
+			if m.quitRequested {
+				if m.cancel != nil {
+					m.cancel()
+				}
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+
 		if m.extras.active {
 			if m.handleExtrasKey(key, lowerKey) {
 				m.reflow()
@@ -262,12 +296,36 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+			if (key == "esc" || key == "shift+tab") && m.state.Question.CanGoBack {
+				m.sendAction(domain.Action{
+					Type:       domain.ActionBack,
+					QuestionID: m.state.Question.ID,
+				})
+				m.state.Question.Active = false
+				m.inputValue = ""
+				m.inputTouched = false
+				m.reflow()
+				return m, nil
+			}
+
 			if kind == domain.QuestionInput {
 				if key == "enter" {
 					text := m.inputValue
 					if !m.inputTouched {
 						text = m.state.Question.Default
 					}
+					check := text
+					if strings.TrimSpace(check) == "" {
+						check = m.state.Question.Default
+					}
+					if err := domain.ValidateAnswer(check, m.state.Question.Validate...); err != nil {
+						m.state.Question.Error = i18n.Error(err)
+						m.reflow()
+						return m, nil
+					}
+					if m.state.Question.Secret {
+						logging.RegisterSecretAnswer(text)
+					}
 					m.sendAction(domain.Action{
 						Type:       domain.ActionAnswerInput,
 						QuestionID: m.state.Question.ID,
@@ -344,6 +402,17 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		if key == "tab" && m.summary.active {
+			m.summary.hidden = false
+			m.reflow()
+			return m, nil
+		}
+
+		if m.handleLogKey(key, lowerKey) {
+			m.reflow()
+			return m, nil
+		}
+
 		// No active question: arrows scroll the log viewport.
 		switch key {
 		case "up":
@@ -368,6 +437,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.engineDone = true
 			m.state.Release.Loading = false
 			m.systemStatusLoading = false
+			m.addTimingSummary()
 			m.reflow()
 			return m, nil
 		}
@@ -387,11 +457,44 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// showsLog reports whether a log event passes the --log-level filter.
+func (m *Model) showsLog(ev domain.Event) bool {
+	return domain.EventSeverity(ev).Enabled(m.meta.LogLevel)
+}
+
+// addTimingSummary appends the per-step timing table to the log once the
+// engine has finished.
+func (m *Model) addTimingSummary() {
+	table := m.timings.Table()
+	if len(table) == 0 {
+		return
+	}
+	now := m.clock()
+	m.state.Logs.Entries = append(m.state.Logs.Entries, domain.LogEntry{
+		TS:      now,
+		Level:   domain.LogInfo,
+		Source:  "ui",
+		Message: "Timing:",
+	})
+	for _, line := range table {
+		m.state.Logs.Entries = append(m.state.Logs.Entries, domain.LogEntry{
+			TS:      now,
+			Level:   domain.LogInfo,
+			Source:  "ui",
+			Message: line,
+			Fields:  map[string]string{"kind": "timing"},
+		})
+	}
+	if m.state.Logs.Max > 0 && len(m.state.Logs.Entries) > m.state.Logs.Max {
+		m.state.Logs.Entries = m.state.Logs.Entries[len(m.state.Logs.Entries)-m.state.Logs.Max:]
+	}
+}
+
 func (m *Model) requestCancel(key string) {
 	m.cancelling = true
 	m.state.Question.Active = false
 	entry := domain.LogEntry{
-		TS:      time.Now(),
+		TS:      m.clock(),
 		Level:   domain.LogWarning,
 		Source:  "ui",
 		StepID:  "",
@@ -421,6 +524,9 @@ func (m *Model) applyEvent(ev domain.Event) {
 	if m.logger != nil {
 		m.logger.Record(ev)
 	}
+	m.timings.Observe(ev)
+	m.noteOutput(ev)
+	m.termStatus.Observe(ev)
 
 	switch ev.Type {
 	case domain.EventExecRequest:
@@ -435,6 +541,9 @@ func (m *Model) applyEvent(ev domain.Event) {
 		case []domain.StepState:
 			m.state.Steps = cloneSteps(p)
 		}
+		for i := range m.state.Steps {
+			m.state.Steps[i].Label = i18n.Step(m.state.Steps[i])
+		}
 	case domain.EventStepStart:
 		if !isInternalStep(ev.StepID) {
 			m.setStepActive(ev)
@@ -464,7 +573,9 @@ func (m *Model) applyEvent(ev domain.Event) {
 		}
 		m.systemStatusLoading = false
 	case domain.EventWarning:
-		m.addLog(ev, domain.LogWarning)
+		if m.showsLog(ev) {
+			m.addLog(ev, domain.LogWarning)
+		}
 		m.setStepWarn(ev.StepID)
 		if ev.StepID == "fetch_release_version" {
 			m.state.Release.Loading = false
@@ -496,19 +607,22 @@ func (m *Model) applyEvent(ev domain.Event) {
 		// Can be a plain log or structured state update.
 		switch payload := ev.Payload.(type) {
 		case domain.QuestionPayload:
-			m.state.Question = payload.Question
+			m.logView.searchActive = false
+			m.state.Question = i18n.Question(payload.Question)
 			if m.state.Question.Kind == "" {
 				m.state.Question.Kind = domain.QuestionSelect
 			}
-			if m.state.Question.Kind == domain.QuestionInput {
-				m.inputValue = ""
-				m.inputTouched = false
-			} else {
-				m.inputValue = ""
-				m.inputTouched = false
+			m.inputValue = ""
+			m.inputTouched = false
+			if m.state.Question.Kind == domain.QuestionInput && m.state.Question.Value != "" {
+				// Revisited question: start from the previous answer.
+				m.inputValue = m.state.Question.Value
+				m.inputTouched = true
 			}
 		case domain.LogPayload:
-			m.addLog(ev, domain.LogInfo)
+			if m.showsLog(ev) {
+				m.addLog(ev, domain.EventSeverity(ev).LogLevel())
+			}
 		default:
 			// Ignore unknown payload types; UI must not parse text.
 		}
@@ -516,6 +630,10 @@ func (m *Model) applyEvent(ev domain.Event) {
 		if p, ok := ev.Payload.(domain.ExtrasState); ok {
 			m.applyExtrasState(p)
 		}
+	case domain.EventSummary:
+		if p, ok := ev.Payload.(domain.SummaryPayload); ok {
+			m.applySummary(p.Summary)
+		}
 	}
 }
 
@@ -552,6 +670,7 @@ func (m *Model) addLog(ev domain.Event, level domain.LogLevel) {
 	if !ok {
 		return
 	}
+	payload.Message = i18n.Log(payload)
 
 	// Structured in-place updates (used for inline progress lines).
 	if payload.Fields != nil {
@@ -603,7 +722,7 @@ func (m *Model) addLog(ev domain.Event, level domain.LogLevel) {
 func (m *Model) setStepActive(ev domain.Event) {
 	label := ev.StepID
 	if p, ok := ev.Payload.(domain.StepStartPayload); ok && p.Label != "" {
-		label = p.Label
+		label = i18n.Text(p.Key, nil, p.Label)
 	}
 
 	found := false
@@ -636,7 +755,7 @@ func (m *Model) setStepDone(ev domain.Event) {
 		}
 	}
 	if allDone(m.state.Steps) && m.state.EndedAt == nil {
-		t := time.Now()
+		t := m.clock()
 		m.state.EndedAt = &t
 	}
 }
@@ -667,7 +786,7 @@ func (m *Model) setProgress(ev domain.Event) {
 		Current: p.Current,
 		Total:   p.Total,
 		Unit:    p.Unit,
-		Updated: time.Now(),
+		Updated: m.clock(),
 		Visible: true,
 	}
 }
@@ -691,6 +810,14 @@ func waitForEvent(events <-chan domain.Event) tea.Cmd {
 	}
 }
 
+// clock returns the current time.
+func (m *Model) clock() time.Time {
+	if m.now != nil {
+		return m.now()
+	}
+	return time.Now()
+}
+
 func pulseTick() tea.Cmd {
 	return tea.Tick(500*time.Millisecond, func(time.Time) tea.Msg { return pulseMsg{} })
 }
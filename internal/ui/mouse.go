@@ -0,0 +1,236 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// wheelLines is how far one wheel notch scrolls a viewport.
+const wheelLines = 3
+
+// zone is a screen rectangle in cells.
+type zone struct {
+	x, y, w, h int
+}
+
+func (z zone) contains(x int, y int) bool {
+	return x >= z.x && x < z.x+z.w && y >= z.y && y < z.y+z.h
+}
+
+// body is the content area of a panel drawn in z: inside the border and the
+// one-cell horizontal padding.
+func (z zone) body() zone {
+	return zone{x: z.x + 2, y: z.y + 1, w: panelContentWidth(z.w), h: panelBodyHeight(z.h, true)}
+}
+
+// Panel zones of the main view. They derive from the same layoutState that
+// View renders, so hit-testing follows every reflow.
+
+func (l layoutState) questZone() zone {
+	return zone{x: 0, y: l.headerH, w: l.leftW, h: l.questH}
+}
+
+func (l layoutState) statusZone() zone {
+	return zone{x: l.leftW + l.gap, y: 0, w: l.rightW, h: l.topAreaH}
+}
+
+func (l layoutState) logZone() zone {
+	return zone{x: 0, y: l.topAreaH, w: l.width, h: l.logH}
+}
+
+// questionZone is the question block pinned to the bottom of the log panel.
+func (l layoutState) questionZone() zone {
+	b := l.logZone().body()
+	return zone{x: b.x, y: b.y + b.h - l.logQuestionH, w: b.w, h: l.logQuestionH}
+}
+
+// handleMouse maps clicks and wheel notches onto the screen that is currently
+// rendered. Where a click stands for a key (submitting a question, pressing a
+// button), it replays that key so mouse and keyboard share one code path.
+func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.confirmQuitActive || m.width <= 0 || m.height <= 0 {
+		return m, nil
+	}
+	ev := tea.MouseEvent(msg)
+	wheel := 0
+	switch {
+	case ev.Action != tea.MouseActionPress:
+		return m, nil
+	case ev.Button == tea.MouseButtonWheelUp:
+		wheel = -1
+	case ev.Button == tea.MouseButtonWheelDown:
+		wheel = 1
+	case ev.Button != tea.MouseButtonLeft:
+		return m, nil
+	}
+	wheelKey := tea.KeyMsg{Type: tea.KeyUp}
+	if wheel > 0 {
+		wheelKey = tea.KeyMsg{Type: tea.KeyDown}
+	}
+
+	usableH := max(0, m.height-1)
+	screen := zone{x: 0, y: 0, w: m.width, h: usableH}
+	switch {
+	case m.cancelling && m.engineDone, m.state.Release.Loading || m.systemStatusLoading:
+		return m, nil
+	case m.summaryVisible():
+		if wheel != 0 {
+			return m.Update(wheelKey)
+		}
+		return m, nil
+	case m.extras.active:
+		switch m.extras.stage {
+		case domain.ExtrasStageSelect:
+			if wheel != 0 {
+				return m.Update(wheelKey)
+			}
+			return m.clickExtrasSelect(screen, ev.X, ev.Y)
+		case domain.ExtrasStageSummary:
+			if wheel != 0 {
+				return m.Update(wheelKey)
+			}
+			body := screen.body()
+			lines := m.extrasSummaryLines(body.w, body.h)
+			closeBtn := zone{x: body.x, y: body.y + min(len(lines), body.h) - 1, w: lipgloss.Width(extrasSummaryCloseLabel), h: 1}
+			if closeBtn.contains(ev.X, ev.Y) {
+				return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			}
+		}
+		return m, nil
+	}
+
+	if m.layout.tooSmall {
+		return m, nil
+	}
+	if wheel != 0 {
+		m.scrollAt(ev.X, ev.Y, wheel)
+		m.reflow()
+		return m, nil
+	}
+	return m.clickQuestion(ev.X, ev.Y)
+}
+
+// scrollAt scrolls the viewport of the panel under the pointer.
+func (m *Model) scrollAt(x int, y int, dir int) {
+	switch {
+	case m.layout.questZone().contains(x, y):
+		if dir < 0 {
+			m.questVP.LineUp(wheelLines)
+		} else {
+			m.questVP.LineDown(wheelLines)
+		}
+	case m.layout.statusZone().contains(x, y):
+		if dir < 0 {
+			m.statusVP.LineUp(wheelLines)
+		} else {
+			m.statusVP.LineDown(wheelLines)
+		}
+	case m.layout.logZone().contains(x, y):
+		if dir < 0 {
+			m.followLogs = false
+			m.logVP.LineUp(wheelLines)
+			return
+		}
+		m.logVP.LineDown(wheelLines)
+		if m.logVP.AtBottom() {
+			m.followLogs = true
+		}
+	}
+}
+
+// clickQuestion selects the clicked option of a select question; clicking the
+// selected option submits it.
+func (m *Model) clickQuestion(x int, y int) (tea.Model, tea.Cmd) {
+	q := m.state.Question
+	if !q.Active || (q.Kind != "" && q.Kind != domain.QuestionSelect) {
+		return m, nil
+	}
+	z := m.layout.questionZone()
+	if !z.contains(x, y) {
+		return m, nil
+	}
+	row, start, visible := m.questionOptionRows(z.h)
+	offset := y - z.y - row
+	if offset < 0 || offset >= visible {
+		return m, nil
+	}
+	idx := start + offset
+	if !q.Options[idx].Enabled {
+		return m, nil
+	}
+	if idx == q.Selected {
+		return m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	}
+	m.state.Question.Selected = idx
+	m.reflow()
+	return m, nil
+}
+
+// clickExtrasSelect handles the extras selection panel: the version picker
+// when it is open, otherwise the package list and the action buttons.
+func (m *Model) clickExtrasSelect(screen zone, x int, y int) (tea.Model, tea.Cmd) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	if m.extras.versionPickerActive {
+		options := len(m.extras.versionPickerOptions)
+		boxW, boxH, listH := extrasVersionPickerSize(options, screen.w, screen.h)
+		box := zone{x: max(0, (screen.w-boxW)/2), y: max(0, (screen.h-boxH)/2), w: boxW, h: boxH}
+		if !box.contains(x, y) {
+			m.closeExtrasVersionPicker()
+			return m, nil
+		}
+		// Title and a blank row precede the options.
+		body := box.body()
+		offset := y - body.y - 2
+		if offset < 0 || offset >= min(listH, options) || x < body.x {
+			return m, nil
+		}
+		m.extras.versionPickerCursor = windowStart(m.extras.versionPickerCursor, options, min(listH, options)) + offset
+		return m.Update(enter)
+	}
+
+	m.extras.searchActive = false
+	body := screen.body()
+	listRow, listHeight, actionsRow := extrasSelectRows(screen.h)
+	row := y - body.y
+	if x < body.x || x >= body.x+body.w {
+		return m, nil
+	}
+
+	if row == actionsRow {
+		left := body.x
+		for i, label := range m.extrasSelectActionLabels() {
+			w := lipgloss.Width(label)
+			if x >= left && x < left+w {
+				m.extras.focus = extrasFocusActions
+				m.extras.action = i
+				return m.Update(enter)
+			}
+			left += w + 2
+		}
+		return m, nil
+	}
+
+	packages := m.visibleExtrasPackages()
+	rows := m.extrasListRows(packages, listHeight)
+	if row < listRow || row-listRow >= len(rows) || rows[row-listRow].header {
+		return m, nil
+	}
+	idx := rows[row-listRow].index
+	// Rows read "> [x] name": the checkbox toggles, the rest of the row moves
+	// the cursor and, on the current row, opens the version picker.
+	onCheckbox := x >= body.x+2 && x < body.x+5
+	current := idx == m.extras.cursor && m.extras.focus == extrasFocusList
+	m.extras.cursor = idx
+	m.extras.focus = extrasFocusList
+	switch {
+	case onCheckbox:
+		return m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	case current:
+		return m.Update(enter)
+	}
+	m.reflow()
+	return m, nil
+}
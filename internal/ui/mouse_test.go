@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+// Like the golden tests, these render English text and don't run in parallel.
+
+func mouseModel(width int, height int, actions chan domain.Action, script ...any) *Model {
+	m := NewModel(context.Background(), ModeInstall, nil, actions, Meta{Version: "1.2.3"}, nil, nil)
+	m.Update(tea.WindowSizeMsg{Width: width, Height: height})
+	for _, entry := range script {
+		if ev, ok := entry.(domain.Event); ok {
+			m.Update(EventMsg{Event: ev, OK: true})
+		}
+	}
+	return m
+}
+
+// locate returns the screen cell where text first appears in the frame.
+func locate(t *testing.T, m *Model, text string) (int, int) {
+	t.Helper()
+	for y, line := range strings.Split(ansi.Strip(m.View()), "\n") {
+		if i := strings.Index(line, text); i >= 0 {
+			return ansi.StringWidth(line[:i]), y
+		}
+	}
+	t.Fatalf("%q not on screen", text)
+	return 0, 0
+}
+
+func click(m *Model, x int, y int) {
+	m.Update(tea.MouseMsg{X: x, Y: y, Action: tea.MouseActionPress, Button: tea.MouseButtonLeft})
+}
+
+func TestMouseSelectsAndSubmitsQuestionOptions(t *testing.T) {
+	actions := make(chan domain.Action, 1)
+	m := mouseModel(100, 30, actions, script(bootScript(), []any{driverQuestion()})...)
+
+	x, y := locate(t, m, "PostgreSQL")
+	click(m, x, y)
+	if got := m.state.Question.Selected; got != 2 {
+		t.Fatalf("Selected = %d, want 2", got)
+	}
+
+	// Resizing reflows the log panel; clicks must follow the new layout.
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+
+	x, y = locate(t, m, "SQL Server")
+	click(m, x, y)
+	if got := m.state.Question.Selected; got != 2 {
+		t.Fatalf("disabled option selected: Selected = %d", got)
+	}
+
+	x, y = locate(t, m, "MySQL or MariaDB")
+	click(m, x, y)
+	if got := m.state.Question.Selected; got != 1 {
+		t.Fatalf("Selected = %d, want 1", got)
+	}
+	click(m, x, y)
+	select {
+	case a := <-actions:
+		if a.OptionID != "mysql" {
+			t.Fatalf("OptionID = %q, want %q", a.OptionID, "mysql")
+		}
+	default:
+		t.Fatalf("clicking the selected option did not answer the question")
+	}
+}
+
+func TestMouseWheelScrollsLog(t *testing.T) {
+	events := bootScript()
+	for i := 0; i < 40; i++ {
+		events = append(events, domain.Event{Type: domain.EventLog, Payload: domain.LogPayload{Message: "line"}})
+	}
+	m := mouseModel(100, 30, nil, events...)
+
+	z := m.layout.logZone()
+	m.Update(tea.MouseMsg{X: z.x + 5, Y: z.y + 2, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelUp})
+	if m.followLogs || m.logVP.AtBottom() {
+		t.Fatalf("wheel up kept following the log (followLogs = %v)", m.followLogs)
+	}
+	m.Update(tea.MouseMsg{X: z.x + 5, Y: z.y + 2, Action: tea.MouseActionPress, Button: tea.MouseButtonWheelDown})
+	if !m.followLogs {
+		t.Fatalf("wheel back to the bottom did not resume following")
+	}
+}
+
+func TestMouseExtrasSelection(t *testing.T) {
+	actions := make(chan domain.Action, 1)
+	m := mouseModel(100, 30, actions, domain.Event{Type: domain.EventExtras, StepID: "extras", Payload: domain.ExtrasState{
+		Active: true,
+		Stage:  domain.ExtrasStageSelect,
+		Packages: []domain.ExtrasPackage{
+			{ID: "managed:sTask", Name: "sTask", Source: "managed", Version: "1.2.0"},
+			{ID: "managed:sSeo", Name: "sSeo", Source: "managed", Version: "1.0.4"},
+		},
+	}})
+	m.state.Release.Loading = false
+	m.systemStatusLoading = false
+
+	_, y := locate(t, m, "sSeo @")
+	x, _ := locate(t, m, "[ ]")
+	click(m, x+1, y)
+	if !m.extras.selected["managed:sSeo"] {
+		t.Fatalf("clicking the checkbox did not select sSeo: %v", m.extras.selected)
+	}
+
+	x, y = locate(t, m, "[ Install selected (1) ]")
+	click(m, x+3, y)
+	select {
+	case a := <-actions:
+		if a.OptionID != uiExtrasInstall || len(a.Values) != 1 || a.Values[0] != "managed:sSeo" {
+			t.Fatalf("action = %+v, want install of managed:sSeo", a)
+		}
+	default:
+		t.Fatalf("clicking Install selected sent no action")
+	}
+}
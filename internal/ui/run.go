@@ -47,8 +47,14 @@ func RunWithCancel(ctx context.Context, mode Mode, events <-chan domain.Event, a
 	// Ensure Lip Gloss and other Charm components detect color capabilities based on the
 	// actual terminal output we're writing to (which might be /dev/tty).
 	configureTerminalOutput(out)
+	useTheme(meta.Theme)
 
 	m := NewModel(ctx, mode, events, actions, meta, cancel, logger)
+	m.clipboard = out
+	if meta.TermProgress {
+		m.termStatus = NewTerminalStatus(out)
+		defer m.termStatus.Close()
+	}
 
 	// Seed a sensible initial size so the UI can render even if WindowSizeMsg never arrives.
 	if w, h, ok := detectTerminalSize(in, out, tty); ok {
@@ -66,6 +72,11 @@ func RunWithCancel(ctx context.Context, mode Mode, events <-chan domain.Event, a
 	if os.Getenv("EVO_NO_ALT_SCREEN") == "" && os.Getenv("NO_ALT_SCREEN") == "" {
 		progOpts = append(progOpts, tea.WithAltScreen())
 	}
+	// Mouse tracking takes over the terminal's own text selection (most
+	// terminals still select with Shift held); EVO_NO_MOUSE turns it off.
+	if os.Getenv("EVO_NO_MOUSE") == "" {
+		progOpts = append(progOpts, tea.WithMouseCellMotion())
+	}
 	p := tea.NewProgram(m, progOpts...)
 	finalModel, err := p.Run()
 	res := RunResult{}
@@ -0,0 +1,37 @@
+package ui
+
+// searchKeyResult tells a search input's owner what a key press did.
+type searchKeyResult int
+
+const (
+	// searchEdited means the query changed (or the key was ignored).
+	searchEdited searchKeyResult = iota
+	// searchCancelled is Esc: the input closes and the query stays.
+	searchCancelled
+	// searchSubmitted is Enter: the input closes and the query stays.
+	searchSubmitted
+)
+
+// editSearchQuery applies a key press to a "/" search input shared by the log
+// view and the Extras list: Ctrl+U clears, Backspace deletes a rune and
+// printable keys are appended.
+func editSearchQuery(query string, key string, lowerKey string) (string, searchKeyResult) {
+	switch lowerKey {
+	case "esc":
+		return query, searchCancelled
+	case "enter":
+		return query, searchSubmitted
+	case "ctrl+u":
+		return "", searchEdited
+	case "backspace", "ctrl+h":
+		rs := []rune(query)
+		if len(rs) > 0 {
+			query = string(rs[:len(rs)-1])
+		}
+		return query, searchEdited
+	}
+	if len([]rune(key)) == 1 && key != "/" {
+		query += key
+	}
+	return query, searchEdited
+}
@@ -11,18 +11,28 @@ var (
 	panelBorder     = lipgloss.RoundedBorder()
 	panelStyle      = lipgloss.NewStyle().Border(panelBorder)
 	panelTitleStyle = lipgloss.NewStyle().Bold(true)
+)
+
+// Palette styles are set by applyTheme; the dark theme is the default.
+var (
+	activeStyle lipgloss.Style
+	okStyle     lipgloss.Style
+	warnStyle   lipgloss.Style
+	errStyle    lipgloss.Style
+	mutedStyle  lipgloss.Style
 
-	activeStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(brightBlue))
-	okStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
-	warnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color(brightYellow))
-	errStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color(brightRed))
-	mutedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	logoStyle    lipgloss.Style
+	versionStyle lipgloss.Style
+	taglineStyle lipgloss.Style
 
-	logoStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color(progressFillHex))
-	versionStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(brightBlue))
-	taglineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(brightYellow))
+	questionStyle     lipgloss.Style
+	defaultInputStyle lipgloss.Style
+	inputStyle        lipgloss.Style
 
-	questionStyle     = versionStyle
-	defaultInputStyle = mutedStyle
-	inputStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("7"))
+	logMatchStyle        lipgloss.Style
+	logCurrentMatchStyle lipgloss.Style
 )
+
+func init() {
+	applyTheme(themes[ThemeDark])
+}
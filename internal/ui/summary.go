@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
+)
+
+type summaryUIState struct {
+	// active is set once the engine reports a finished installation.
+	active bool
+	// hidden switches back to the main view (Tab toggles).
+	hidden bool
+
+	data     domain.InstallSummary
+	logPaths []string
+	scroll   int
+	notice   string
+}
+
+func (m *Model) applySummary(s domain.InstallSummary) {
+	s.Details = i18n.Details(s.Details)
+	steps := make([]domain.LogPayload, len(s.NextSteps))
+	for i, p := range s.NextSteps {
+		p.Message = i18n.Log(p)
+		steps[i] = p
+	}
+	s.NextSteps = steps
+
+	m.summary = summaryUIState{active: true, data: s}
+	if m.logger != nil {
+		m.summary.logPaths = m.logger.PlannedPaths()
+	}
+	m.state.Question.Active = false
+	m.logView.searchActive = false
+}
+
+// summaryVisible reports whether the summary screen replaces the main view.
+func (m *Model) summaryVisible() bool {
+	return m.summary.active && !m.summary.hidden && !m.extras.active
+}
+
+func (m *Model) handleSummaryKey(lowerKey string) {
+	switch lowerKey {
+	case "c", "с":
+		m.copyCredentials()
+	case "tab":
+		m.summary.hidden = true
+	case "up":
+		m.summary.scroll = max(0, m.summary.scroll-1)
+	case "down":
+		m.summary.scroll++
+	case "enter", "esc", "q", "й":
+		m.quitRequested = true
+	}
+}
+
+// copyCredentials puts the manager path and admin credentials on the
+// clipboard with an OSC 52 sequence, which works over SSH as long as the
+// terminal supports it.
+func (m *Model) copyCredentials() {
+	text := m.summary.data.Credentials()
+	if text == "" || m.clipboard == nil {
+		return
+	}
+	if err := writeClipboard(m.clipboard, text, os.Getenv); err != nil {
+		m.summary.notice = err.Error()
+		return
+	}
+	m.summary.notice = i18n.T("summary.copied", nil)
+}
+
+func writeClipboard(out io.Writer, text string, getenv func(string) string) error {
+	seq := osc52.New(text)
+	switch {
+	case getenv("TMUX") != "":
+		seq = seq.Tmux()
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		seq = seq.Screen()
+	}
+	_, err := seq.WriteTo(out)
+	return err
+}
+
+func (m *Model) renderSummary(width int, height int) string {
+	innerH := max(0, height-2)
+	if innerH < 6 {
+		return minSizeView(width, height)
+	}
+	contentW := panelContentWidth(width)
+	s := m.summary.data
+
+	details := append([]domain.QuestionDetail(nil), s.Details...)
+	if len(m.summary.logPaths) > 0 {
+		details = append(details, domain.QuestionDetail{
+			Label: i18n.T("field.log", nil),
+			Value: strings.Join(m.summary.logPaths, ", "),
+		})
+	}
+	labelW := 0
+	for _, d := range details {
+		labelW = max(labelW, runewidth.StringWidth(d.Label))
+	}
+
+	lines := []string{}
+	for _, d := range details {
+		pad := strings.Repeat(" ", labelW-runewidth.StringWidth(d.Label))
+		lines = append(lines, mutedStyle.Render(d.Label+":"+pad)+" "+d.Value)
+	}
+
+	if len(s.Extras) > 0 {
+		lines = append(lines, "", questionStyle.Render(i18n.T("summary.extras", nil)))
+		for _, r := range s.Extras {
+			icon, style := extrasStatusMarker(r.Status)
+			label := r.Name
+			if r.Status == domain.ExtrasStatusError && r.Message != "" {
+				label += " - " + r.Message
+			}
+			lines = append(lines, style.Render(icon)+" "+label)
+		}
+	}
+
+	if len(s.NextSteps) > 0 {
+		lines = append(lines, "", questionStyle.Render(i18n.T("summary.next_steps", nil)))
+		wrap := lipgloss.NewStyle().Width(max(1, contentW-3))
+		for i, step := range s.NextSteps {
+			for j, line := range splitLines(wrap.Render(step.Message)) {
+				prefix := "   "
+				if j == 0 {
+					prefix = fmt.Sprintf("%d. ", i+1)
+				}
+				lines = append(lines, prefix+strings.TrimRight(line, " "))
+			}
+		}
+	}
+
+	// Keep the title and notice pinned; scroll the rest.
+	title := okStyle.Copy().Bold(true).Render(i18n.T("summary.title", nil))
+	bodyH := innerH - 2
+	if m.summary.notice != "" {
+		bodyH -= 2
+	}
+	bodyH = max(1, bodyH)
+	m.summary.scroll = min(m.summary.scroll, max(0, len(lines)-bodyH))
+	visible := lines[m.summary.scroll:]
+	if len(visible) > bodyH {
+		visible = visible[:bodyH]
+	}
+
+	out := append([]string{title, ""}, visible...)
+	if m.summary.notice != "" {
+		for len(out) < innerH-1 {
+			out = append(out, "")
+		}
+		out = append(out, okStyle.Render(m.summary.notice))
+	}
+	return panel(i18n.T("summary.panel", nil), strings.Join(out, "\n"), width, height)
+}
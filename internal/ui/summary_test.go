@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestSummaryScreenCopiesCredentialsAndQuits(t *testing.T) {
+	var clipboard bytes.Buffer
+	m := &Model{width: 100, height: 30, clipboard: &clipboard}
+	m.applyEvent(domain.Event{
+		Type: domain.EventSummary,
+		Payload: domain.SummaryPayload{Summary: domain.InstallSummary{
+			ProjectPath:   "/var/www/evo",
+			ManagerPath:   "/manager/",
+			AdminUsername: "admin",
+			AdminPassword: "admin-secret",
+			Details: []domain.QuestionDetail{
+				{Label: "Manager URL", Value: "/manager/", Key: "field.manager_url"},
+			},
+			NextSteps: []domain.LogPayload{{Message: "Point the web server document root at /var/www/evo."}},
+		}},
+	})
+
+	view := m.View()
+	if !strings.Contains(view, "/manager/") || !strings.Contains(view, "document root") {
+		t.Fatalf("summary view is missing rows:\n%s", view)
+	}
+	if strings.Contains(view, "admin-secret") {
+		t.Fatal("summary view shows the admin password")
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	want := base64.StdEncoding.EncodeToString([]byte("Manager: /manager/\nUsername: admin\nPassword: admin-secret"))
+	if got := clipboard.String(); !strings.Contains(got, "]52;c;") || !strings.Contains(got, want) {
+		t.Fatalf("clipboard sequence = %q", got)
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if m.summaryVisible() {
+		t.Fatal("Tab should switch to the main view")
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyTab})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !m.quitRequested || cmd == nil {
+		t.Fatal("Enter on the summary should quit")
+	}
+}
+
+func TestWriteClipboardWrapsForTmux(t *testing.T) {
+	t.Parallel()
+
+	var out bytes.Buffer
+	env := map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"}
+	if err := writeClipboard(&out, "x", func(k string) string { return env[k] }); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "\x1bPtmux;") {
+		t.Fatalf("sequence = %q, want a tmux passthrough", out.String())
+	}
+}
@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
+)
+
+// attentionAfter is how long the installer must have run unattended before a
+// question rings the bell; back-to-back questions don't.
+const attentionAfter = 15 * time.Second
+
+// OSC 9;4 progress states (ConEmu, Windows Terminal, WezTerm, Ghostty).
+const (
+	termProgressClear  = 0
+	termProgressNormal = 1
+	termProgressError  = 2
+	termProgressPaused = 4
+)
+
+// TerminalStatus mirrors the installer state outside the window: OSC 9;4
+// tab/taskbar progress, the window title ("evo: Step 5/7 47%"), and a bell
+// plus an OSC 777 notification when the install finishes, fails or needs
+// input. A nil *TerminalStatus does nothing.
+type TerminalStatus struct {
+	out io.Writer
+
+	stepID    string
+	stepIndex int
+	stepTotal int
+	progress  domain.ProgressState
+
+	waiting  bool
+	failed   bool
+	finished bool
+	// lastInput is when the user last had to answer something.
+	lastInput time.Time
+
+	lastTitle string
+	lastState string
+}
+
+// NewTerminalStatus returns a reporter writing to out. It pushes the current
+// window title so Close can restore it.
+func NewTerminalStatus(out io.Writer) *TerminalStatus {
+	s := &TerminalStatus{out: out, lastInput: time.Now()}
+	s.write("\x1b[22;0t")
+	return s
+}
+
+// Observe updates progress, title and notifications from an engine event.
+func (s *TerminalStatus) Observe(ev domain.Event) {
+	if s == nil || s.finished {
+		return
+	}
+	ts := ev.TS
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	switch ev.Type {
+	case domain.EventStepStart:
+		p, ok := ev.Payload.(domain.StepStartPayload)
+		if !ok || p.Total <= 0 {
+			return
+		}
+		s.stepID, s.stepIndex, s.stepTotal = ev.StepID, p.Index, p.Total
+		s.progress = domain.ProgressState{}
+		s.waiting = false
+	case domain.EventProgress:
+		p, ok := ev.Payload.(domain.ProgressPayload)
+		if !ok || ev.StepID != s.stepID {
+			return
+		}
+		s.progress = domain.ProgressState{StepID: ev.StepID, Current: p.Current, Total: p.Total, Unit: p.Unit, Visible: true}
+		s.waiting = false
+	case domain.EventStepDone:
+		if p, ok := ev.Payload.(domain.StepDonePayload); ok && !p.OK && s.stepTotal > 0 && !s.failed {
+			s.failed = true
+			s.notify(i18n.T("notify.failed", nil))
+		}
+	case domain.EventLog:
+		if _, ok := ev.Payload.(domain.QuestionPayload); ok {
+			s.askInput(ts)
+		} else {
+			s.waiting = false
+		}
+	case domain.EventExtras:
+		if p, ok := ev.Payload.(domain.ExtrasState); ok && p.Stage == domain.ExtrasStageSelect {
+			s.askInput(ts)
+		} else {
+			s.waiting = false
+		}
+	case domain.EventSummary:
+		s.finished = true
+		s.setTitle(i18n.T("term.done", nil))
+		s.setProgress(termProgressClear, 0)
+		s.notify(i18n.T("notify.done", nil))
+		return
+	default:
+		return
+	}
+	s.render()
+}
+
+// Close clears the progress indicator and restores the window title.
+func (s *TerminalStatus) Close() {
+	if s == nil {
+		return
+	}
+	s.setProgress(termProgressClear, 0)
+	s.write("\x1b[23;0t")
+}
+
+func (s *TerminalStatus) askInput(ts time.Time) {
+	if !s.waiting && ts.Sub(s.lastInput) >= attentionAfter {
+		s.notify(i18n.T("notify.input", nil))
+	}
+	s.waiting = true
+	s.lastInput = ts
+}
+
+func (s *TerminalStatus) render() {
+	if s.stepTotal <= 0 {
+		return
+	}
+	title := i18n.T("term.step", map[string]string{
+		"index": fmt.Sprint(s.stepIndex),
+		"total": fmt.Sprint(s.stepTotal),
+	})
+	stepPct := -1
+	if s.progress.Visible && s.progress.Total > 0 {
+		stepPct = int(min(s.progress.Current, s.progress.Total) * 100 / s.progress.Total)
+		title += fmt.Sprintf(" %d%%", stepPct)
+	}
+	if s.waiting {
+		title = i18n.T("term.input", nil)
+	}
+	s.setTitle(title)
+
+	// The tab progress covers the whole run: finished steps plus the
+	// reported progress of the current one.
+	done := float64(max(0, s.stepIndex-1))
+	if stepPct >= 0 {
+		done += float64(stepPct) / 100
+	}
+	overall := int(done * 100 / float64(s.stepTotal))
+	switch {
+	case s.failed:
+		s.setProgress(termProgressError, overall)
+	case s.waiting:
+		s.setProgress(termProgressPaused, overall)
+	default:
+		s.setProgress(termProgressNormal, overall)
+	}
+}
+
+func (s *TerminalStatus) setTitle(title string) {
+	if title == s.lastTitle {
+		return
+	}
+	s.lastTitle = title
+	s.write("\x1b]2;" + sanitizeOSC(title) + "\a")
+}
+
+func (s *TerminalStatus) setProgress(state int, pct int) {
+	seq := fmt.Sprintf("\x1b]9;4;%d;%d\a", state, min(100, max(0, pct)))
+	if seq == s.lastState {
+		return
+	}
+	s.lastState = seq
+	s.write(seq)
+}
+
+// notify rings the bell and sends an OSC 777 desktop notification; terminals
+// without OSC 777 support ignore it.
+func (s *TerminalStatus) notify(body string) {
+	s.write("\a\x1b]777;notify;Evolution CMS Installer;" + sanitizeOSC(body) + "\a")
+}
+
+func (s *TerminalStatus) write(seq string) {
+	if s.out != nil {
+		_, _ = io.WriteString(s.out, seq)
+	}
+}
+
+// sanitizeOSC drops control characters and the ';' separator, which would end
+// or split the sequence.
+func sanitizeOSC(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == ';' {
+			return -1
+		}
+		return r
+	}, text)
+}
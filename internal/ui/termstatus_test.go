@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestTerminalStatusReportsProgressAndNotifies(t *testing.T) {
+	var out strings.Builder
+	s := NewTerminalStatus(&out)
+	start := s.lastInput
+
+	s.Observe(domain.Event{Type: domain.EventStepStart, StepID: "install", TS: start.Add(time.Minute), Payload: domain.StepStartPayload{Index: 5, Total: 7}})
+	s.Observe(domain.Event{Type: domain.EventProgress, StepID: "install", TS: start.Add(time.Minute), Payload: domain.ProgressPayload{Current: 47, Total: 100}})
+	got := out.String()
+	if !strings.Contains(got, "\x1b]2;evo: Step 5/7 47%\a") {
+		t.Fatalf("output %q has no window title", got)
+	}
+	if !strings.Contains(got, "\x1b]9;4;1;63\a") {
+		t.Fatalf("output %q has no OSC 9;4 progress", got)
+	}
+
+	out.Reset()
+	s.Observe(domain.Event{Type: domain.EventLog, StepID: "install", TS: start.Add(2 * time.Minute), Payload: domain.QuestionPayload{}})
+	got = out.String()
+	if !strings.Contains(got, "\a\x1b]777;notify;") || !strings.Contains(got, "\x1b]9;4;4;") {
+		t.Fatalf("question output = %q, want a notification and paused progress", got)
+	}
+
+	out.Reset()
+	s.Observe(domain.Event{Type: domain.EventLog, StepID: "install", TS: start.Add(2*time.Minute + time.Second), Payload: domain.QuestionPayload{}})
+	if strings.Contains(out.String(), "777;notify") {
+		t.Fatalf("back-to-back question notified again: %q", out.String())
+	}
+
+	out.Reset()
+	s.Observe(domain.Event{Type: domain.EventSummary, Payload: domain.SummaryPayload{}})
+	s.Close()
+	got = out.String()
+	if !strings.Contains(got, "777;notify;Evolution CMS Installer;Evolution CMS is installed.") {
+		t.Fatalf("summary output = %q, want a finish notification", got)
+	}
+	if !strings.HasSuffix(got, "\x1b[23;0t") {
+		t.Fatalf("Close output = %q, want the window title restored", got)
+	}
+}
@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Theme names accepted by --theme, EVO_THEME and the config file.
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// theme is a TUI palette. An empty color leaves the terminal default.
+type theme struct {
+	name string
+
+	accent   string
+	ok       string
+	warn     string
+	err      string
+	muted    string
+	brand    string
+	tagline  string
+	input    string
+	progress string
+
+	matchFG   string
+	matchBG   string
+	currentBG string
+
+	// bold emphasizes status colors (high contrast).
+	bold bool
+	// plain themes carry no color at all: emphasis uses bold, faint and
+	// reverse video, and status markers add distinct icons and text.
+	plain bool
+}
+
+var themes = map[string]theme{
+	ThemeDark: {
+		name:      ThemeDark,
+		accent:    brightBlue,
+		ok:        "10",
+		warn:      brightYellow,
+		err:       brightRed,
+		muted:     "8",
+		brand:     progressFillHex,
+		tagline:   brightYellow,
+		input:     "7",
+		progress:  progressFillHex,
+		matchFG:   "0",
+		matchBG:   brightYellow,
+		currentBG: progressFillHex,
+	},
+	ThemeLight: {
+		name:      ThemeLight,
+		accent:    "#1d4ed8",
+		ok:        "#15803d",
+		warn:      "#b45309",
+		err:       "#b91c1c",
+		muted:     "#6b7280",
+		brand:     "#047857",
+		tagline:   "#b45309",
+		input:     "#111827",
+		progress:  "#059669",
+		matchFG:   "#111827",
+		matchBG:   "#fde68a",
+		currentBG: "#6ee7b7",
+	},
+	ThemeHighContrast: {
+		name:      ThemeHighContrast,
+		accent:    "14",
+		ok:        "10",
+		warn:      "11",
+		err:       "9",
+		muted:     "7",
+		brand:     "10",
+		tagline:   "11",
+		input:     "15",
+		progress:  "10",
+		matchFG:   "0",
+		matchBG:   "11",
+		currentBG: "14",
+		bold:      true,
+	},
+	ThemeMonochrome: {
+		name:  ThemeMonochrome,
+		plain: true,
+	},
+}
+
+var activeTheme theme
+
+// ParseTheme validates a theme name; "" means auto.
+func ParseTheme(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || name == ThemeAuto {
+		return ThemeAuto, nil
+	}
+	if _, ok := themes[name]; ok {
+		return name, nil
+	}
+	return "", fmt.Errorf("unknown theme %q (use auto, dark, light, high-contrast or monochrome)", name)
+}
+
+// useTheme applies the named theme once the terminal output is configured;
+// auto picks dark or light from the detected terminal background.
+func useTheme(name string) {
+	if name == "" || name == ThemeAuto {
+		name = ThemeDark
+		if !lipgloss.HasDarkBackground() {
+			name = ThemeLight
+		}
+	}
+	t, ok := themes[name]
+	if !ok {
+		t = themes[ThemeDark]
+	}
+	applyTheme(t)
+	if t.plain {
+		lipgloss.SetColorProfile(termenv.Ascii)
+	}
+}
+
+func applyTheme(t theme) {
+	activeTheme = t
+	fg := func(c string) lipgloss.Style {
+		s := lipgloss.NewStyle()
+		if c != "" {
+			s = s.Foreground(lipgloss.Color(c))
+		}
+		return s
+	}
+
+	activeStyle = fg(t.accent)
+	okStyle = fg(t.ok)
+	warnStyle = fg(t.warn)
+	errStyle = fg(t.err)
+	mutedStyle = fg(t.muted)
+
+	logoStyle = fg(t.brand)
+	versionStyle = fg(t.accent)
+	taglineStyle = fg(t.tagline)
+
+	inputStyle = fg(t.input)
+	logMatchStyle = fg(t.matchFG).Background(lipgloss.Color(t.matchBG))
+	logCurrentMatchStyle = fg(t.matchFG).Background(lipgloss.Color(t.currentBG)).Bold(true)
+
+	if t.bold {
+		activeStyle = activeStyle.Bold(true)
+		warnStyle = warnStyle.Bold(true)
+		errStyle = errStyle.Bold(true)
+	}
+	if t.plain {
+		activeStyle = activeStyle.Bold(true)
+		errStyle = errStyle.Bold(true)
+		mutedStyle = mutedStyle.Faint(true)
+		versionStyle = versionStyle.Bold(true)
+		logMatchStyle = lipgloss.NewStyle().Reverse(true)
+		logCurrentMatchStyle = lipgloss.NewStyle().Reverse(true).Bold(true).Underline(true)
+	}
+
+	questionStyle = versionStyle
+	defaultInputStyle = mutedStyle
+}
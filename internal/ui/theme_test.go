@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/evolution-cms/installer/internal/domain"
+)
+
+func TestMonochromeThemeKeepsStatusReadableWithoutColor(t *testing.T) {
+	applyTheme(themes[ThemeMonochrome])
+	defer applyTheme(themes[ThemeDark])
+
+	icons := map[string]bool{}
+	for _, level := range []domain.StatusLevel{domain.StatusOK, domain.StatusWarn, domain.StatusError} {
+		icon, _ := statusIndicator(level)
+		icons[icon] = true
+	}
+	if len(icons) != 3 {
+		t.Fatalf("status icons = %v, want three distinct icons", icons)
+	}
+	if done, _, _ := stepMarker(domain.StepDone); done == "⚠" {
+		t.Fatal("done step must not use the warning marker")
+	}
+	if warn, _, _ := stepMarker(domain.StepWarn); warn != "⚠" {
+		t.Fatalf("warning step marker = %q, want ⚠", warn)
+	}
+
+	m := &Model{}
+	m.state.SystemStatus.Items = []domain.StatusItem{{Label: "pdo_mysql", Level: domain.StatusWarn}}
+	if got, want := m.renderSystem(40), "⚠ pdo_mysql (warning)"; got != want {
+		t.Fatalf("renderSystem() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
+)
+
+// stallAfter is how long an active step may stay silent before the Quest
+// track highlights it; long seeders make the installer look hung otherwise.
+const stallAfter = 30 * time.Second
+
+// noteOutput remembers when the engine last reported progress of any kind.
+func (m *Model) noteOutput(ev domain.Event) {
+	switch ev.Type {
+	case domain.EventStepStart, domain.EventStepDone, domain.EventLog, domain.EventProgress,
+		domain.EventWarning, domain.EventError, domain.EventTiming, domain.EventExtras:
+	default:
+		return
+	}
+	ts := ev.TS
+	if ts.IsZero() {
+		ts = m.clock()
+	}
+	if ts.After(m.lastOutput) {
+		m.lastOutput = ts
+	}
+}
+
+// waitingForUser reports whether the run is paused on a question or the
+// extras selection, which is not a stall.
+func (m *Model) waitingForUser() bool {
+	if m.state.Question.Active {
+		return true
+	}
+	return m.extras.active && m.extras.stage != domain.ExtrasStageProgress
+}
+
+// stepTimer returns the right-hand text of a Quest track row: the duration of
+// finished steps, or elapsed/estimate for the active one. stalled is set when
+// the active step has been silent for stallAfter.
+func (m *Model) stepTimer(s domain.StepState, now time.Time) (text string, stalled bool) {
+	t, ok := m.timings.Step(s.ID)
+	if !ok {
+		return "", false
+	}
+	if t.Duration() > 0 {
+		return domain.FormatDuration(t.Duration()), false
+	}
+	if s.Status != domain.StepActive || t.Started.IsZero() || m.engineDone {
+		return "", false
+	}
+
+	quiet := now.Sub(t.Started)
+	if !m.lastOutput.IsZero() && m.lastOutput.After(t.Started) {
+		quiet = now.Sub(m.lastOutput)
+	}
+	if quiet >= stallAfter && !m.waitingForUser() {
+		return i18n.T("ui.steps.stalled", map[string]string{"duration": formatElapsed(quiet)}), true
+	}
+
+	text = formatElapsed(now.Sub(t.Started))
+	if est, ok := m.meta.StepDurations.Estimate(s.ID, t.Variant); ok {
+		text += " / ~" + formatElapsed(est)
+	}
+	return text, false
+}
+
+// questTitle adds the total elapsed time and, when every remaining step has a
+// recorded duration, the ETA to the Quest track title.
+func (m *Model) questTitle(now time.Time) string {
+	title := i18n.T("ui.panel.quest", nil)
+	if m.timings.Total() <= 0 && !m.hasActiveStep() {
+		return title
+	}
+	elapsed := m.timings.Total()
+	if !m.engineDone && m.state.EndedAt == nil {
+		elapsed = now.Sub(m.state.StartedAt)
+	}
+	title += " · " + formatElapsed(elapsed)
+	if eta, ok := m.remainingEstimate(now); ok && !m.engineDone {
+		title += " · " + i18n.T("ui.steps.eta", map[string]string{"duration": formatElapsed(eta)})
+	}
+	return title
+}
+
+// remainingEstimate sums the expected time of the active and pending steps.
+// Pending steps use the variant of the latest started step.
+func (m *Model) remainingEstimate(now time.Time) (time.Duration, bool) {
+	hist := m.meta.StepDurations
+	if hist == nil {
+		return 0, false
+	}
+	variant := ""
+	for _, t := range m.timings.Steps {
+		if t.Variant != "" {
+			variant = t.Variant
+		}
+	}
+
+	var remaining time.Duration
+	pending := false
+	for _, s := range m.state.Steps {
+		switch s.Status {
+		case domain.StepDone, domain.StepWarn:
+			continue
+		case domain.StepError:
+			return 0, false
+		}
+		t, started := m.timings.Step(s.ID)
+		v := variant
+		if started && t.Variant != "" {
+			v = t.Variant
+		}
+		est, ok := hist.Estimate(s.ID, v)
+		if !ok {
+			return 0, false
+		}
+		if s.Status == domain.StepActive && started && !t.Started.IsZero() {
+			est -= now.Sub(t.Started)
+			if est < 0 {
+				est = 0
+			}
+		}
+		remaining += est
+		pending = true
+	}
+	return remaining, pending
+}
+
+// formatElapsed renders running timers in whole seconds so they don't
+// flicker: 7s, 4m02s, 1h05m.
+func formatElapsed(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d/time.Second))
+	}
+	return domain.FormatDuration(d)
+}
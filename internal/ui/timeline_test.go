@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/logging"
+)
+
+func TestQuestTrackShowsEstimatesAndStalls(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	m := &Model{
+		state: domain.AppState{
+			StartedAt: start,
+			Steps: []domain.StepState{
+				{ID: "download", Label: "Download", Status: domain.StepDone},
+				{ID: "install", Label: "Install", Status: domain.StepActive},
+				{ID: "finalize", Label: "Finalize", Status: domain.StepPending},
+			},
+		},
+		meta: Meta{StepDurations: &logging.StepDurations{Steps: map[string][]time.Duration{
+			"download":       {20 * time.Second},
+			"install/mysql":  {90 * time.Second},
+			"finalize/mysql": {10 * time.Second},
+		}}},
+	}
+	for _, ev := range []domain.Event{
+		{Type: domain.EventStepStart, StepID: "download", TS: start},
+		{Type: domain.EventStepDone, StepID: "download", TS: start.Add(20 * time.Second), Payload: domain.StepDonePayload{OK: true}},
+		{Type: domain.EventStepStart, StepID: "install", TS: start.Add(20 * time.Second), Payload: domain.StepStartPayload{Variant: "mysql"}},
+	} {
+		m.timings.Observe(ev)
+		m.noteOutput(ev)
+	}
+
+	now := start.Add(40 * time.Second)
+	if got, _ := m.stepTimer(m.state.Steps[1], now); got != "20s / ~1m30s" {
+		t.Fatalf("stepTimer(install) = %q, want %q", got, "20s / ~1m30s")
+	}
+	if got := m.questTitle(now); got != "Quest track · 40s · ETA ~1m20s" {
+		t.Fatalf("questTitle() = %q", got)
+	}
+
+	now = start.Add(65 * time.Second)
+	got, stalled := m.stepTimer(m.state.Steps[1], now)
+	if !stalled || got != "no output for 45s" {
+		t.Fatalf("stepTimer(install) = %q, %v, want a stall after 45s of silence", got, stalled)
+	}
+
+	m.state.Question.Active = true
+	if _, stalled := m.stepTimer(m.state.Steps[1], now); stalled {
+		t.Fatal("a step waiting for an answer must not be reported as stalled")
+	}
+
+	m.meta.StepDurations = nil
+	if got := m.questTitle(now); strings.Contains(got, "ETA") {
+		t.Fatalf("questTitle() = %q, want no ETA without history", got)
+	}
+}
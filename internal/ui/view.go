@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
-	"time"
 	"unicode/utf8"
 
 	"github.com/charmbracelet/lipgloss"
@@ -13,6 +12,7 @@ import (
 	reflowtruncate "github.com/muesli/reflow/truncate"
 
 	"github.com/evolution-cms/installer/internal/domain"
+	"github.com/evolution-cms/installer/internal/i18n"
 )
 
 func (m *Model) View() string {
@@ -30,21 +30,23 @@ func (m *Model) View() string {
 
 	switch {
 	case m.cancelling && m.engineDone:
-		body = lipgloss.Place(m.width, usableH, lipgloss.Center, lipgloss.Center, "Installation cancelled.")
+		body = lipgloss.Place(m.width, usableH, lipgloss.Center, lipgloss.Center, i18n.T("ui.cancelled", nil))
 	case m.state.Release.Loading || m.systemStatusLoading:
 		if m.cancelling {
-			line1 := m.spin.View() + " Cancelling…"
-			line2 := mutedStyle.Render("Stopping installer…")
+			line1 := m.spin.View() + " " + i18n.T("ui.cancelling", nil)
+			line2 := mutedStyle.Render(i18n.T("ui.stopping", nil))
 			body = lipgloss.Place(m.width, usableH, lipgloss.Center, lipgloss.Center, line1+"\n"+line2)
 			break
 		}
-		msg := "Fetching latest version…"
+		msg := i18n.T("ui.loading.release", nil)
 		if !m.state.Release.Loading && m.systemStatusLoading {
-			msg = "Checking system status…"
+			msg = i18n.T("ui.loading.system", nil)
 		}
 		line1 := m.spin.View() + " " + msg
-		line2 := mutedStyle.Render("Starting installer…")
+		line2 := mutedStyle.Render(i18n.T("ui.loading.starting", nil))
 		body = lipgloss.Place(m.width, usableH, lipgloss.Center, lipgloss.Center, line1+"\n"+line2)
+	case m.summaryVisible():
+		body = m.renderSummary(m.width, usableH)
 	case m.extras.active:
 		body = m.renderExtrasView(m.width, usableH)
 	default:
@@ -57,11 +59,11 @@ func (m *Model) View() string {
 		}
 
 		header := m.renderHeader(m.layout.leftW, m.layout.showLogo)
-		quest := panel("Quest track", m.questVP.View(), m.layout.leftW, m.layout.questH)
+		quest := panel(m.questTitle(m.clock()), m.questVP.View(), m.layout.leftW, m.layout.questH)
 
 		leftTop := lipgloss.JoinVertical(lipgloss.Top, header, quest)
 
-		status := panel("System status", m.statusVP.View(), m.layout.rightW, m.layout.topAreaH)
+		status := panel(i18n.T("ui.panel.status", nil), m.statusVP.View(), m.layout.rightW, m.layout.topAreaH)
 
 		top := lipgloss.JoinHorizontal(lipgloss.Top,
 			leftTop,
@@ -69,7 +71,7 @@ func (m *Model) View() string {
 			status,
 		)
 
-		logs := panel("Log", m.renderLogPanelBody(panelContentWidth(m.layout.width), panelBodyHeight(m.layout.logH, true)), m.layout.width, m.layout.logH)
+		logs := panel(m.logPanelTitle(), m.renderLogPanelBody(panelContentWidth(m.layout.width), panelBodyHeight(m.layout.logH, true)), m.layout.width, m.layout.logH)
 
 		body = lipgloss.JoinVertical(lipgloss.Top, top, logs)
 	}
@@ -85,26 +87,41 @@ func (m *Model) View() string {
 }
 
 func keyHintsLine() string {
-	return "↑/↓ Navigate/Scroll  PgUp/PgDn Scroll  End Follow  Enter Select  ctrl+c Cancel  ctrl+q Quit"
+	return i18n.T("ui.hints.question", nil)
+}
+
+func logHintsLine() string {
+	return i18n.T("ui.hints.log", nil)
 }
 
 func (m *Model) footerHintText() string {
 	if !m.extras.active {
-		return keyHintsLine()
+		switch {
+		case m.summaryVisible():
+			return i18n.T("ui.hints.summary", nil)
+		case m.state.Question.Active:
+			return keyHintsLine()
+		case m.logView.searchActive:
+			return i18n.T("ui.hints.search", nil)
+		case m.summary.active:
+			return i18n.T("ui.hints.summary_back", nil) + "  " + logHintsLine()
+		default:
+			return logHintsLine()
+		}
 	}
 	switch m.extras.stage {
 	case domain.ExtrasStageSelect:
 		if m.extras.versionPickerActive {
-			return "↑/↓ Move  Enter Select  Esc Close  ctrl+q Quit"
+			return i18n.T("ui.hints.extras_version", nil)
 		}
 		if m.extras.searchActive {
-			return "Type Search  Enter Apply  Esc Close  Ctrl+U Clear  ctrl+c Cancel  ctrl+q Quit"
+			return i18n.T("ui.hints.search", nil)
 		}
-		return "↑/↓ Move  Space Toggle  / Search  L Legacy  Tab Actions  Enter Select  ctrl+c Cancel  ctrl+q Quit"
+		return i18n.T("ui.hints.extras_select", nil)
 	case domain.ExtrasStageProgress:
-		return "Installing extras...  ctrl+c Cancel  ctrl+q Quit"
+		return i18n.T("ui.hints.extras_progress", nil)
 	case domain.ExtrasStageSummary:
-		return "Enter Close  Esc/Q Close  ctrl+q Quit"
+		return i18n.T("ui.hints.extras_summary", nil)
 	default:
 		return keyHintsLine()
 	}
@@ -385,12 +402,28 @@ func formatVersion(v string) string {
 }
 
 func (m *Model) renderSteps(width int) string {
+	now := m.clock()
 	lines := make([]string, 0, len(m.state.Steps))
 	for _, s := range m.state.Steps {
 		icon, iconStyle, labelStyle := stepMarker(s.Status)
+		took, stalled := m.stepTimer(s, now)
+		tookStyle := mutedStyle
+		if stalled {
+			iconStyle, labelStyle, tookStyle = warnStyle, warnStyle, warnStyle
+		}
 		avail := max(0, width-2)
+		tookW := lipgloss.Width(took)
+		if took != "" && avail > tookW+8 {
+			avail -= tookW + 1
+		} else {
+			took = ""
+		}
 		label := truncatePlain(s.Label, avail)
 		line := iconStyle.Render(icon) + " " + labelStyle.Render(label)
+		if took != "" {
+			pad := max(1, avail-lipgloss.Width(label)+1)
+			line += strings.Repeat(" ", pad) + tookStyle.Render(took)
+		}
 		lines = append(lines, truncateANSI(line, width))
 	}
 	if len(lines) == 0 {
@@ -407,6 +440,9 @@ func (m *Model) renderSystem(width int) string {
 	for _, it := range m.state.SystemStatus.Items {
 		ind, indStyle := statusIndicator(it.Level)
 		label := it.Label
+		if activeTheme.plain {
+			label += statusLevelText(it.Level)
+		}
 		avail := max(0, width-2)
 		line := indStyle.Render(ind) + " " + truncatePlain(label, avail)
 		lines = append(lines, truncateANSI(line, width))
@@ -415,7 +451,16 @@ func (m *Model) renderSystem(width int) string {
 }
 
 func (m *Model) renderLogStream(width int) string {
-	entries := m.state.Logs.Entries
+	entries := m.visibleLogEntries()
+	matches := m.logMatches()
+	matchAt := make(map[int]bool, len(matches))
+	for _, i := range matches {
+		matchAt[i] = true
+	}
+	current := -1
+	if len(matches) > 0 {
+		current = matches[min(m.logView.match, len(matches)-1)]
+	}
 	lines := make([]string, 0, len(entries))
 
 	activeStepID := ""
@@ -445,7 +490,7 @@ func (m *Model) renderLogStream(width int) string {
 	for i, e := range entries {
 		ts := e.TS
 		if ts.IsZero() {
-			ts = time.Now()
+			ts = m.clock()
 		}
 
 		prefix, pStyle := logPrefix(e.Level)
@@ -460,12 +505,20 @@ func (m *Model) renderLogStream(width int) string {
 
 		timeStr := ts.Format("15:04:05")
 		avail := max(0, width-lipgloss.Width(timeStr)-1-2) // "time␠<icon>␠"
-		msg := truncateANSI(m.renderLogMessage(e, avail), avail)
+		var msg string
+		if matchAt[i] {
+			msg = truncateANSI(highlightLogMatches(truncatePlain(e.Message, avail), m.logView.searchQuery, i == current), avail)
+		} else {
+			msg = truncateANSI(m.renderLogMessage(e, avail), avail)
+		}
 		line := timeStr + " " + pStyle.Render(prefix) + " " + msg
 		lines = append(lines, truncateANSI(line, width))
 	}
 
 	if len(lines) == 0 {
+		if m.logView.filtered() && len(m.state.Logs.Entries) > 0 {
+			return truncatePlain("(no log lines match the filters; Esc clears them)", width)
+		}
 		return truncatePlain("(no logs yet)", width)
 	}
 	return strings.Join(lines, "\n")
@@ -809,7 +862,14 @@ func (m *Model) renderQuestionLines(width int, height int) []string {
 	}
 
 	prompt := truncatePlain("? "+m.state.Question.Prompt, width)
-	out = append(out, questionStyle.Render(prompt))
+	promptLine := questionStyle.Render(prompt)
+	if m.state.Question.CanGoBack {
+		const hint = "  Esc: back"
+		if lipgloss.Width(prompt)+len(hint) <= width {
+			promptLine += mutedStyle.Render(hint)
+		}
+	}
+	out = append(out, promptLine)
 	if len(out) >= height {
 		return out[:height]
 	}
@@ -832,41 +892,44 @@ func (m *Model) renderQuestionLines(width int, height int) []string {
 		}
 		inputLine := "> " + v
 		out = append(out, truncateANSI(inputLine, width))
+		if msg := m.state.Question.Error; msg != "" && len(out) < height {
+			out = append(out, errStyle.Render(truncatePlain("✗ "+msg, width)))
+		}
 		for len(out) < height {
 			out = append(out, "")
 		}
 		return out[:height]
 	}
 
-	remaining := height - len(out)
-	if remaining <= 0 {
-		return out
+	if msg := m.state.Question.Error; msg != "" {
+		out = append(out, errStyle.Render(truncatePlain("✗ "+msg, width)))
 	}
 
-	opts := m.state.Question.Options
-	if len(opts) == 0 {
-		for len(out) < height {
-			out = append(out, "")
-		}
+	if len(out) >= height {
 		return out[:height]
 	}
 
-	visible := remaining
-	if visible > len(opts) {
-		visible = len(opts)
+	opts := m.state.Question.Options
+	optionRow, start, visible := m.questionOptionRows(height)
+
+	// Details yield to the options when the block is short on height.
+	if details := m.state.Question.Details; len(details) > 0 {
+		shown := optionRow - len(out)
+		labelW := 0
+		for _, d := range details[:shown] {
+			labelW = max(labelW, lipgloss.Width(d.Label))
+		}
+		for _, d := range details[:shown] {
+			label := d.Label + ":" + strings.Repeat(" ", labelW-lipgloss.Width(d.Label)+1)
+			out = append(out, truncateANSI("  "+mutedStyle.Render(label)+d.Value, width))
+		}
 	}
 
-	// Sliding window: keep the selected option within the visible range.
-	start := 0
-	if len(opts) > visible {
-		start = m.state.Question.Selected - visible/2
-		if start < 0 {
-			start = 0
-		}
-		maxStart := len(opts) - visible
-		if start > maxStart {
-			start = maxStart
+	if len(opts) == 0 {
+		for len(out) < height {
+			out = append(out, "")
 		}
+		return out[:height]
 	}
 
 	for i := 0; i < visible; i++ {
@@ -896,6 +959,32 @@ func (m *Model) renderQuestionLines(width int, height int) []string {
 	return out[:height]
 }
 
+// questionOptionRows returns the block row of the first visible option of a
+// select question, the index of that option and how many options fit into a
+// question block of height rows.
+func (m *Model) questionOptionRows(height int) (row int, start int, visible int) {
+	q := m.state.Question
+	row = 2 // separator + prompt
+	if q.Error != "" {
+		row++
+	}
+	if height <= row {
+		return row, 0, 0
+	}
+	row += min(len(q.Details), max(0, height-row-len(q.Options)))
+	visible = min(height-row, len(q.Options))
+	return row, windowStart(q.Selected, len(q.Options), visible), visible
+}
+
+// windowStart returns the first visible item of a scrolling list so that the
+// cursor stays in view.
+func windowStart(cursor int, total int, visible int) int {
+	if visible <= 0 || total <= visible {
+		return 0
+	}
+	return min(max(0, cursor-visible/2), total-visible)
+}
+
 func panel(title string, body string, width int, height int) string {
 	if width <= 0 || height <= 0 {
 		return ""
@@ -931,7 +1020,11 @@ func stepMarker(status domain.StepStatus) (string, lipgloss.Style, lipgloss.Styl
 	case domain.StepActive:
 		return "▣", activeStyle, lipgloss.NewStyle().Bold(true)
 	case domain.StepWarn:
-		// "Done with warnings" still reads better as a completed checkbox.
+		// "Done with warnings" still reads better as a completed checkbox,
+		// unless color is the only thing telling it apart.
+		if activeTheme.plain {
+			return "⚠", warnStyle, lipgloss.NewStyle()
+		}
 		return "✔", warnStyle, lipgloss.NewStyle()
 	case domain.StepError:
 		return "✖", errStyle, lipgloss.NewStyle()
@@ -941,6 +1034,16 @@ func stepMarker(status domain.StepStatus) (string, lipgloss.Style, lipgloss.Styl
 }
 
 func statusIndicator(level domain.StatusLevel) (string, lipgloss.Style) {
+	if activeTheme.plain {
+		switch level {
+		case domain.StatusOK:
+			return "✔", okStyle
+		case domain.StatusWarn:
+			return "⚠", warnStyle
+		default:
+			return "✖", errStyle
+		}
+	}
 	switch level {
 	case domain.StatusOK:
 		return "●", okStyle
@@ -951,12 +1054,26 @@ func statusIndicator(level domain.StatusLevel) (string, lipgloss.Style) {
 	}
 }
 
+// statusLevelText spells out non-OK levels for themes without color.
+func statusLevelText(level domain.StatusLevel) string {
+	switch level {
+	case domain.StatusOK:
+		return ""
+	case domain.StatusWarn:
+		return " (warning)"
+	default:
+		return " (error)"
+	}
+}
+
 func logPrefix(level domain.LogLevel) (string, lipgloss.Style) {
 	switch level {
 	case domain.LogError:
 		return "✖", errStyle
 	case domain.LogWarning:
 		return "⚠", warnStyle
+	case domain.LogDebug, domain.LogTrace:
+		return "·", mutedStyle
 	default:
 		return "•", mutedStyle
 	}
@@ -2,6 +2,7 @@
 
 use AllowDynamicProperties;
 use EvolutionCMS\Installer\Concerns\ConfiguresDatabase;
+use EvolutionCMS\Installer\Concerns\ImportsDatabaseDump;
 use EvolutionCMS\Installer\Presets\Preset;
 use EvolutionCMS\Installer\Process\CreatesDatabaseConfig;
 use EvolutionCMS\Installer\Utilities\Console;
@@ -22,6 +23,7 @@ use Symfony\Component\Process\Process;
 class InstallCommand extends Command
 {
     use ConfiguresDatabase;
+    use ImportsDatabaseDump;
 
     protected ?OutputInterface $logSection = null;
     protected ?TuiRenderer $tui = null;
@@ -54,7 +56,16 @@ class InstallCommand extends Command
             ->addOption('db-name', null, InputOption::VALUE_OPTIONAL, 'The database name')
             ->addOption('db-user', null, InputOption::VALUE_OPTIONAL, 'The database user')
             ->addOption('db-password', null, InputOption::VALUE_OPTIONAL, 'The database password')
+            ->addOption('db-socket', null, InputOption::VALUE_OPTIONAL, 'The database Unix socket (MySQL socket file or PostgreSQL socket directory)')
+            ->addOption('db-ssl-mode', null, InputOption::VALUE_OPTIONAL, 'The database TLS mode (disable, prefer, require, verify-ca, verify-full)')
+            ->addOption('db-ssl-ca', null, InputOption::VALUE_OPTIONAL, 'CA certificate (PEM) to verify the database server')
+            ->addOption('db-ssl-cert', null, InputOption::VALUE_OPTIONAL, 'Client certificate (PEM) for the database connection')
+            ->addOption('db-ssl-key', null, InputOption::VALUE_OPTIONAL, 'Client private key (PEM) for the database connection')
+            ->addOption('db-collation', null, InputOption::VALUE_OPTIONAL, 'The MySQL collation of the database (default depends on the server version)')
+            ->addOption('db-options', null, InputOption::VALUE_OPTIONAL, 'Extra database driver options as a URL query string (e.g. sslmode=require)')
             ->addOption('db-prefix', null, InputOption::VALUE_OPTIONAL, 'The database table prefix', 'evo_')
+            ->addOption('db-import', null, InputOption::VALUE_OPTIONAL, 'SQL dump (.sql or .sql.gz) to load instead of running the seeders')
+            ->addOption('db-existing', null, InputOption::VALUE_OPTIONAL, 'What to do with the Evolution CMS tables already in the database (keep: update them instead of seeding)')
             ->addOption('admin-username', null, InputOption::VALUE_OPTIONAL, 'The admin username')
             ->addOption('admin-email', null, InputOption::VALUE_OPTIONAL, 'The admin email')
             ->addOption('admin-password', null, InputOption::VALUE_OPTIONAL, 'The admin password')
@@ -114,6 +125,11 @@ class InstallCommand extends Command
         $options['install_in_current_dir'] = $installInCurrentDir;
         $options['composer_clear_cache'] = (bool) $input->getOption('composer-clear-cache');
         $options['composer_update'] = (bool) $input->getOption('composer-update');
+        $options['db_import'] = $this->resolveDatabaseDump($input->getOption('db-import'));
+        $options['db_existing'] = trim((string) $input->getOption('db-existing'));
+        if (!in_array($options['db_existing'], ['', 'keep'], true)) {
+            throw new \InvalidArgumentException('Invalid --db-existing: the only supported value is "keep".');
+        }
 
         // Step 3: Download Evolution CMS
         $branch = $input->getOption('branch');
@@ -222,6 +238,31 @@ class InstallCommand extends Command
                 $inputs['database'] = $this->gatherDatabaseInputs($helper, $input, $output, $firstAttempt);
             }
 
+            $dbOptions = $this->parseDatabaseOptions($input->getOption('db-options'));
+            if ($dbOptions !== [] && ($inputs['database']['type'] ?? '') !== 'sqlite') {
+                $inputs['database']['options'] = $dbOptions;
+            }
+            $collation = trim((string) $input->getOption('db-collation'));
+            if ($collation !== '' && ($inputs['database']['type'] ?? '') === 'mysql' && preg_match('/^[A-Za-z0-9]+_[A-Za-z0-9_]+$/', $collation)) {
+                $inputs['database']['collation'] = $collation;
+            }
+            $prefix = trim((string) $input->getOption('db-prefix'));
+            if ($prefix !== '') {
+                // The prefix ends up in raw SQL table names.
+                if (!preg_match('/^[A-Za-z][A-Za-z0-9_]{0,18}_$/', $prefix)) {
+                    throw new \InvalidArgumentException('Invalid --db-prefix: it must start with a letter and may only contain letters, digits and underscores, and end with an underscore.');
+                }
+                $inputs['database']['prefix'] = $prefix;
+            }
+            if (($inputs['database']['type'] ?? '') !== 'sqlite') {
+                foreach (['socket' => 'db-socket', 'ssl_mode' => 'db-ssl-mode', 'ssl_ca' => 'db-ssl-ca', 'ssl_cert' => 'db-ssl-cert', 'ssl_key' => 'db-ssl-key'] as $key => $option) {
+                    $value = trim((string) $input->getOption($option));
+                    if ($value !== '') {
+                        $inputs['database'][$key] = $value;
+                    }
+                }
+            }
+
             // Test database connection
             if ($this->testDatabaseConnection($inputs['database'])) {
                 $databaseConnected = true;
@@ -491,7 +532,7 @@ class InstallCommand extends Command
             if ($type === 'sqlite') {
                 // SQLite: Connect directly to the database file
                 if (empty($config['name'])) {
-                    $this->tui->replaceLastLogs('<fg=red>✗</> Database connection failed: SQLite database name is required.', 2);
+                    $this->tui->replaceLastLogs('<fg=red>✗</> Database connection failed: SQLite database name is required.', 2, 'error');
                     $this->lastDatabaseConnectionError = 'SQLite database name is required.';
                     return false;
                 }
@@ -559,7 +600,7 @@ class InstallCommand extends Command
             }
 
             $this->lastDatabaseConnectionError = $errorMessage;
-            $this->tui->replaceLastLogs('<fg=red>✗</> Database connection failed: ' . $errorMessage, 2);
+            $this->tui->replaceLastLogs('<fg=red>✗</> Database connection failed: ' . $errorMessage, 2, 'error');
             return false;
         }
     }
@@ -650,12 +691,12 @@ class InstallCommand extends Command
             $answer = $this->tui->ask('Enter your Admin email:');
 
             if (empty($answer)) {
-                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Email address cannot be empty. Please try again.', 3);
+                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Email address cannot be empty. Please try again.', 3, 'warning');
                 continue;
             }
 
             if (!filter_var($answer, FILTER_VALIDATE_EMAIL)) {
-                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Please enter a valid email address. Try again.', 3);
+                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Please enter a valid email address. Try again.', 3, 'warning');
                 continue;
             }
 
@@ -674,12 +715,12 @@ class InstallCommand extends Command
             $answer = $this->tui->ask('Enter your Admin password:');
 
             if (empty($answer)) {
-                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Password cannot be empty. Please try again.', 3);
+                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Password cannot be empty. Please try again.', 3, 'warning');
                 continue;
             }
 
             if (strlen($answer) < 6) {
-                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Password must be at least 6 characters long. Try again.', 3);
+                $this->tui->replaceLastLogs('<fg=yellow>⚠</> Password must be at least 6 characters long. Try again.', 3, 'warning');
                 continue;
             }
 
@@ -713,6 +754,31 @@ class InstallCommand extends Command
         return $sanitized;
     }
 
+    /**
+     * Parse --db-options (a URL query string) into DSN options.
+     * Invalid names and values that could break out of the DSN are dropped.
+     */
+    protected function parseDatabaseOptions(?string $raw): array
+    {
+        $raw = trim((string) $raw);
+        if ($raw === '') {
+            return [];
+        }
+
+        parse_str($raw, $parsed);
+        $options = [];
+        foreach ($parsed as $key => $value) {
+            if (!is_string($key) || !preg_match('/^[A-Za-z_][A-Za-z0-9_]*$/', $key)) {
+                continue;
+            }
+            if (!is_string($value) || preg_match('/[;\x00\r\n]/', $value)) {
+                continue;
+            }
+            $options[$key] = $value;
+        }
+        return $options;
+    }
+
     /**
      * Ask for installation language with interactive radio button selection.
      */
@@ -860,7 +926,7 @@ class InstallCommand extends Command
             $version = $versionResolver->getLatestCompatibleVersion($phpVersion, true);
 
             if (!$version) {
-                $this->tui->replaceLastLogs('<fg=red>✗</> Could not find a compatible Evolution CMS version for PHP ' . $phpVersion . '.', 2);
+                $this->tui->replaceLastLogs('<fg=red>✗</> Could not find a compatible Evolution CMS version for PHP ' . $phpVersion . '.', 2, 'error');
                 throw new \RuntimeException("Could not find a compatible Evolution CMS version for PHP {$phpVersion}.");
             }
 
@@ -1058,14 +1124,33 @@ class InstallCommand extends Command
         // Step 4.2: Install dependencies via Composer
         $this->setupComposer($projectPath, $options);
 
-        // Step 4.3: Run database migrations
-        $this->runMigrations($projectPath, $options);
+        if (($options['db_import'] ?? '') !== '') {
+            // Step 4.3: Load the SQL dump in place of the seeders
+            $this->importDatabaseDump($projectPath, $options, $options['db_import']);
+
+            // Step 4.4: Run the migrations the dump predates
+            $this->runMigrations($projectPath, $options);
 
-        // Step 4.4: Run database seeders (roles/permissions/templates)
-        $this->runSeeders($projectPath, $options);
+            // Create or reset the admin account; the dump keeps its own settings and users.
+            $this->createAdminUserDirectly($projectPath, $options, false);
+            $this->tui->addLog('Database import completed successfully.', 'success');
+        } elseif (($options['db_existing'] ?? '') === 'keep') {
+            // Step 4.3: Update the existing installation's tables
+            $this->runMigrations($projectPath, $options);
+
+            // The tables keep their settings and users; only the admin account is created or reset.
+            $this->createAdminUserDirectly($projectPath, $options, false);
+            $this->tui->addLog('Existing database updated successfully.', 'success');
+        } else {
+            // Step 4.3: Run database migrations
+            $this->runMigrations($projectPath, $options);
 
-        // Apply selected language to manager settings (system_settings.manager_language).
-        $this->setManagerLanguage($projectPath, $options);
+            // Step 4.4: Run database seeders (roles/permissions/templates)
+            $this->runSeeders($projectPath, $options);
+
+            // Apply selected language to manager settings (system_settings.manager_language).
+            $this->setManagerLanguage($projectPath, $options);
+        }
 
         // Mark installation steps as completed
         $this->steps['install']['completed'] = true;
@@ -1110,6 +1195,11 @@ class InstallCommand extends Command
             }
         }
         $recommendedCollation = $this->getRecommendedCollation($dbConfig['type'], $charset, $serverVersion);
+        if ($dbConfig['type'] === 'mysql' && !empty($dbConfig['collation'])) {
+            // Chosen when the Go installer created the database (--db-collation).
+            $recommendedCollation = $dbConfig['collation'];
+            $charset = $this->getCharsetFromCollation($recommendedCollation);
+        }
 
         // Create database with recommended collation
         $dbConfigForOps = $this->getDatabaseConfigForOperations($projectPath, $dbConfig);
@@ -1336,7 +1426,7 @@ class InstallCommand extends Command
                     throw new \RuntimeException("Seeder {$seeder} failed: {$fullError}");
                 }
             } catch (\Exception $e) {
-                $this->tui->replaceLastLogs("<fg=red>✗</> Seeder {$seeder} failed: " . $e->getMessage(), 2);
+                $this->tui->replaceLastLogs("<fg=red>✗</> Seeder {$seeder} failed: " . $e->getMessage(), 2, 'error');
                 throw $e;
             }
         }
@@ -1358,7 +1448,7 @@ class InstallCommand extends Command
         $composerJson = $composerWorkDir . '/composer.json';
 
         if (!file_exists($composerJson)) {
-            $this->tui->replaceLastLogs('<fg=yellow>⚠</> composer.json not found. Skipping dependency installation.', 2);
+            $this->tui->replaceLastLogs('<fg=yellow>⚠</> composer.json not found. Skipping dependency installation.', 2, 'warning');
             return;
         }
 
@@ -1604,17 +1694,17 @@ class InstallCommand extends Command
         }
         $process->setEnv($env);
 
-        $this->tui->addLog('Composer executable: ' . $this->describeComposerExecutable($composerCommand));
+        $this->tui->addLog('Composer executable: ' . $this->describeComposerExecutable($composerCommand), 'debug');
         $version = $this->getComposerVersion($composerCommand, $workingDir);
         if ($version !== null) {
-            $this->tui->addLog('Composer version: ' . $version);
+            $this->tui->addLog('Composer version: ' . $version, 'debug');
         } else {
             $this->tui->addLog('Composer version: unknown', 'warning');
         }
-        $this->tui->addLog('Composer command: ' . $this->formatComposerCommand($fullCommand));
-        $this->tui->addLog('Composer working dir: ' . $workingDir);
-        $this->tui->addLog('Composer timeout: ' . $this->formatComposerTimeout($timeout, $idleTimeout));
-        $this->tui->addLog('Composer env: ' . $this->formatComposerEnvSummary($env));
+        $this->tui->addLog('Composer command: ' . $this->formatComposerCommand($fullCommand), 'debug');
+        $this->tui->addLog('Composer working dir: ' . $workingDir, 'debug');
+        $this->tui->addLog('Composer timeout: ' . $this->formatComposerTimeout($timeout, $idleTimeout), 'debug');
+        $this->tui->addLog('Composer env: ' . $this->formatComposerEnvSummary($env), 'debug');
 
         $buffers = [
             Process::OUT => '',
@@ -2752,7 +2842,7 @@ class InstallCommand extends Command
             $this->tui->replaceLastLogs('<fg=green>✔</> Package discovery completed.');
         } else {
             $fullOutput = trim($process->getOutput() . "\n" . $process->getErrorOutput());
-            $this->tui->replaceLastLogs('<fg=red>✗</> Package discovery failed.');
+            $this->tui->replaceLastLogs('<fg=red>✗</> Package discovery failed.', 1, 'error');
             if ($fullOutput !== '') {
                 $this->tui->addLog($fullOutput, 'error');
             }
@@ -2877,9 +2967,10 @@ class InstallCommand extends Command
      *
      * @param string $projectPath
      * @param array $options
+     * @param bool $adoptSingleUser Update the only existing user when none matches (fresh installs)
      * @return void
      */
-    protected function createAdminUserDirectly(string $projectPath, array $options): void
+    protected function createAdminUserDirectly(string $projectPath, array $options, bool $adoptSingleUser = true): void
     {
         $this->tui->addLog('Creating admin user directly in the database...', 'info');
 
@@ -2927,7 +3018,7 @@ class InstallCommand extends Command
 
             // If we didn't find by login/email and this looks like a fresh install (single user),
             // update that user instead of creating a second admin account.
-            if (!is_array($existingUser)) {
+            if (!is_array($existingUser) && $adoptSingleUser) {
                 try {
                     $count = (int) ($dbh->query("SELECT COUNT(*) FROM {$usersTable}")?->fetchColumn() ?: 0);
                     if ($count === 1) {
@@ -3020,7 +3111,7 @@ class InstallCommand extends Command
             $this->verifyAdminCredentials($dbh, $usersTable, $loginColumn, $username, $password);
             $this->tui->replaceLastLogs('<fg=green>✔</> Admin user created successfully.', 2);
         } catch (\Exception $e) {
-            $this->tui->replaceLastLogs('<fg=red>✗</> Could not create admin user automatically: ' . $e->getMessage(), 2);
+            $this->tui->replaceLastLogs('<fg=red>✗</> Could not create admin user automatically: ' . $e->getMessage(), 2, 'error');
             throw $e;
         }
     }
@@ -7,6 +7,7 @@ use EvolutionCMS\Installer\Utilities\Console;
 trait ConfiguresDatabase
 {
     use HandlesCollations;
+    use ResolvesDatabaseTransport;
 
     /**
      * Create database connection.
@@ -24,18 +25,23 @@ trait ConfiguresDatabase
         $user = $config['user'] ?? '';
         $password = $config['password'] ?? '';
 
-        $dsn = $this->buildDsn($type, $host, $port, $database);
+        $options = $config['options'] ?? [];
+        $attributes = [PDO::ATTR_ERRMODE => PDO::ERRMODE_EXCEPTION];
+        if ($type !== 'sqlite') {
+            $transport = $this->resolveDatabaseTransport($config);
+            $host = $transport['host'];
+            $options = array_merge($options, $transport['options']);
+            $attributes += $transport['attributes'];
+        }
+
+        $dsn = $this->buildDsn($type, $host, $port, $database, $options);
 
         try {
             // SQLite doesn't need username/password
             if ($type === 'sqlite') {
-                $dbh = new PDO($dsn, null, null, [
-                    PDO::ATTR_ERRMODE => PDO::ERRMODE_EXCEPTION,
-                ]);
+                $dbh = new PDO($dsn, null, null, $attributes);
             } else {
-                $dbh = new PDO($dsn, $user, $password, [
-                    PDO::ATTR_ERRMODE => PDO::ERRMODE_EXCEPTION,
-                ]);
+                $dbh = new PDO($dsn, $user, $password, $attributes);
             }
 
             return $dbh;
@@ -76,9 +82,10 @@ trait ConfiguresDatabase
      * @param string $host
      * @param int|null $port
      * @param string|null $database
+     * @param array $options Extra driver options appended as key=value pairs
      * @return string
      */
-    protected function buildDsn(string $type, string $host, ?int $port, ?string $database): string
+    protected function buildDsn(string $type, string $host, ?int $port, ?string $database, array $options = []): string
     {
         if ($type === 'sqlite') {
             // SQLite: database is the path to the file
@@ -98,7 +105,7 @@ trait ConfiguresDatabase
             if ($database) {
                 $dsn .= ";Database={$database}";
             }
-            return $dsn;
+            return $dsn . $this->buildDsnOptions($options);
         }
 
         if ($type === 'pgsql') {
@@ -109,7 +116,7 @@ trait ConfiguresDatabase
             if ($database) {
                 $dsn .= ";dbname={$database}";
             }
-            return $dsn;
+            return $dsn . $this->buildDsnOptions($options);
         }
 
         // MySQL / MariaDB
@@ -121,7 +128,29 @@ trait ConfiguresDatabase
             $dsn .= ";dbname={$database}";
         }
         
-        return $dsn;
+        return $dsn . $this->buildDsnOptions($options);
+    }
+
+    /**
+     * Render extra driver options as ";key=value" DSN pairs.
+     *
+     * @param array $options
+     * @return string
+     */
+    protected function buildDsnOptions(array $options): string
+    {
+        $out = '';
+        foreach ($options as $key => $value) {
+            if (!is_string($key) || !preg_match('/^[A-Za-z_][A-Za-z0-9_]*$/', $key)) {
+                continue;
+            }
+            $value = (string) $value;
+            if (preg_match('/[;\x00\r\n]/', $value)) {
+                continue;
+            }
+            $out .= ";{$key}={$value}";
+        }
+        return $out;
     }
 
     /**
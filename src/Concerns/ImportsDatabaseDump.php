@@ -0,0 +1,368 @@
+<?php namespace EvolutionCMS\Installer\Concerns;
+
+use PDO;
+use PDOException;
+
+trait ImportsDatabaseDump
+{
+    /**
+     * Validate the --db-import file and return its absolute path ('' when not set).
+     *
+     * @param string|null $path
+     * @return string
+     */
+    protected function resolveDatabaseDump(?string $path): string
+    {
+        $path = trim((string) $path);
+        if ($path === '') {
+            return '';
+        }
+
+        $lower = strtolower($path);
+        if (!str_ends_with($lower, '.sql') && !str_ends_with($lower, '.sql.gz')) {
+            throw new \InvalidArgumentException('Invalid --db-import: expected a .sql or .sql.gz file.');
+        }
+
+        $real = realpath($path);
+        if ($real === false || !is_file($real) || !is_readable($real)) {
+            throw new \InvalidArgumentException("Invalid --db-import: cannot read {$path}.");
+        }
+
+        return $real;
+    }
+
+    /**
+     * Load an SQL dump (plain or gzipped) into the configured database.
+     *
+     * Statements are streamed, so dumps larger than memory work. Progress is
+     * reported in bytes of uncompressed SQL.
+     *
+     * @param string $projectPath
+     * @param array $options
+     * @param string $file
+     * @return void
+     */
+    protected function importDatabaseDump(string $projectPath, array $options, string $file): void
+    {
+        $dbConfig = $this->getDatabaseConfigForOperations($projectPath, $options['database'] ?? []);
+        $type = (string) ($dbConfig['type'] ?? 'mysql');
+        $name = basename($file);
+        $total = $this->databaseDumpSize($file);
+
+        $this->tui->addLog("Importing database dump {$name}...");
+
+        // gzopen() reads uncompressed files transparently.
+        $fh = gzopen($file, 'rb');
+        if ($fh === false) {
+            throw new \RuntimeException("Cannot open database dump {$file}.");
+        }
+
+        $dbh = $this->createConnection($dbConfig);
+        if ($type === 'mysql') {
+            $dbh->exec('SET FOREIGN_KEY_CHECKS=0');
+        } elseif ($type === 'sqlite') {
+            $dbh->exec('PRAGMA foreign_keys = OFF');
+            // One transaction keeps SQLite from syncing the file after every INSERT.
+            $dbh->beginTransaction();
+        }
+
+        $state = ['buffer' => '', 'delimiter' => ';', 'quote' => null, 'comment' => false, 'dollar' => null];
+        $lineNo = 0;
+        $executed = 0;
+        $reported = 0;
+        $step = max(65536, intdiv($total, 100));
+
+        try {
+            while (($line = gzgets($fh)) !== false) {
+                $lineNo++;
+                foreach ($this->splitDumpStatements($line, $state, $type) as $sql) {
+                    if ($type === 'pgsql' && preg_match('/^COPY\s+(\S+)\s*(?:\(([^)]*)\))?\s+FROM\s+stdin$/i', $sql, $m)) {
+                        $this->copyDumpRows($dbh, $fh, $m[1], $m[2] ?? '', $lineNo);
+                        $executed++;
+                        continue;
+                    }
+                    $this->execDumpStatement($dbh, $sql, $lineNo);
+                    $executed++;
+                }
+
+                $position = (int) gztell($fh);
+                if ($position - $reported >= $step) {
+                    $reported = $position;
+                    $this->tui->updateProgress('Importing database dump', $position, max($total, $position), 'bytes');
+                }
+            }
+
+            // The last statement may lack a delimiter.
+            $tail = trim($state['buffer']);
+            if ($tail !== '' && $state['quote'] === null && $state['dollar'] === null) {
+                $this->execDumpStatement($dbh, $tail, $lineNo);
+                $executed++;
+            }
+
+            if ($dbh->inTransaction()) {
+                $dbh->commit();
+            }
+        } catch (\Throwable $e) {
+            if ($dbh->inTransaction()) {
+                // SQLite: the transaction takes the whole import back.
+                $dbh->rollBack();
+                throw $e;
+            }
+            throw $this->partialImportError($e, $executed);
+        } finally {
+            gzclose($fh);
+            if ($type === 'mysql') {
+                try {
+                    $dbh->exec('SET FOREIGN_KEY_CHECKS=1');
+                } catch (\Throwable $ignored) {
+                    // A lost connection takes the session setting with it.
+                }
+            }
+        }
+
+        $position = max($total, $reported);
+        $this->tui->updateProgress('Importing database dump', $position, $position, 'bytes');
+        $this->tui->addLog("Imported {$executed} statement(s) from {$name}.", 'success');
+
+        $this->assertDumpMatchesTablePrefix($dbh, (string) ($dbConfig['prefix'] ?? ($options['database']['prefix'] ?? 'evo_')));
+    }
+
+    /**
+     * The error for an import that stopped part way: the statements before
+     * the failure stay in the database, so the user is pointed to the backup
+     * taken before the import (EVO_DB_BACKUPS, set by the evo installer).
+     *
+     * @param \Throwable $e
+     * @param int $executed
+     * @return \RuntimeException
+     */
+    protected function partialImportError(\Throwable $e, int $executed): \RuntimeException
+    {
+        $message = rtrim($e->getMessage(), '.') . ". The database is partially imported ({$executed} statement(s) ran).";
+        $backups = array_filter(explode(PATH_SEPARATOR, (string) getenv('EVO_DB_BACKUPS')));
+        $message .= $backups
+            ? ' Restore it from the backup taken before the import: ' . implode(', ', $backups) . '.'
+            : ' No backup was taken before the import; drop the imported tables before retrying.';
+
+        return new \RuntimeException($message, 0, $e);
+    }
+
+    /**
+     * Size of the SQL in a dump, for progress. gzip keeps the uncompressed size
+     * modulo 4 GiB in its last four bytes.
+     *
+     * @param string $file
+     * @return int
+     */
+    protected function databaseDumpSize(string $file): int
+    {
+        $size = (int) @filesize($file);
+        if (!str_ends_with(strtolower($file), '.gz') || $size < 18) {
+            return $size;
+        }
+
+        $fh = @fopen($file, 'rb');
+        if ($fh === false) {
+            return $size;
+        }
+        fseek($fh, -4, SEEK_END);
+        $tail = fread($fh, 4);
+        fclose($fh);
+
+        if (!is_string($tail) || strlen($tail) !== 4) {
+            return $size;
+        }
+
+        return max($size, (int) unpack('V', $tail)[1]);
+    }
+
+    /**
+     * Feed one dump line to the statement splitter and return the statements
+     * it completes. $state carries the unfinished statement and the quoting
+     * context (string, block comment, PostgreSQL dollar quote) between lines.
+     *
+     * @param string $line
+     * @param array $state
+     * @param string $type
+     * @return string[]
+     */
+    protected function splitDumpStatements(string $line, array &$state, string $type): array
+    {
+        $idle = $state['buffer'] === '' && $state['quote'] === null && !$state['comment'] && $state['dollar'] === null;
+        if ($idle) {
+            $trimmed = trim($line);
+            if ($trimmed === '' || str_starts_with($trimmed, '--') || ($type === 'mysql' && str_starts_with($trimmed, '#'))) {
+                return [];
+            }
+            // mysqldump switches the delimiter around triggers and routines.
+            if ($type === 'mysql' && preg_match('/^DELIMITER\s+(\S+)$/i', $trimmed, $m)) {
+                $state['delimiter'] = $m[1];
+                return [];
+            }
+        }
+
+        $delimiter = $state['delimiter'];
+        $statements = [];
+        $len = strlen($line);
+        $start = 0;
+        $i = 0;
+
+        while ($i < $len) {
+            if ($state['comment']) {
+                $end = strpos($line, '*/', $i);
+                if ($end === false) {
+                    break;
+                }
+                $state['comment'] = false;
+                $i = $end + 2;
+                continue;
+            }
+
+            if ($state['quote'] !== null) {
+                $quote = $state['quote'];
+                // Only MySQL escapes quotes with a backslash; PostgreSQL dumps use standard strings.
+                $i += strcspn($line, ($type === 'mysql' && $quote !== '`') ? $quote . '\\' : $quote, $i);
+                if ($i >= $len) {
+                    break;
+                }
+                if ($line[$i] === '\\') {
+                    $i += 2;
+                    continue;
+                }
+                $state['quote'] = null;
+                $i++;
+                continue;
+            }
+
+            if ($state['dollar'] !== null) {
+                $end = strpos($line, $state['dollar'], $i);
+                if ($end === false) {
+                    break;
+                }
+                $i = $end + strlen($state['dollar']);
+                $state['dollar'] = null;
+                continue;
+            }
+
+            $i += strcspn($line, "'\"`/-#\$" . $delimiter[0], $i);
+            if ($i >= $len) {
+                break;
+            }
+
+            if (substr_compare($line, $delimiter, $i, strlen($delimiter)) === 0) {
+                $sql = trim($state['buffer'] . substr($line, $start, $i - $start));
+                if ($sql !== '') {
+                    $statements[] = $sql;
+                }
+                $state['buffer'] = '';
+                $i += strlen($delimiter);
+                $start = $i;
+                continue;
+            }
+
+            $char = $line[$i];
+            $next = $line[$i + 1] ?? '';
+            if ($char === "'" || $char === '"' || ($char === '`' && $type === 'mysql')) {
+                $state['quote'] = $char;
+                $i++;
+            } elseif ($char === '/' && $next === '*') {
+                // Kept in the statement: MySQL runs /*!40101 ... */ conditional comments.
+                $state['comment'] = true;
+                $i += 2;
+            } elseif (($char === '-' && $next === '-') || ($char === '#' && $type === 'mysql')) {
+                break;
+            } elseif ($char === '$' && $type === 'pgsql' && preg_match('/\$[A-Za-z_]*\$/A', $line, $m, 0, $i)) {
+                $state['dollar'] = $m[0];
+                $i += strlen($m[0]);
+            } else {
+                $i++;
+            }
+        }
+
+        $rest = substr($line, $start);
+        if ($state['buffer'] !== '' || trim($rest) !== '') {
+            $state['buffer'] .= $rest;
+        }
+
+        return $statements;
+    }
+
+    protected function execDumpStatement(PDO $dbh, string $sql, int $lineNo): void
+    {
+        try {
+            $dbh->exec($sql);
+        } catch (PDOException $e) {
+            $snippet = preg_replace('/\s+/', ' ', $sql) ?? $sql;
+            if (strlen($snippet) > 120) {
+                $snippet = substr($snippet, 0, 117) . '...';
+            }
+            throw new \RuntimeException("Database import failed near line {$lineNo} ({$snippet}): " . $e->getMessage(), 0, $e);
+        }
+    }
+
+    /**
+     * Load the data block of a pg_dump "COPY ... FROM stdin;" statement, which
+     * PDO cannot run as plain SQL.
+     *
+     * @param PDO $dbh
+     * @param resource $fh
+     * @param string $table
+     * @param string $columns
+     * @param int $lineNo
+     * @return void
+     */
+    protected function copyDumpRows(PDO $dbh, $fh, string $table, string $columns, int &$lineNo): void
+    {
+        $fields = trim($columns) !== '' ? trim($columns) : null;
+        $flush = function (array $rows) use ($dbh, $table, $fields, &$lineNo): void {
+            if ($rows === []) {
+                return;
+            }
+            try {
+                $dbh->pgsqlCopyFromArray($table, $rows, "\t", "\\\\N", $fields);
+            } catch (PDOException $e) {
+                throw new \RuntimeException("Database import failed near line {$lineNo} (COPY {$table}): " . $e->getMessage(), 0, $e);
+            }
+        };
+
+        $rows = [];
+        while (($row = gzgets($fh)) !== false) {
+            $lineNo++;
+            $row = rtrim($row, "\r\n");
+            if ($row === '\\.') {
+                break;
+            }
+            $rows[] = $row;
+            if (count($rows) >= 5000) {
+                $flush($rows);
+                $rows = [];
+            }
+        }
+        $flush($rows);
+    }
+
+    /**
+     * Fail early when the dump was taken with another table prefix: the
+     * migrations would otherwise build a second, empty schema next to it.
+     *
+     * @param PDO $dbh
+     * @param string $prefix
+     * @return void
+     */
+    protected function assertDumpMatchesTablePrefix(PDO $dbh, string $prefix): void
+    {
+        $tables = array_map('strtolower', $this->listDatabaseTables($dbh));
+        if (in_array(strtolower($prefix . 'site_content'), $tables, true)) {
+            return;
+        }
+
+        foreach ($tables as $table) {
+            if (str_ends_with($table, 'site_content')) {
+                $found = substr($table, 0, -strlen('site_content'));
+                throw new \RuntimeException("The imported dump uses table prefix \"{$found}\", not \"{$prefix}\". Re-run with --db-table-prefix={$found}.");
+            }
+        }
+
+        throw new \RuntimeException("The imported dump has no Evolution CMS tables ({$prefix}site_content is missing).");
+    }
+}
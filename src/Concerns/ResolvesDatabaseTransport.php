@@ -0,0 +1,110 @@
+<?php namespace EvolutionCMS\Installer\Concerns;
+
+use PDO;
+
+/**
+ * Maps --db-socket and the --db-ssl-* options onto what each driver reads.
+ * Shared by the installer connection and the generated database config.
+ */
+trait ResolvesDatabaseTransport
+{
+    /**
+     * Resolve --db-socket and the --db-ssl-* options for a server driver.
+     *
+     * The socket becomes the MySQL unix_socket (used only with host
+     * "localhost") or the PostgreSQL host directory. TLS becomes DSN options
+     * for PostgreSQL and SQL Server and PDO attributes for MySQL.
+     *
+     * @param array $config
+     * @return array{host: string, options: array, attributes: array}
+     */
+    protected function resolveDatabaseTransport(array $config): array
+    {
+        $type = $config['type'] ?? 'mysql';
+        $host = (string) ($config['host'] ?? '');
+        $socket = trim((string) ($config['socket'] ?? ''));
+        $mode = strtolower(trim((string) ($config['ssl_mode'] ?? '')));
+        $ca = trim((string) ($config['ssl_ca'] ?? ''));
+        $cert = trim((string) ($config['ssl_cert'] ?? ''));
+        $key = trim((string) ($config['ssl_key'] ?? ''));
+        $options = [];
+        $attributes = [];
+
+        if ($type === 'mysql') {
+            if ($socket !== '') {
+                $host = 'localhost';
+                $options['unix_socket'] = $socket;
+            }
+            $attributes = $this->mysqlSslAttributes($mode, $ca, $cert, $key);
+        } elseif ($type === 'pgsql') {
+            if ($socket !== '') {
+                $host = str_starts_with(basename($socket), '.s.PGSQL.') ? dirname($socket) : $socket;
+            }
+            $options = array_filter([
+                'sslmode' => $mode,
+                'sslrootcert' => $ca,
+                'sslcert' => $cert,
+                'sslkey' => $key,
+            ], static fn ($value) => $value !== '');
+        } elseif ($type === 'sqlsrv') {
+            if ($mode === 'disable') {
+                $options['Encrypt'] = 'no';
+            } elseif ($mode === 'require') {
+                $options['Encrypt'] = 'yes';
+                $options['TrustServerCertificate'] = 'yes';
+            } elseif ($mode === 'verify-ca' || $mode === 'verify-full') {
+                $options['Encrypt'] = 'yes';
+                $options['TrustServerCertificate'] = 'no';
+            }
+        }
+
+        return ['host' => $host, 'options' => $options, 'attributes' => $attributes];
+    }
+
+    /**
+     * PDO attributes that turn on TLS for MySQL.
+     *
+     * pdo_mysql only enables TLS when a CA, CA path, certificate or key is
+     * set, so without --db-ssl-ca the system CA directory is used.
+     *
+     * @return array
+     */
+    protected function mysqlSslAttributes(string $mode, string $ca, string $cert, string $key): array
+    {
+        $tls = in_array($mode, ['require', 'verify-ca', 'verify-full'], true)
+            || ($mode !== 'disable' && ($ca !== '' || $cert !== '' || $key !== ''));
+        if (!$tls) {
+            return [];
+        }
+
+        $attributes = [];
+        if ($ca !== '') {
+            $attributes[PDO::MYSQL_ATTR_SSL_CA] = $ca;
+        } else {
+            $attributes[PDO::MYSQL_ATTR_SSL_CAPATH] = $this->defaultCaPath();
+        }
+        if ($cert !== '') {
+            $attributes[PDO::MYSQL_ATTR_SSL_CERT] = $cert;
+        }
+        if ($key !== '') {
+            $attributes[PDO::MYSQL_ATTR_SSL_KEY] = $key;
+        }
+        $attributes[PDO::MYSQL_ATTR_SSL_VERIFY_SERVER_CERT] = str_starts_with($mode, 'verify-');
+
+        return $attributes;
+    }
+
+    /**
+     * System CA certificate directory.
+     */
+    protected function defaultCaPath(): string
+    {
+        if (function_exists('openssl_get_cert_locations')) {
+            $dir = openssl_get_cert_locations()['default_cert_dir'] ?? '';
+            if (is_string($dir) && $dir !== '') {
+                return $dir;
+            }
+        }
+        return '/etc/ssl/certs';
+    }
+}
@@ -1,9 +1,13 @@
 <?php namespace EvolutionCMS\Installer\Process;
 
+use EvolutionCMS\Installer\Concerns\ResolvesDatabaseTransport;
 use EvolutionCMS\Installer\Utilities\Console;
+use PDO;
 
 class CreatesDatabaseConfig
 {
+    use ResolvesDatabaseTransport;
+
     /**
      * Create database configuration file.
      *
@@ -52,6 +56,12 @@ class CreatesDatabaseConfig
             'prefix' => $tablePrefix,
             'method' => $method,
             'engine' => $engine,
+            'options' => $dbConfig['options'] ?? [],
+            'socket' => $dbConfig['socket'] ?? '',
+            'ssl_mode' => $dbConfig['ssl_mode'] ?? '',
+            'ssl_ca' => $dbConfig['ssl_ca'] ?? '',
+            'ssl_cert' => $dbConfig['ssl_cert'] ?? '',
+            'ssl_key' => $dbConfig['ssl_key'] ?? '',
         ]);
 
         if (file_exists($configFile) && !is_writable($configFile)) {
@@ -93,8 +103,25 @@ class CreatesDatabaseConfig
         if ($rawDriver === 'sqlite') {
             $rawDatabase = $this->normalizeSqliteDatabasePath($rawDatabase);
         }
+        $rawHost = (string) $params['host'];
+        $rawSocket = $rawDriver === 'sqlite' ? '' : trim((string) ($params['socket'] ?? ''));
+        $transport = $this->resolveDatabaseTransport([
+            'type' => $rawDriver,
+            'host' => $rawHost,
+            'socket' => $rawSocket,
+            'ssl_mode' => $params['ssl_mode'] ?? '',
+            'ssl_ca' => $params['ssl_ca'] ?? '',
+            'ssl_cert' => $params['ssl_cert'] ?? '',
+            'ssl_key' => $params['ssl_key'] ?? '',
+        ]);
+        if ($rawSocket !== '' && $rawDriver === 'pgsql') {
+            // PostgreSQL takes the socket directory as host.
+            $rawHost = $transport['host'];
+            $rawSocket = '';
+        }
         $driver = $this->escapePhpString($rawDriver);
-        $host = $this->escapePhpString((string) $params['host']);
+        $host = $this->escapePhpString($rawHost);
+        $socket = $this->escapePhpString($rawSocket);
         $port = $this->escapePhpString((string) ($params['port'] ?? ''));
         $database = $this->escapePhpString($rawDatabase);
         $username = $this->escapePhpString((string) $params['username']);
@@ -107,6 +134,15 @@ class CreatesDatabaseConfig
         $foreignKeysLine = $rawDriver === 'sqlite'
             ? "    'foreign_key_constraints' => env('DB_FOREIGN_KEYS', true),\n"
             : '';
+        $driverOptions = (array) ($params['options'] ?? []);
+        // --db-ssl-* win over the same settings in --db-options.
+        foreach (['sslmode' => 'ssl_mode', 'sslrootcert' => 'ssl_ca', 'sslcert' => 'ssl_cert', 'sslkey' => 'ssl_key'] as $optionKey => $param) {
+            if (trim((string) ($params[$param] ?? '')) !== '') {
+                unset($driverOptions[$optionKey]);
+            }
+        }
+        $driverOptionLines = $this->generateDriverOptionLines($driverOptions);
+        [$tlsLines, $pdoOptionLines] = $this->generateTlsLines($transport);
         $databaseDefault = "'{$database}'";
         if ($rawDriver === 'sqlite' && !$this->isAbsolutePath($rawDatabase)) {
             $rel = ltrim($rawDatabase, "/\\");
@@ -122,20 +158,93 @@ class CreatesDatabaseConfig
     'database' => env('DB_DATABASE', {$databaseDefault}),
     'username' => env('DB_USERNAME', '{$username}'),
     'password' => env('DB_PASSWORD', '{$password}'),
-    'unix_socket' => env('DB_SOCKET', ''),
+    'unix_socket' => env('DB_SOCKET', '{$socket}'),
     'charset' => env('DB_CHARSET', '{$charset}'),
     'collation' => env('DB_COLLATION', '{$collation}'),
     'prefix' => env('DB_PREFIX', '{$prefix}'),
 {$foreignKeysLine}    'method' => env('DB_METHOD', '{$method}'),
     'strict' => env('DB_STRICT', false),
     'engine' => env('DB_ENGINE', '{$engine}'),
-    'options' => [
+{$driverOptionLines}{$tlsLines}    'options' => [
         PDO::ATTR_STRINGIFY_FETCHES => true,
-    ]
+{$pdoOptionLines}    ]
 ];
 PHP;
     }
 
+    /**
+     * Render driver options from --db-options (e.g. sslmode) as connection
+     * keys. Keys that the template already defines are skipped.
+     *
+     * @param array $options
+     * @return string
+     */
+    protected function generateDriverOptionLines(array $options): string
+    {
+        $reserved = [
+            'driver', 'host', 'port', 'database', 'username', 'password', 'unix_socket', 'charset',
+            'collation', 'prefix', 'foreign_key_constraints', 'method', 'strict', 'engine', 'options',
+        ];
+        $lines = '';
+        foreach ($options as $key => $value) {
+            if (!is_string($key) || !preg_match('/^[A-Za-z_][A-Za-z0-9_]*$/', $key) || in_array($key, $reserved, true)) {
+                continue;
+            }
+            $value = $this->escapePhpString((string) $value);
+            $lines .= "    '{$key}' => '{$value}',\n";
+        }
+        return $lines;
+    }
+
+    /**
+     * Render the resolved TLS settings as connection keys the Laravel
+     * connectors read (PostgreSQL sslmode/sslrootcert/sslcert/sslkey, SQL
+     * Server encrypt and trust_server_certificate) and as PDO options for
+     * MySQL.
+     *
+     * @param array{host: string, options: array, attributes: array} $transport
+     * @return array{0: string, 1: string} Connection key lines and PDO option lines
+     */
+    protected function generateTlsLines(array $transport): array
+    {
+        $keys = [
+            'sslmode' => 'sslmode',
+            'sslrootcert' => 'sslrootcert',
+            'sslcert' => 'sslcert',
+            'sslkey' => 'sslkey',
+            'Encrypt' => 'encrypt',
+            'TrustServerCertificate' => 'trust_server_certificate',
+        ];
+        $lines = '';
+        foreach ($transport['options'] as $option => $value) {
+            if (isset($keys[$option])) {
+                $lines .= "    '{$keys[$option]}' => '" . $this->escapePhpString((string) $value) . "',\n";
+            }
+        }
+        if ($transport['attributes'] === []) {
+            // The PDO::MYSQL_ATTR_* constants only exist with pdo_mysql.
+            return [$lines, ''];
+        }
+
+        $names = [
+            PDO::MYSQL_ATTR_SSL_CA => 'MYSQL_ATTR_SSL_CA',
+            PDO::MYSQL_ATTR_SSL_CAPATH => 'MYSQL_ATTR_SSL_CAPATH',
+            PDO::MYSQL_ATTR_SSL_CERT => 'MYSQL_ATTR_SSL_CERT',
+            PDO::MYSQL_ATTR_SSL_KEY => 'MYSQL_ATTR_SSL_KEY',
+            PDO::MYSQL_ATTR_SSL_VERIFY_SERVER_CERT => 'MYSQL_ATTR_SSL_VERIFY_SERVER_CERT',
+        ];
+        $pdoLines = '';
+        foreach ($transport['attributes'] as $attribute => $value) {
+            if (!isset($names[$attribute])) {
+                continue;
+            }
+            $value = is_bool($value) ? ($value ? 'true' : 'false') : "'" . $this->escapePhpString((string) $value) . "'";
+            $pdoLines .= "        PDO::{$names[$attribute]} => {$value},\n";
+        }
+
+        return [$lines, $pdoLines];
+    }
+
     protected function normalizeSqliteDatabasePath(string $path): string
     {
         $path = trim($path);
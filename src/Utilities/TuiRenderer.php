@@ -14,6 +14,7 @@ final class TuiRenderer
     private array $systemStatus = [];
     private ?string $activeInput = null;
     private bool $plainLogDirty = false;
+    private string $plainLogLevel = 'info';
 
     private bool $fixedRendered = false;
     private int $fixedLines = 0;
@@ -39,6 +40,7 @@ final class TuiRenderer
 
         $this->logs[] = $icon . ($type == 'ask' ? "<fg=cyan>{$message}</>" : $message);
         $this->plainLogDirty = true;
+        $this->setPlainLogLevel($type);
         $this->render(true);
     }
 
@@ -68,7 +70,7 @@ final class TuiRenderer
      * @param string $message Base message
      * @param int $current Current progress (bytes, items, etc.)
      * @param int $total Total size (bytes, items, etc.)
-     * @param string $unit Unit to display (e.g., 'MB', 'files')
+     * @param string $unit Unit to display (e.g., 'MB', 'files', 'bytes')
      */
     public function updateProgress(string $message, int $current, int $total, string $unit = 'MB'): void
     {
@@ -86,6 +88,10 @@ final class TuiRenderer
             // For files, just show numbers with "files" text
             $formattedCurrent = number_format($current) . ' files';
             $formattedTotal = number_format($total) . ' files';
+        } elseif ($unit === 'bytes') {
+            // Exact counts: the Go installer turns them into progress events.
+            $formattedCurrent = $current . ' bytes';
+            $formattedTotal = $total . ' bytes';
         } else {
             // For bytes/MB, use formatBytes
             $formattedCurrent = $this->formatBytes($current, $unit);
@@ -131,14 +137,16 @@ final class TuiRenderer
 
     /**
      * Replace last N log entries with a single new log entry.
+     * $type is the addLog() type of the new entry.
      */
-    public function replaceLastLogs(string $line, int $count = 1): void
+    public function replaceLastLogs(string $line, int $count = 1, string $type = 'info'): void
     {
         for ($i = 0; $i < $count && count($this->logs) > 0; $i++) {
             array_pop($this->logs);
         }
         $this->logs[] = $line;
         $this->plainLogDirty = true;
+        $this->setPlainLogLevel($type);
         $this->render(true);
     }
 
@@ -320,6 +328,19 @@ final class TuiRenderer
         return $this->output->isDecorated() && !getenv('CI');
     }
 
+    /**
+     * Remember the severity of the pending plain output line.
+     */
+    private function setPlainLogLevel(string $type): void
+    {
+        $this->plainLogLevel = match ($type) {
+            'error'   => 'error',
+            'warning' => 'warn',
+            'debug'   => 'debug',
+            default   => 'info',
+        };
+    }
+
     private function renderPlain(): void
     {
         if (!$this->plainLogDirty) {
@@ -327,8 +348,15 @@ final class TuiRenderer
         }
         $this->plainLogDirty = false;
 
+        $level = $this->plainLogLevel;
+        $this->plainLogLevel = 'info';
         if ($log = end($this->logs)) {
-            $this->output->writeln(strip_tags($log));
+            $line = strip_tags($log);
+            // The Go installer asks for the severity of each line instead of guessing it from the text.
+            if (getenv('EVO_LOG_SEVERITY')) {
+                $line = "[evo:{$level}] {$line}";
+            }
+            $this->output->writeln($line);
         }
     }
     